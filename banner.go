@@ -1,8 +1,17 @@
 package zendia
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"runtime"
+	"sort"
 	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
 )
 
 // BannerConfig configuração do banner
@@ -11,13 +20,83 @@ type BannerConfig struct {
 	Version    string
 	Port       string
 	ShowRoutes bool
+	Disable    bool      // Não exibe o banner
+	NoColor    bool      // Remove as cores ANSI (automático quando a saída não é um terminal)
+	Output     io.Writer // Destino do banner (padrão: os.Stdout)
+}
+
+// StartupInfo resumo da inicialização para logar como JSON no boot
+// É a mesma informação exibida pelo banner, em formato legível por ferramentas de deploy
+type StartupInfo struct {
+	AppName    string    `json:"app_name"`
+	Version    string    `json:"version"`
+	GitCommit  string    `json:"git_commit,omitempty"`
+	Port       string    `json:"port"`
+	GoVersion  string    `json:"go_version"`
+	RouteCount int       `json:"route_count"`
+	Features   []string  `json:"features"`
+	StartedAt  time.Time `json:"started_at"`
+}
+
+// StartupInfo monta o resumo da inicialização com os dados do config e da instância
+// Versão vazia usa BuildVersion; Features lista, em ordem alfabética, os recursos
+// habilitados (tenant, firebase_auth, monitoring, metrics_persistence, health...)
+//
+// Uso:
+//
+//	slog.Info("startup", "info", app.StartupInfo(bannerConfig))
+func (z *Zendia) StartupInfo(config BannerConfig) StartupInfo {
+	version := config.Version
+	if version == "" {
+		version = BuildVersion
+	}
+
+	features := make([]string, 0, len(z.features)+2)
+	if z.tenantExtractor != nil {
+		features = append(features, FeatureTenant)
+	}
+	if z.firebaseAuthConfig != nil {
+		features = append(features, FeatureFirebaseAuth)
+	}
+	for feature, enabled := range z.features {
+		if enabled {
+			features = append(features, feature)
+		}
+	}
+	sort.Strings(features)
+
+	return StartupInfo{
+		AppName:    config.AppName,
+		Version:    version,
+		GitCommit:  BuildCommit,
+		Port:       config.Port,
+		GoVersion:  runtime.Version(),
+		RouteCount: len(z.engine.Routes()),
+		Features:   features,
+		StartedAt:  z.startTime,
+	}
 }
 
+// ansiEscapeRegex casa sequências de cor ANSI
+var ansiEscapeRegex = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
 // ShowBanner exibe o banner do framework
 func (z *Zendia) ShowBanner(config BannerConfig) {
+	if config.Disable {
+		return
+	}
+
+	output := config.Output
+	if output == nil {
+		output = os.Stdout
+	}
+
+	info := z.StartupInfo(config)
+	var buf bytes.Buffer
+
 	// ASCII Art do ZendiaFramework
-	fmt.Println("\033[36m") // Cor ciano
-	fmt.Println(`
+	fmt.Fprintln(&buf, "\033[36m") // Cor ciano
+	fmt.Fprintln(&buf, `
                                    █████ ███           
                                   ░░███ ░░░            
  █████████  ██████  ████████    ███████ ████   ██████  
@@ -26,30 +105,49 @@ func (z *Zendia) ShowBanner(config BannerConfig) {
   ███░   █░███░░░   ░███ ░███ ░███ ░███ ░███  ███░░███ 
  █████████░░██████  ████ █████░░█████████████░░████████
 ░░░░░░░░░  ░░░░░░  ░░░░ ░░░░░  ░░░░░░░░░░░░░  ░░░░░░░░ `)
-	fmt.Println("\033[0m") // Reset cor
+	fmt.Fprintln(&buf, "\033[0m") // Reset cor
 
 	// Informações do framework
-	fmt.Println("\033[1;32m🚀 ZendiaFramework - Framework Go Multi-Tenant para APIs\033[0m")
-	fmt.Println("\033[90m   Feito com ❤️  para a comunidade Go brasileira\033[0m")
-	fmt.Println()
+	fmt.Fprintln(&buf, "\033[1;32m🚀 ZendiaFramework - Framework Go Multi-Tenant para APIs\033[0m")
+	fmt.Fprintln(&buf, "\033[90m   Feito com ❤️  para a comunidade Go brasileira\033[0m")
+	fmt.Fprintln(&buf)
 
 	// Informações da aplicação
-	fmt.Printf("\033[1;34m📦 Aplicação:\033[0m %s\n", config.AppName)
-	fmt.Printf("\033[1;35m🔢 Versão:\033[0m    %s\n", config.Version)
-	fmt.Printf("\033[1;33m🌐 Porta:\033[0m     %s\n", config.Port)
-	fmt.Printf("\033[1;36m🔗 URL:\033[0m       http://localhost%s\n", config.Port)
-	fmt.Println()
+	fmt.Fprintf(&buf, "\033[1;34m📦 Aplicação:\033[0m %s\n", info.AppName)
+	fmt.Fprintf(&buf, "\033[1;35m🔢 Versão:\033[0m    %s\n", info.Version)
+	fmt.Fprintf(&buf, "\033[1;33m🌐 Porta:\033[0m     %s\n", info.Port)
+	fmt.Fprintf(&buf, "\033[1;36m🔗 URL:\033[0m       http://localhost%s\n", info.Port)
+	if len(info.Features) > 0 {
+		fmt.Fprintf(&buf, "\033[1;32m🧩 Recursos:\033[0m  %s\n", strings.Join(info.Features, ", "))
+	}
+	fmt.Fprintln(&buf)
 
 	if config.ShowRoutes {
 		routes := z.engine.Routes()
-		fmt.Printf("\033[1;36m📋 Rotas registradas (%d):\033[0m\n", len(routes))
+		fmt.Fprintf(&buf, "\033[1;36m📋 Rotas registradas (%d):\033[0m\n", info.RouteCount)
 		for _, r := range routes {
-			fmt.Printf("   \033[1;33m%-7s\033[0m %s\n", r.Method, r.Path)
+			fmt.Fprintf(&buf, "   \033[1;33m%-7s\033[0m %s\n", r.Method, r.Path)
 		}
-		fmt.Println()
+		fmt.Fprintln(&buf)
 	}
 
-	fmt.Println("\033[1;32m🎯 Pronto para servir requisições!\033[0m")
-	fmt.Println("\033[90m" + strings.Repeat("─", 60) + "\033[0m")
-	fmt.Println()
+	fmt.Fprintln(&buf, "\033[1;32m🎯 Pronto para servir requisições!\033[0m")
+	fmt.Fprintln(&buf, "\033[90m"+strings.Repeat("─", 60)+"\033[0m")
+	fmt.Fprintln(&buf)
+
+	banner := buf.Bytes()
+	if config.NoColor || !isTerminal(output) {
+		banner = ansiEscapeRegex.ReplaceAll(banner, nil)
+	}
+
+	output.Write(banner)
+}
+
+// isTerminal verifica se o writer é um terminal (TTY)
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
 }
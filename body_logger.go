@@ -0,0 +1,162 @@
+package zendia
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// redactedValue valor que substitui campos sensíveis no log
+const redactedValue = "[REDACTED]"
+
+// DefaultBodyLogRedactKeys campos mascarados quando BodyLoggerConfig.RedactKeys é vazio
+var DefaultBodyLogRedactKeys = []string{"password", "token", "secret"}
+
+// BodyLoggerConfig configuração do BodyLogger
+type BodyLoggerConfig struct {
+	MaxBodySize int          // Bytes capturados de cada body (padrão: DefaultBodyLogMaxSize)
+	RedactKeys  []string     // Campos JSON mascarados; casa por substring sem diferenciar maiúsculas (padrão: DefaultBodyLogRedactKeys)
+	Logger      *slog.Logger // Destino do log (padrão: slog.Default())
+}
+
+// BodyLogger middleware de DEBUG que loga os bodies de requisição e resposta
+// Só bodies JSON são logados, com os campos de RedactKeys mascarados em qualquer nível
+// ("password", "access_token", "client_secret"...) e strings passadas por sanitizeLogValue.
+// Bodies não JSON ou maiores que MaxBodySize aparecem apenas com o tamanho, pois não dá
+// para mascará-los com segurança. O handler continua recebendo o body completo.
+//
+// Não habilite em produção sem revisar RedactKeys: o log recebe dados do usuário.
+//
+//	app.Use(zendia.BodyLogger(zendia.BodyLoggerConfig{
+//	    RedactKeys: []string{"password", "token", "secret", "cpf"},
+//	}))
+func BodyLogger(config BodyLoggerConfig) gin.HandlerFunc {
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = DefaultBodyLogMaxSize
+	}
+	if len(config.RedactKeys) == 0 {
+		config.RedactKeys = DefaultBodyLogRedactKeys
+	}
+	redact := make([]string, len(config.RedactKeys))
+	for i, key := range config.RedactKeys {
+		redact[i] = strings.ToLower(key)
+	}
+
+	return func(c *gin.Context) {
+		var request []byte
+		if c.Request.Body != nil {
+			// Lê um byte além do limite para saber se o body foi truncado
+			request, _ = io.ReadAll(io.LimitReader(c.Request.Body, int64(config.MaxBodySize)+1))
+			c.Request.Body = readCloser{io.MultiReader(bytes.NewReader(request), c.Request.Body), c.Request.Body}
+		}
+
+		writer := &bodyLogWriter{ResponseWriter: c.Writer, limit: config.MaxBodySize}
+		c.Writer = writer
+
+		c.Next()
+
+		logger := config.Logger
+		if logger == nil {
+			logger = slog.Default()
+		}
+		logger.Info("http body",
+			"method", c.Request.Method,
+			"path", sanitizeLogValue(c.Request.URL.Path),
+			"status", c.Writer.Status(),
+			"request", redactBody(request, len(request), config.MaxBodySize, redact),
+			"response", redactBody(writer.body.Bytes(), writer.size, config.MaxBodySize, redact),
+		)
+	}
+}
+
+// readCloser lê do body reconstituído e fecha o body original
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// bodyLogWriter repassa a resposta ao cliente guardando até limit bytes do body
+type bodyLogWriter struct {
+	gin.ResponseWriter
+	body  bytes.Buffer
+	limit int
+	size  int
+}
+
+func (w *bodyLogWriter) Write(data []byte) (int, error) {
+	if remaining := w.limit + 1 - w.body.Len(); remaining > 0 {
+		if len(data) < remaining {
+			remaining = len(data)
+		}
+		w.body.Write(data[:remaining])
+	}
+	w.size += len(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bodyLogWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// redactBody retorna o body JSON mascarado, ou só o tamanho quando não dá para mascarar
+func redactBody(body []byte, size, limit int, redact []string) string {
+	if size == 0 {
+		return ""
+	}
+	if size > limit {
+		return "[truncated: " + strconv.Itoa(size) + "+ bytes]"
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return "[non-JSON: " + strconv.Itoa(size) + " bytes]"
+	}
+
+	out, err := json.Marshal(redactValue(generic, redact))
+	if err != nil {
+		return "[non-JSON: " + strconv.Itoa(size) + " bytes]"
+	}
+	return string(out)
+}
+
+// redactValue mascara os campos sensíveis e sanitiza as strings, percorrendo objetos e arrays
+func redactValue(value interface{}, redact []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, nested := range v {
+			if sensitiveKey(key, redact) {
+				out[sanitizeLogValue(key)] = redactedValue
+				continue
+			}
+			out[sanitizeLogValue(key)] = redactValue(nested, redact)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = redactValue(item, redact)
+		}
+		return out
+	case string:
+		return sanitizeLogValue(v)
+	}
+	return value
+}
+
+func sensitiveKey(key string, redact []string) bool {
+	key = strings.ToLower(key)
+	for _, r := range redact {
+		if strings.Contains(key, r) {
+			return true
+		}
+	}
+	return false
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"sync"
 	"time"
 
@@ -27,8 +29,9 @@ type CacheConfig struct {
 // MemoryCacheConfig configuração específica do cache em memória
 type MemoryCacheConfig struct {
 	CacheConfig
-	MaxSize   int
-	MaxMemory int64
+	MaxSize         int
+	MaxMemory       int64
+	CleanupInterval time.Duration // Intervalo da limpeza automática (padrão: 5 minutos)
 }
 
 type cacheItem struct {
@@ -37,11 +40,16 @@ type cacheItem struct {
 }
 
 // MemoryCache implementação de cache em memória
+// A limpeza roda numa goroutine própria até Close ser chamado
 type MemoryCache struct {
-	config MemoryCacheConfig
-	items  sync.Map
-	size   int64
-	mutex  sync.RWMutex
+	config    MemoryCacheConfig
+	items     sync.Map
+	size      int64
+	count     int
+	mutex     sync.RWMutex
+	stop      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
 }
 
 // NewMemoryCache cria um novo cache em memória
@@ -58,8 +66,15 @@ func NewMemoryCache(config MemoryCacheConfig) *MemoryCache {
 	if config.KeyPrefix == "" {
 		config.KeyPrefix = "zendia:"
 	}
+	if config.CleanupInterval <= 0 {
+		config.CleanupInterval = DefaultMemoryCacheCleanupInterval
+	}
 
-	cache := &MemoryCache{config: config}
+	cache := &MemoryCache{
+		config:  config,
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
 	go cache.cleanup()
 	return cache
 }
@@ -71,7 +86,9 @@ func (mc *MemoryCache) Get(ctx context.Context, key string) ([]byte, bool) {
 		if time.Now().Before(ci.expiresAt) {
 			return ci.data, true
 		}
-		mc.items.Delete(fullKey)
+		mc.mutex.Lock()
+		mc.removeLocked(fullKey, ci)
+		mc.mutex.Unlock()
 	}
 	return nil, false
 }
@@ -90,21 +107,26 @@ func (mc *MemoryCache) Set(ctx context.Context, key string, value []byte, ttl ti
 	mc.mutex.Lock()
 	defer mc.mutex.Unlock()
 
+	if old, ok := mc.items.Load(fullKey); ok {
+		mc.removeLocked(fullKey, old.(*cacheItem))
+	}
+
 	if mc.size+int64(len(value)) > mc.config.MaxMemory {
 		mc.evictOldest()
 	}
 
 	mc.items.Store(fullKey, item)
 	mc.size += int64(len(value))
+	mc.count++
 	return nil
 }
 
 func (mc *MemoryCache) Delete(ctx context.Context, key string) error {
 	fullKey := mc.config.KeyPrefix + key
-	if item, ok := mc.items.LoadAndDelete(fullKey); ok {
-		mc.mutex.Lock()
-		mc.size -= int64(len(item.(*cacheItem).data))
-		mc.mutex.Unlock()
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	if item, ok := mc.items.Load(fullKey); ok {
+		mc.removeLocked(fullKey, item.(*cacheItem))
 	}
 	return nil
 }
@@ -114,25 +136,81 @@ func (mc *MemoryCache) Clear(ctx context.Context) error {
 	defer mc.mutex.Unlock()
 	mc.items = sync.Map{}
 	mc.size = 0
+	mc.count = 0
 	return nil
 }
 
+// Close para a goroutine de limpeza; o cache continua utilizável, só sem limpeza automática
+func (mc *MemoryCache) Close() error {
+	mc.closeOnce.Do(func() {
+		close(mc.stop)
+	})
+	<-mc.stopped
+	return nil
+}
+
+// CleanupNow remove os itens expirados e aplica o MaxSize imediatamente
+func (mc *MemoryCache) CleanupNow() {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	now := time.Now()
+	type entry struct {
+		key  interface{}
+		item *cacheItem
+	}
+	var alive []entry
+
+	mc.items.Range(func(key, value interface{}) bool {
+		item := value.(*cacheItem)
+		if now.After(item.expiresAt) {
+			mc.removeLocked(key, item)
+		} else {
+			alive = append(alive, entry{key, item})
+		}
+		return true
+	})
+
+	// Acima do MaxSize remove os que expiram primeiro
+	if excess := len(alive) - mc.config.MaxSize; excess > 0 {
+		sort.Slice(alive, func(i, j int) bool {
+			return alive[i].item.expiresAt.Before(alive[j].item.expiresAt)
+		})
+		for _, e := range alive[:excess] {
+			mc.removeLocked(e.key, e.item)
+		}
+	}
+}
+
+// Len quantidade de itens armazenados (inclui expirados ainda não limpos)
+func (mc *MemoryCache) Len() int {
+	mc.mutex.RLock()
+	defer mc.mutex.RUnlock()
+	return mc.count
+}
+
 func (mc *MemoryCache) cleanup() {
-	ticker := time.NewTicker(5 * time.Minute)
+	defer close(mc.stopped)
+
+	ticker := time.NewTicker(mc.config.CleanupInterval)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		now := time.Now()
-		mc.items.Range(func(key, value interface{}) bool {
-			item := value.(*cacheItem)
-			if now.After(item.expiresAt) {
-				mc.items.Delete(key)
-				mc.mutex.Lock()
-				mc.size -= int64(len(item.data))
-				mc.mutex.Unlock()
-			}
-			return true
-		})
+	for {
+		select {
+		case <-mc.stop:
+			return
+		case <-ticker.C:
+			mc.CleanupNow()
+		}
+	}
+}
+
+// removeLocked remove o item se ainda for o armazenado na chave, ajustando os contadores
+// Deve ser chamado com mutex adquirido
+func (mc *MemoryCache) removeLocked(key interface{}, item *cacheItem) {
+	if mc.items.CompareAndDelete(key, item) {
+		mc.size -= int64(len(item.data))
+		mc.count--
 	}
 }
 
@@ -141,20 +219,26 @@ func (mc *MemoryCache) evictOldest() {
 	mc.items.Range(func(key, value interface{}) bool {
 		item := value.(*cacheItem)
 		if now.After(item.expiresAt) {
-			mc.items.Delete(key)
-			mc.size -= int64(len(item.data))
+			mc.removeLocked(key, item)
 			return false
 		}
 		return true
 	})
 }
 
+// CacheMetricsSink recebe os hits e misses do CachedRepository por tipo de entidade
+type CacheMetricsSink interface {
+	RecordCacheHit(typeName string)
+	RecordCacheMiss(typeName string)
+}
+
 // CachedRepository wrapper que adiciona cache ao Repository
 type CachedRepository[T MongoAuditableEntity] struct {
 	base     *Repository[T]
 	cache    CacheProvider
 	config   CacheConfig
 	typeName string
+	sinks    []CacheMetricsSink
 }
 
 // NewCachedRepository cria um repository com cache
@@ -174,6 +258,27 @@ func NewCachedRepository[T MongoAuditableEntity](base *Repository[T], cache Cach
 	}
 }
 
+// AddMetricsSink envia cada hit e miss do cache, com o typeName do repository, para um sink (ex: *Metrics)
+// Configure antes de usar o repository:
+//
+//	metrics := app.AddMonitoring()
+//	userRepo := zendia.NewCachedRepository(base, cache, zendia.CacheConfig{}, "User")
+//	userRepo.AddMetricsSink(metrics) // GET /metrics → "cache": {"User": {"hit_rate": 95, ...}}
+func (cr *CachedRepository[T]) AddMetricsSink(sink CacheMetricsSink) {
+	cr.sinks = append(cr.sinks, sink)
+}
+
+// recordLookup repassa o resultado de uma consulta ao cache para os sinks
+func (cr *CachedRepository[T]) recordLookup(hit bool) {
+	for _, sink := range cr.sinks {
+		if hit {
+			sink.RecordCacheHit(cr.typeName)
+		} else {
+			sink.RecordCacheMiss(cr.typeName)
+		}
+	}
+}
+
 func (cr *CachedRepository[T]) makeKey(operation string, id uuid.UUID) string {
 	return fmt.Sprintf("%s:%s:%v", cr.typeName, operation, id)
 }
@@ -182,15 +287,35 @@ func (cr *CachedRepository[T]) makeTenantKey(operation string, tenantID string)
 	return fmt.Sprintf("%s:%s:tenant:%s", cr.typeName, operation, tenantID)
 }
 
+// listKey chave da listagem do tenant; com WithIsolationKeys inclui os valores de cada chave
+// para não compartilhar a lista entre, por exemplo, organizações do mesmo tenant
+func (cr *CachedRepository[T]) listKey(ctx context.Context) (string, bool) {
+	tenantID := GetTenantID(ctx)
+	if tenantID == "" {
+		return "", false
+	}
+	key := cr.makeTenantKey("list", tenantID)
+
+	keys := cr.base.config.isolationKeys
+	values, err := isolationValues(ctx, keys)
+	if err != nil {
+		return "", false
+	}
+	for i, k := range keys {
+		key += fmt.Sprintf(":%s:%s", k, values[i])
+	}
+	return key, true
+}
+
 func (cr *CachedRepository[T]) Create(ctx context.Context, entity T) (T, error) {
 	result, err := cr.base.Create(ctx, entity)
 	if err != nil {
 		return result, err
 	}
 
-	tenantInfo := GetTenantInfo(ctx)
-	if tenantInfo.TenantID != "" {
-		cr.cache.Delete(ctx, cr.makeTenantKey("list", tenantInfo.TenantID))
+	if key, ok := cr.listKey(ctx); ok {
+		cr.cache.Delete(ctx, key)
+		cr.cache.Delete(ctx, listVersionKey(key))
 	}
 
 	return result, nil
@@ -203,14 +328,22 @@ func (cr *CachedRepository[T]) GetByID(ctx context.Context, id uuid.UUID) (T, er
 	if data, found := cr.cache.Get(ctx, key); found {
 		var result T
 		if err := json.Unmarshal(data, &result); err == nil {
+			cr.recordLookup(true)
+			if err := cr.checkAccess(ctx, result); err != nil {
+				return zero, err
+			}
 			return result, nil
 		}
 	}
+	cr.recordLookup(false)
 
 	result, err := cr.base.GetByID(ctx, id)
 	if err != nil {
 		return zero, err
 	}
+	if err := cr.checkAccess(ctx, result); err != nil {
+		return zero, err
+	}
 
 	if data, err := json.Marshal(result); err == nil {
 		cr.cache.Set(ctx, key, data, cr.config.TTL)
@@ -219,6 +352,71 @@ func (cr *CachedRepository[T]) GetByID(ctx context.Context, id uuid.UUID) (T, er
 	return result, nil
 }
 
+// GetByIDs resolve os IDs pelo cache e busca só os ausentes, numa única query ao base
+// Entidades buscadas populam o cache; o resultado segue a ordem de ids, sem os não encontrados
+func (cr *CachedRepository[T]) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]T, error) {
+	found := make(map[uuid.UUID]T, len(ids))
+	seen := make(map[uuid.UUID]bool, len(ids))
+	var misses []uuid.UUID
+
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if data, ok := cr.cache.Get(ctx, cr.makeKey("get", id)); ok {
+			var cached T
+			if err := json.Unmarshal(data, &cached); err == nil {
+				cr.recordLookup(true)
+				// Entidade de outro tenant: o base também não a retornaria
+				if cr.checkAccess(ctx, cached) == nil {
+					found[id] = cached
+				}
+				continue
+			}
+		}
+		cr.recordLookup(false)
+		misses = append(misses, id)
+	}
+
+	if len(misses) > 0 {
+		fetched, err := cr.base.GetByIDs(ctx, misses)
+		if err != nil {
+			return nil, err
+		}
+		for _, entity := range fetched {
+			if cr.checkAccess(ctx, entity) != nil {
+				continue
+			}
+			found[entity.GetID()] = entity
+			if data, err := json.Marshal(entity); err == nil {
+				cr.cache.Set(ctx, cr.makeKey("get", entity.GetID()), data, cr.config.TTL)
+			}
+		}
+	}
+
+	result := make([]T, 0, len(found))
+	for _, id := range ids {
+		if entity, ok := found[id]; ok {
+			result = append(result, entity)
+			delete(found, id)
+		}
+	}
+	return result, nil
+}
+
+// checkAccess confere tenant e chaves de isolamento: a chave do cache por ID não inclui o tenant
+func (cr *CachedRepository[T]) checkAccess(ctx context.Context, entity T) error {
+	if !cr.base.config.audit {
+		return nil
+	}
+	if err := checkEntityTenant(ctx, entity); err != nil {
+		return err
+	}
+	return checkEntityIsolation(ctx, entity, cr.base.config.isolationKeys)
+}
+
 func (cr *CachedRepository[T]) GetFirst(ctx context.Context, filters map[string]interface{}) (T, error) {
 	return cr.base.GetFirst(ctx, filters)
 }
@@ -229,13 +427,7 @@ func (cr *CachedRepository[T]) Update(ctx context.Context, id uuid.UUID, entity
 		return result, err
 	}
 
-	cr.cache.Delete(ctx, cr.makeKey("get", id))
-
-	tenantInfo := GetTenantInfo(ctx)
-	if tenantInfo.TenantID != "" {
-		cr.cache.Delete(ctx, cr.makeTenantKey("list", tenantInfo.TenantID))
-	}
-
+	cr.invalidate(ctx, id)
 	return result, nil
 }
 
@@ -245,30 +437,57 @@ func (cr *CachedRepository[T]) Delete(ctx context.Context, id uuid.UUID) error {
 		return err
 	}
 
-	cr.cache.Delete(ctx, cr.makeKey("get", id))
+	cr.invalidate(ctx, id)
+	return nil
+}
 
-	tenantInfo := GetTenantInfo(ctx)
-	if tenantInfo.TenantID != "" {
-		cr.cache.Delete(ctx, cr.makeTenantKey("list", tenantInfo.TenantID))
+// HardDelete remove permanentemente e invalida a entidade e a listagem do tenant
+func (cr *CachedRepository[T]) HardDelete(ctx context.Context, id uuid.UUID) error {
+	if err := cr.base.HardDelete(ctx, id); err != nil {
+		return err
 	}
 
+	cr.invalidate(ctx, id)
 	return nil
 }
 
+// Restore restaura um soft delete e invalida a listagem do tenant, que não incluía a entidade
+func (cr *CachedRepository[T]) Restore(ctx context.Context, id uuid.UUID) error {
+	if err := cr.base.Restore(ctx, id); err != nil {
+		return err
+	}
+
+	cr.invalidate(ctx, id)
+	return nil
+}
+
+// invalidate remove do cache a entidade e a listagem do tenant após uma escrita
+func (cr *CachedRepository[T]) invalidate(ctx context.Context, id uuid.UUID) {
+	cr.cache.Delete(ctx, cr.makeKey("get", id))
+
+	if key, ok := cr.listKey(ctx); ok {
+		cr.cache.Delete(ctx, key)
+		cr.cache.Delete(ctx, listVersionKey(key))
+	}
+}
+
+// GetAll usa o cache só para a listagem completa do tenant
+// Com filtros ou QueryOptions (projeção, limite, ordem) a consulta vai direto ao base:
+// um resultado parcial guardado na chave da listagem seria servido a chamadas sem opts
 func (cr *CachedRepository[T]) GetAll(ctx context.Context, filters map[string]interface{}, opts ...*QueryOptions) ([]T, error) {
-	tenantInfo := GetTenantInfo(ctx)
-	if tenantInfo.TenantID == "" || len(filters) > 0 {
+	key, ok := cr.listKey(ctx)
+	if !ok || len(filters) > 0 || hasQueryOptions(opts) {
 		return cr.base.GetAll(ctx, filters, opts...)
 	}
 
-	key := cr.makeTenantKey("list", tenantInfo.TenantID)
-
 	if data, found := cr.cache.Get(ctx, key); found {
 		var result []T
 		if err := json.Unmarshal(data, &result); err == nil {
+			cr.recordLookup(true)
 			return result, nil
 		}
 	}
+	cr.recordLookup(false)
 
 	result, err := cr.base.GetAll(ctx, filters, opts...)
 	if err != nil {
@@ -277,11 +496,23 @@ func (cr *CachedRepository[T]) GetAll(ctx context.Context, filters map[string]in
 
 	if data, err := json.Marshal(result); err == nil {
 		cr.cache.Set(ctx, key, data, cr.config.TTL)
+		// Versão da listagem para o ETag (ListETag) sem precisar ler a lista
+		cr.cache.Set(ctx, listVersionKey(key), []byte(strconv.FormatInt(time.Now().UnixNano(), 36)), cr.config.TTL)
 	}
 
 	return result, nil
 }
 
+// hasQueryOptions indica se algum dos opts foi informado
+func hasQueryOptions(opts []*QueryOptions) bool {
+	for _, opt := range opts {
+		if opt != nil {
+			return true
+		}
+	}
+	return false
+}
+
 func (cr *CachedRepository[T]) GetAllSkipTake(ctx context.Context, filters map[string]interface{}, pagination Pagination, opts ...*QueryOptions) ([]T, int64, error) {
 	return cr.base.GetAllSkipTake(ctx, filters, pagination, opts...)
 }
@@ -81,3 +81,50 @@ func TestMemoryCache_Clear(t *testing.T) {
 		t.Fatal("Should not find any keys after clear")
 	}
 }
+
+func TestMemoryCache_CleanupAndClose(t *testing.T) {
+	cache := NewMemoryCache(MemoryCacheConfig{
+		CacheConfig:     CacheConfig{TTL: 5 * time.Minute},
+		MaxSize:         2,
+		CleanupInterval: 10 * time.Millisecond,
+	})
+
+	ctx := context.Background()
+	cache.Set(ctx, "expired", []byte("x"), time.Millisecond)
+	cache.Set(ctx, "soon", []byte("y"), time.Minute)
+	cache.Set(ctx, "later1", []byte("z"), time.Hour)
+	cache.Set(ctx, "later2", []byte("w"), time.Hour)
+
+	// A goroutine de limpeza remove o expirado e aplica o MaxSize
+	deadline := time.Now().Add(time.Second)
+	for cache.Len() > 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if cache.Len() != 2 {
+		t.Fatalf("Expected 2 items after cleanup, got %d", cache.Len())
+	}
+	if _, found := cache.Get(ctx, "soon"); found {
+		t.Fatal("Item closest to expiry should be evicted above MaxSize")
+	}
+
+	if err := cache.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	select {
+	case <-cache.stopped:
+	default:
+		t.Fatal("Cleanup goroutine still running after Close")
+	}
+	cache.Close() // idempotente
+
+	// Depois do Close só CleanupNow limpa
+	cache.Set(ctx, "short", []byte("s"), time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	if cache.Len() != 3 {
+		t.Fatalf("Expected no automatic cleanup after Close, got %d items", cache.Len())
+	}
+	cache.CleanupNow()
+	if cache.Len() != 2 {
+		t.Fatalf("Expected CleanupNow to reclaim expired item, got %d items", cache.Len())
+	}
+}
@@ -0,0 +1,130 @@
+package zendia
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen retornado por CircuitBreaker.Execute enquanto o circuito está aberto
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState estado do CircuitBreaker
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"    // Chamadas passam normalmente
+	CircuitOpen     CircuitState = "open"      // Chamadas recusadas até o cooldown passar
+	CircuitHalfOpen CircuitState = "half_open" // Uma chamada de teste decide se fecha ou reabre
+)
+
+// CircuitBreaker evita chamar repetidamente uma dependência fora do ar
+// Depois de threshold falhas seguidas o circuito abre e recusa chamadas por cooldown;
+// passado o cooldown, uma única chamada de teste é liberada: sucesso fecha o circuito,
+// falha reabre por mais um cooldown. Seguro para uso concorrente.
+//
+// Uso:
+//
+//	breaker := zendia.NewCircuitBreaker(5, 30*time.Second)
+//	err := breaker.Execute(func() error { return paymentClient.Charge(ctx, order) })
+//	if errors.Is(err, zendia.ErrCircuitOpen) { ... }
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	state     CircuitState
+	failures  int
+	openedAt  time.Time
+	probing   bool
+	now       func() time.Time
+}
+
+// NewCircuitBreaker cria o breaker fechado
+// threshold <= 0 usa DefaultCircuitBreakerThreshold; cooldown <= 0 usa DefaultCircuitBreakerCooldown
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold <= 0 {
+		threshold = DefaultCircuitBreakerThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultCircuitBreakerCooldown
+	}
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		state:     CircuitClosed,
+		now:       time.Now,
+	}
+}
+
+// Allow indica se a chamada pode ser feita; depois dela informe Success ou Failure
+// Com o cooldown vencido, a primeira chamada passa como teste (half-open) e as demais esperam
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = CircuitHalfOpen
+		b.probing = true
+		return true
+	case CircuitHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	}
+	return true
+}
+
+// Success registra uma chamada bem-sucedida e fecha o circuito
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = CircuitClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// Failure registra uma falha; abre o circuito no threshold ou se a chamada de teste falhar
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.probing = false
+	if b.state == CircuitHalfOpen || b.failures >= b.threshold {
+		b.state = CircuitOpen
+		b.openedAt = b.now()
+	}
+}
+
+// Execute chama fn se o circuito permitir, registrando o resultado
+// Retorna ErrCircuitOpen sem chamar fn enquanto o circuito estiver aberto
+func (b *CircuitBreaker) Execute(fn func() error) error {
+	if !b.Allow() {
+		return ErrCircuitOpen
+	}
+	if err := fn(); err != nil {
+		b.Failure()
+		return err
+	}
+	b.Success()
+	return nil
+}
+
+// State estado atual do circuito
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Failures falhas seguidas desde o último sucesso
+func (b *CircuitBreaker) Failures() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.failures
+}
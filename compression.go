@@ -0,0 +1,226 @@
+package zendia
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CompressionEncoder cria um writer que comprime os dados escritos em w
+type CompressionEncoder func(w io.Writer) (io.WriteCloser, error)
+
+// CompressionConfig configuração do middleware de compressão
+type CompressionConfig struct {
+	Level    int                           // Nível de compressão para gzip/deflate (0 = padrão)
+	Encoders map[string]CompressionEncoder // Encoders extras por encoding (ex: "br")
+}
+
+// compressionPreference ordem de preferência do servidor em caso de empate de q-value
+var compressionPreference = []string{"br", "gzip", "deflate"}
+
+// Compression middleware que comprime respostas conforme o Accept-Encoding
+//
+// A escolha respeita os q-values enviados pelo cliente, o curinga "*" e o
+// "identity". gzip e deflate vêm registrados; brotli pode ser habilitado
+// registrando um encoder em Encoders["br"].
+func Compression(config ...CompressionConfig) gin.HandlerFunc {
+	cfg := CompressionConfig{}
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.Level == 0 {
+		cfg.Level = gzip.DefaultCompression
+	}
+
+	encoders := map[string]CompressionEncoder{
+		"gzip": func(w io.Writer) (io.WriteCloser, error) {
+			return gzip.NewWriterLevel(w, cfg.Level)
+		},
+		"deflate": func(w io.Writer) (io.WriteCloser, error) {
+			return flate.NewWriter(w, cfg.Level)
+		},
+	}
+	for name, encoder := range cfg.Encoders {
+		encoders[strings.ToLower(name)] = encoder
+	}
+
+	available := compressionOrder(encoders)
+
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"), available)
+		if encoding == "" || c.Request.Method == "HEAD" || c.GetHeader("Upgrade") != "" {
+			c.Next()
+			return
+		}
+
+		cw := &compressWriter{
+			ResponseWriter: c.Writer,
+			encoding:       encoding,
+			encoder:        encoders[encoding],
+		}
+		c.Writer = cw
+		defer cw.close()
+
+		c.Next()
+	}
+}
+
+// compressionOrder retorna os encodings disponíveis na ordem de preferência do servidor
+func compressionOrder(encoders map[string]CompressionEncoder) []string {
+	order := make([]string, 0, len(encoders))
+	for _, name := range compressionPreference {
+		if _, ok := encoders[name]; ok {
+			order = append(order, name)
+		}
+	}
+
+	var extras []string
+	for name := range encoders {
+		known := false
+		for _, preferred := range compressionPreference {
+			if name == preferred {
+				known = true
+				break
+			}
+		}
+		if !known {
+			extras = append(extras, name)
+		}
+	}
+	sort.Strings(extras)
+
+	return append(order, extras...)
+}
+
+// negotiateEncoding escolhe o encoding a partir do header Accept-Encoding
+// Retorna "" quando a resposta deve seguir sem compressão (identity)
+func negotiateEncoding(header string, available []string) string {
+	if strings.TrimSpace(header) == "" {
+		return ""
+	}
+
+	qualities := parseAcceptEncoding(header)
+	starQ, hasStar := qualities["*"]
+
+	best := ""
+	bestQ := 0.0
+	for _, encoding := range available {
+		q, ok := qualities[encoding]
+		if !ok {
+			if !hasStar {
+				continue
+			}
+			q = starQ
+		}
+		// Empate mantém o primeiro, que é o preferido pelo servidor
+		if q > bestQ {
+			best = encoding
+			bestQ = q
+		}
+	}
+
+	if best == "" {
+		return ""
+	}
+
+	// identity só vence se o cliente o listar explicitamente com q maior
+	if identityQ, ok := qualities["identity"]; ok && identityQ > bestQ {
+		return ""
+	}
+
+	return best
+}
+
+// parseAcceptEncoding converte o header em um mapa encoding → q-value
+func parseAcceptEncoding(header string) map[string]float64 {
+	qualities := make(map[string]float64)
+
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		encoding := strings.ToLower(strings.TrimSpace(fields[0]))
+		if encoding == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(strings.ToLower(param), "q=") {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(param[2:]), 64)
+			if err != nil || parsed < 0 || parsed > 1 {
+				parsed = 0
+			}
+			q = parsed
+		}
+
+		qualities[encoding] = q
+	}
+
+	return qualities
+}
+
+// compressWriter comprime o body da resposta sob demanda
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	encoder  CompressionEncoder
+	writer   io.WriteCloser
+	bypass   bool
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if w.writer == nil && !w.bypass {
+		w.start()
+	}
+	if w.bypass {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.writer.Write(data)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// start inicializa o encoder no primeiro Write, antes dos headers serem enviados
+func (w *compressWriter) start() {
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		w.bypass = true
+		return
+	}
+
+	writer, err := w.encoder(w.ResponseWriter)
+	if err != nil {
+		w.bypass = true
+		return
+	}
+
+	header.Set("Content-Encoding", w.encoding)
+	header.Del("Content-Length")
+	w.writer = writer
+}
+
+// Flush envia o que o encoder já comprimiu antes de liberar o writer original
+// Sem isso o Flush do StreamJSON/StreamPaged não passaria do buffer do gzip
+func (w *compressWriter) Flush() {
+	if flusher, ok := w.writer.(interface{ Flush() error }); ok {
+		flusher.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *compressWriter) close() {
+	if w.writer != nil {
+		w.writer.Close()
+	}
+}
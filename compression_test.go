@@ -0,0 +1,135 @@
+package zendia
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	available := []string{"br", "gzip", "deflate"}
+
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{"sem header", "", ""},
+		{"gzip simples", "gzip", "gzip"},
+		{"empate usa preferência do servidor", "gzip, br, deflate", "br"},
+		{"q-value maior vence", "gzip;q=1.0, br;q=0.5", "gzip"},
+		{"q-value com espaços", "deflate ; q=0.9, gzip ; q=0.8", "deflate"},
+		{"identity veta gzip", "identity;q=1.0, gzip;q=0", ""},
+		{"identity com q maior", "identity, gzip;q=0.5", ""},
+		{"curinga", "*", "br"},
+		{"curinga com exclusão", "*;q=0.5, br;q=0", "gzip"},
+		{"curinga zerado", "*;q=0", ""},
+		{"encoding desconhecido", "compress", ""},
+		{"q inválido", "gzip;q=abc", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, negotiateEncoding(tt.header, available))
+		})
+	}
+
+	// Sem brotli registrado, gzip é escolhido
+	assert.Equal(t, "gzip", negotiateEncoding("br, gzip", []string{"gzip", "deflate"}))
+}
+
+func TestMiddleware_Compression(t *testing.T) {
+	app := New()
+	app.Use(Compression())
+
+	app.GET("/test", Handle(func(c *Context[any]) error {
+		c.Success("Message Teste: ", "compressed response")
+		return nil
+	}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	app.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+
+	reader, err := gzip.NewReader(w.Body)
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(reader)
+	assert.Contains(t, string(body), "compressed response")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/test", nil)
+	req.Header.Set("Accept-Encoding", "identity;q=1.0, gzip;q=0")
+	app.ServeHTTP(w, req)
+
+	assert.Equal(t, "", w.Header().Get("Content-Encoding"))
+	assert.Contains(t, w.Body.String(), "compressed response")
+}
+
+func TestMiddleware_CompressionStreaming(t *testing.T) {
+	release := make(chan struct{})
+	app := New()
+	app.Use(Compression())
+
+	app.GET("/export", Handle(func(c *Context[any]) error {
+		items := make(chan interface{})
+		go func() {
+			defer close(items)
+			for i := 0; i < streamFlushEvery; i++ {
+				items <- map[string]int{"id": i}
+			}
+			// Segura o stream: o primeiro lote precisa chegar antes do handler terminar
+			<-release
+			items <- map[string]int{"id": streamFlushEvery}
+		}()
+		return c.StreamJSON(items)
+	}))
+
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL+"/export", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err) {
+		close(release)
+		return
+	}
+	defer resp.Body.Close()
+	assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+
+	first := make(chan map[string]int, 1)
+	go func() {
+		defer close(first)
+		reader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return
+		}
+		decoder := json.NewDecoder(reader)
+		if _, err := decoder.Token(); err != nil {
+			return
+		}
+		var item map[string]int
+		if decoder.Decode(&item) == nil {
+			first <- item
+		}
+	}()
+
+	select {
+	case item, ok := <-first:
+		assert.True(t, ok, "first item should be decodable")
+		assert.Equal(t, 0, item["id"])
+	case <-time.After(2 * time.Second):
+		t.Error("compressed stream was not flushed before the handler returned")
+	}
+	close(release)
+}
@@ -9,6 +9,8 @@ const (
 	ClaimUserUUID string = "user_uuid" // ID do usuário no seu banco (não usar "user_id" - é reservado)
 	ClaimUserName string = "user_name" // Nome do usuário
 	ClaimRole     string = "role"      // Role do usuário no seu sistema
+
+	RoleAdmin string = "admin" // Role exigida pelo Context.ImpersonateTenant
 )
 
 // Context Keys - Internal context keys (do not modify)
@@ -20,28 +22,65 @@ const (
 	AuthTenantIDKey    string = "auth_tenant_id"
 	AuthUserIDKey      string = "auth_user_id"
 	AuthNameKey        string = "auth_name"
+	AuthRoleKey        string = "auth_role"
 )
 
 // HTTP Headers - Headers automáticos do framework
 const (
-	HeaderTenantID string = "X-Tenant-ID"
-	HeaderUserID   string = "X-User-ID"
-	HeaderUserName string = "X-User-Name"
+	HeaderTenantID   string = "X-Tenant-ID"
+	HeaderUserID     string = "X-User-ID"
+	HeaderUserName   string = "X-User-Name"
+	HeaderAPIVersion string = "X-API-Version"
+
+	HeaderCacheStatus string = "X-Cache" // HIT/MISS do CacheResponse
+
+	HeaderRateLimitLimit     string = "X-RateLimit-Limit"     // Requisições permitidas na janela do RateLimit
+	HeaderRateLimitRemaining string = "X-RateLimit-Remaining" // Requisições restantes na janela atual
 )
 
-// Default Public Routes - Rotas públicas padrão do Firebase Auth
+// Default Public Routes - Rotas públicas padrão de toda instância (ver Zendia.PublicRoutes)
 var DefaultPublicRoutes = []string{
 	"/health",
 	"/docs",
 	"/swagger",
+	"/public",
 }
 
+// StatusClientClosedRequest status não-padrão (convenção do nginx) para requisições
+// canceladas pelo cliente antes da resposta
+const StatusClientClosedRequest = 499
+
 // Response Fields - Campos padrão das respostas JSON
 const (
-	ResponseSuccess string = "success"
-	ResponseMessage string = "message"
-	ResponseData    string = "data"
-	ResponseError   string = "error"
+	ResponseSuccess   string = "success"
+	ResponseMessage   string = "message"
+	ResponseData      string = "data"
+	ResponseError     string = "error"
+	ResponseErrorCode string = "error_code"
+	ResponseTotal     string = "total"
+	ResponseSkip      string = "skip"     // StreamPaged
+	ResponseTake      string = "take"     // StreamPaged
+	ResponseHasMore   string = "has_more" // StreamPaged
+	ResponseStack     string = "stack"    // Só em respostas 500 com DevMode
+	ResponseFields    string = "fields"   // Erros por campo em respostas VALIDATION_FAILED
+)
+
+// Error Codes - Discriminador "error_code" das respostas de erro
+// Permite ao cliente distinguir, por exemplo, validação (exibir erros por campo)
+// de requisição malformada (mensagem genérica), ambos com status 400
+const (
+	ErrorCodeValidation           = "VALIDATION_FAILED"
+	ErrorCodeBadRequest           = "BAD_REQUEST"
+	ErrorCodeNotFound             = "NOT_FOUND"
+	ErrorCodeUnauthorized         = "UNAUTHORIZED"
+	ErrorCodeForbidden            = "FORBIDDEN"
+	ErrorCodeConflict             = "CONFLICT"
+	ErrorCodeUnsupportedMediaType = "UNSUPPORTED_MEDIA_TYPE"
+	ErrorCodeClientClosedRequest  = "CLIENT_CLOSED_REQUEST"
+	ErrorCodeUpgradeRequired      = "UPGRADE_REQUIRED"
+	ErrorCodeGatewayTimeout       = "GATEWAY_TIMEOUT"
+	ErrorCodeTooManyRequests      = "TOO_MANY_REQUESTS"
+	ErrorCodeInternal             = "INTERNAL_ERROR"
 )
 
 // Context Values - Values for context.Context (audit trail)
@@ -60,16 +99,21 @@ const (
 
 // Route Constants
 const (
-	RoutePublic  = "/public"
-	RouteDocs    = "/docs"
-	RouteAuth    = "/auth"
-	RouteSwagger = "/swagger"
-	RouteHealth  = "/health"
-	RouteAPIV1   = "/api/v1"
-	RouteLogin   = "/auth/login"
-	RouteMe      = "/me"
-	RouteUsers   = "/users"
-	RouteMetrics = "/public/metrics"
+	RoutePublic       = "/public"
+	RouteDocs         = "/docs"
+	RouteAuth         = "/auth"
+	RouteSwagger      = "/swagger"
+	RouteHealth       = "/health"
+	RouteHealthLive   = "/health/live"
+	RouteHealthReady  = "/health/ready"
+	RouteInfo         = "/info"
+	RouteSchemas      = "/schemas"
+	RouteAPIV1        = "/api/v1"
+	RouteLogin        = "/auth/login"
+	RouteMe           = "/me"
+	RouteUsers        = "/users"
+	RouteMetrics      = "/public/metrics"
+	RouteSlowRequests = "/admin/slow-requests"
 )
 
 // Environment Variables
@@ -86,6 +130,7 @@ const (
 	DefaultHost            = "localhost:8080"
 	DefaultVersion         = "1.0"
 	DefaultBasePath        = "/api/v1"
+	DefaultLocale          = "pt-BR" // Idioma das mensagens sem o middleware I18n
 )
 
 // Cache Constants
@@ -95,13 +140,68 @@ const (
 	DefaultMemoryCacheMaxMem = 5 * 1024 * 1024   // 5MB (in-memory)
 	DefaultRedisCacheMaxMem  = 100 * 1024 * 1024 // 100MB (Redis)
 	DefaultCacheKeyPrefix    = "zendia:"
+
+	DefaultMemoryCacheCleanupInterval = 5 * time.Minute
+	DefaultTenantValidationTTL        = time.Minute // Cache do resultado do TenantValidator
+)
+
+// Repository Constants
+const (
+	DefaultPageSize    = 10   // Take sem valor informado (ver Zendia.SetPageLimits)
+	DefaultMaxPageSize = 1000 // Maior take aceito por handlers e repositories
+
+	DefaultMaxResults      = 10000       // Limite de documentos do GetAll sem paginação
+	MaxCountByGroups       = 1000        // Grupos retornados pelo CountBy
+	MaxDeleteReasonLength  = 500         // Motivo de exclusão maior é truncado
+	DefaultImportBatchSize = 500         // Registros por InsertMany no ImportTenant
+	MaxImportLineSize      = 1024 * 1024 // 1MB por linha do NDJSON importado
+	DefaultImportMaxErrors = 1000        // Linhas recusadas guardadas no ImportReport
+
+	DefaultDeleteHookRevertTimeout = 10 * time.Second // Reversão do WithDeleteHookRollback sem WithOperationTimeout
+)
+
+// Feature Names - recursos reportados em StartupInfo
+const (
+	FeatureTenant             = "tenant"
+	FeatureFirebaseAuth       = "firebase_auth"
+	FeatureMonitoring         = "monitoring"
+	FeatureMetricsPersistence = "metrics_persistence"
+	FeatureHealth             = "health"
+	FeatureSlowRequests       = "slow_requests"
+	FeatureSwagger            = "swagger"
+)
+
+// Health Check Constants
+const (
+	DefaultHTTPHealthCheckBackoff  = 200 * time.Millisecond // Espera entre tentativas, multiplicada pela tentativa
+	DefaultCircuitBreakerThreshold = 5                      // Falhas seguidas até abrir o circuito
+	DefaultCircuitBreakerCooldown  = 30 * time.Second       // Tempo aberto antes da chamada de teste
+)
+
+// Upload Constants
+const (
+	DefaultMaxUploadSize = 10 * 1024 * 1024 // 10MB por arquivo
+)
+
+// Body Logger Constants
+const (
+	DefaultBodyLogMaxSize = 4 * 1024 // 4KB capturados por body no BodyLogger
+)
+
+// Rate Limit Constants
+const (
+	DefaultRateLimitRequests = 100         // Requisições por janela e por chave
+	DefaultRateLimitWindow   = time.Minute // Duração da janela do RateLimit
+	DefaultRateLimitBodyPeek = 64 * 1024   // Bytes do body lidos por RateLimitKeyByIPAndField
 )
 
 // Query Parameters
 const (
-	QuerySkip = "skip"
-	QueryTake = "take"
-	QueryName = "name"
+	QuerySkip   = "skip"
+	QueryTake   = "take"
+	QuerySort   = "sort"
+	QueryName   = "name"
+	QueryFields = "fields"
 )
 
 // Field Names
@@ -115,10 +215,41 @@ const (
 const (
 	MsgLoginRequired        = "Faça login primeiro para setar o tenant"
 	MsgInvalidPagination    = "Invalid pagination parameters"
+	MsgInvalidSort          = "Invalid sort field"
 	MsgInvalidUUID          = "Invalid UUID format"
+	MsgInvalidInteger       = "Invalid integer format"
+	MsgInvalidID            = "Invalid ID format"
+	MsgInvalidTenantID      = "Invalid tenant ID format"
+	MsgTenantNotAllowed     = "Tenant not found or disabled"
 	MsgRepositoryNotInit    = "Repository not properly initialized"
+	MsgInvalidFilterField   = "Invalid filter field"
+	MsgUnsupportedOperation = "Operation not supported by this repository"
+	MsgRequestCanceled      = "Request canceled by client"
+	MsgOperationTimedOut    = "Operation timed out"
+	MsgRateLimitExceeded    = "Too many requests, try again later"
+	MsgImpersonationDenied  = "Tenant impersonation requires the admin role"
+	MsgImpersonationReason  = "Tenant impersonation requires a reason"
+	MsgInvalidSelectField   = "Invalid field selection"
+	MsgTooManyResults       = "Result set too large, use pagination"
+	MsgMissingIsolationKey  = "Missing tenant isolation key"
+	MsgReadOnlyRepository   = "Repository is read-only"
+	MsgInvalidCursor        = "Invalid pagination cursor"
+	MsgFilterTooComplex     = "Filter too complex"
+	MsgEmptyPatch           = "No fields to update"
+	MsgInvalidPatchField    = "Invalid patch field"
+	MsgInvalidPipeline      = "Invalid aggregation pipeline"
+	MsgExportRequiresAudit  = "Tenant export requires an audited repository"
+	MsgImportRequiresAudit  = "Tenant import requires an audited repository"
+	MsgImportInvalidJSON    = "Invalid JSON record"
+	MsgImportLineTooLong    = "Import line too long"
+	MsgImportTenantMismatch = "Record belongs to another tenant"
+	MsgImportDuplicate      = "Record already exists"
+	MsgDeleteReasonRequired = "Delete reason is required"
+	MsgInternalServerError  = "Internal server error"
+	MsgSuccess              = "Sucesso."
 	MsgCreatedSuccess       = "Criado com sucesso."
 	MsgUpdatedSuccess       = "Atualizado com sucesso."
+	MsgImportCompleted      = "Importação concluída."
 	MsgRetrievedSuccess     = "Capturado com sucesso."
 	MsgRetrievedByIDSuccess = "Capturado com sucesso usando ID."
 	MsgUserData             = "Dados do usuário"
@@ -1,17 +1,36 @@
 package zendia
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// streamFlushEvery quantidade de itens entre cada flush do StreamJSON
+const streamFlushEvery = 100
+
+// BoundBodyKey chave do body validado por BindJSON/BindAll no gin.Context
+const BoundBodyKey = "bound_body"
+
 // Context é um wrapper do gin.Context com funcionalidades adicionais
 type Context[T any] struct {
 	*gin.Context
 	zendia *Zendia // Referência para a instância principal
+
+	errorCode string // error_code do APIError em tratamento por Handle
 }
 
 // BindJSON faz o bind e validação de dados JSON
@@ -22,11 +41,12 @@ func (c *Context[T]) BindJSON(obj *T) error {
 
 	// Valida usando o validator compartilhado
 	if c.zendia != nil {
-		if err := c.zendia.GetValidator().Validate(obj); err != nil {
+		if err := c.zendia.GetValidator().ValidateLocale(obj, c.Locale()); err != nil {
 			return err
 		}
 	}
 
+	c.Set(BoundBodyKey, obj)
 	return nil
 }
 
@@ -38,7 +58,7 @@ func (c *Context[T]) BindQuery(obj *T) error {
 
 	// Valida usando o validator compartilhado
 	if c.zendia != nil {
-		if err := c.zendia.GetValidator().Validate(obj); err != nil {
+		if err := c.zendia.GetValidator().ValidateLocale(obj, c.Locale()); err != nil {
 			return err
 		}
 	}
@@ -54,7 +74,7 @@ func (c *Context[T]) BindURI(obj *T) error {
 
 	// Valida usando o validator compartilhado
 	if c.zendia != nil {
-		if err := c.zendia.GetValidator().Validate(obj); err != nil {
+		if err := c.zendia.GetValidator().ValidateLocale(obj, c.Locale()); err != nil {
 			return err
 		}
 	}
@@ -62,38 +82,371 @@ func (c *Context[T]) BindURI(obj *T) error {
 	return nil
 }
 
+// BindAll faz o bind de URI, query e JSON na mesma estrutura, validando uma única vez no final
+//
+// Precedência: URI → query → JSON. Cada fonte só sobrescreve os campos que
+// estão presentes nela, então um campo vindo da URI permanece se o body não o
+// enviar. O JSON só é lido quando o Content-Type é application/json (ou um
+// tipo com sufixo +json, como application/merge-patch+json) e o body não está vazio.
+func (c *Context[T]) BindAll(obj *T) error {
+	// Cada fonte só decodifica; a validação de um struct parcial falharia
+	// em campos obrigatórios que ainda vão chegar pelas fontes seguintes
+	if len(c.Context.Params) > 0 {
+		params := make(map[string][]string, len(c.Context.Params))
+		for _, p := range c.Context.Params {
+			params[p.Key] = []string{p.Value}
+		}
+		if err := binding.MapFormWithTag(obj, params, "uri"); err != nil {
+			return NewValidationError("Invalid URI parameters", err)
+		}
+	}
+
+	if query := c.Request.URL.Query(); len(query) > 0 {
+		if err := binding.MapFormWithTag(obj, query, "form"); err != nil {
+			return NewValidationError("Invalid query parameters", err)
+		}
+	}
+
+	if isJSONContentType(c.ContentType()) && c.Request.Body != nil && c.Request.ContentLength != 0 {
+		decoder := json.NewDecoder(c.Request.Body)
+		if binding.EnableDecoderUseNumber {
+			decoder.UseNumber()
+		}
+		if binding.EnableDecoderDisallowUnknownFields {
+			decoder.DisallowUnknownFields()
+		}
+		if err := decoder.Decode(obj); err != nil && !errors.Is(err, io.EOF) {
+			return NewValidationError("Invalid JSON data", err)
+		}
+	}
+
+	// Tags binding do gin, validadas uma única vez com o struct completo
+	if binding.Validator != nil {
+		if err := binding.Validator.ValidateStruct(obj); err != nil {
+			return NewValidationError("Invalid request data", err)
+		}
+	}
+
+	// Valida usando o validator compartilhado
+	if c.zendia != nil {
+		if err := c.zendia.GetValidator().ValidateLocale(obj, c.Locale()); err != nil {
+			return err
+		}
+	}
+
+	c.Set(BoundBodyKey, obj)
+	return nil
+}
+
+// Body retorna o objeto do último BindJSON/BindAll bem-sucedido da requisição
+// ok é false se nada foi validado ou se o bind foi de outro tipo
+func (c *Context[T]) Body() (T, bool) {
+	if val, exists := c.Get(BoundBodyKey); exists {
+		if obj, ok := val.(*T); ok {
+			return *obj, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// GetBoundBody retorna o body validado (*T do handler) para middlewares que rodam depois do handler
+// Permite auditar o que foi enviado sem reler o body, já consumido pelo bind
+//
+//	func AuditBody() gin.HandlerFunc {
+//	    return func(c *gin.Context) {
+//	        c.Next()
+//	        if body, ok := zendia.GetBoundBody(c); ok {
+//	            auditLog.Record(c.FullPath(), body)
+//	        }
+//	    }
+//	}
+func GetBoundBody(c *gin.Context) (interface{}, bool) {
+	return c.Get(BoundBodyKey)
+}
+
+// isJSONContentType verifica se o Content-Type deve disparar bind de JSON
+func isJSONContentType(contentType string) bool {
+	return contentType == "application/json" || strings.HasSuffix(contentType, "+json")
+}
+
+// ParamUUID lê um parâmetro da rota e converte para UUID
+// Retorna BadRequest se o valor estiver ausente ou mal formatado
+func (c *Context[T]) ParamUUID(name string) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Context.Param(name))
+	if err != nil {
+		return uuid.Nil, NewBadRequestError(fmt.Sprintf("%s: parameter '%s'", MsgInvalidUUID, name))
+	}
+	return id, nil
+}
+
+// ParamInt lê um parâmetro da rota e converte para int
+// Retorna BadRequest se o valor estiver ausente ou não for numérico
+func (c *Context[T]) ParamInt(name string) (int, error) {
+	value, err := strconv.Atoi(c.Context.Param(name))
+	if err != nil {
+		return 0, NewBadRequestError(fmt.Sprintf("%s: parameter '%s'", MsgInvalidInteger, name))
+	}
+	return value, nil
+}
+
+// Pagination lê os query params skip/take aplicando defaults e limites
+// Aceita 0 <= skip e 0 < take <= maxTake; fora disso retorna BadRequest
+// defaultTake e maxTake <= 0 usam os limites da instância (ver Zendia.SetPageLimits)
+func (c *Context[T]) Pagination(defaultTake, maxTake int) (skip, take int, err error) {
+	limits := GetPageLimits(c.Request.Context())
+	if defaultTake <= 0 {
+		defaultTake = limits.Default
+	}
+	if maxTake <= 0 {
+		maxTake = limits.Max
+	}
+
+	skip, err = strconv.Atoi(c.DefaultQuery(QuerySkip, "0"))
+	if err != nil || skip < 0 {
+		return 0, 0, NewBadRequestError(MsgInvalidPagination)
+	}
+
+	take, err = strconv.Atoi(c.DefaultQuery(QueryTake, strconv.Itoa(defaultTake)))
+	if err != nil || take <= 0 || take > maxTake {
+		return 0, 0, NewBadRequestError(MsgInvalidPagination)
+	}
+
+	return skip, take, nil
+}
+
 // Success retorna uma resposta de sucesso padronizada
 // Se total for informado, inclui no response (para listagens paginadas)
 func (c *Context[T]) Success(message string, data interface{}, total ...int64) {
+	envelope := c.envelope()
 	response := gin.H{
-		ResponseMessage: message,
-		ResponseSuccess: true,
-		ResponseData:    data,
+		envelope.Message: message,
+		envelope.Success: true,
+		envelope.Data:    data,
 	}
 	if len(total) > 0 {
-		response["total"] = total[0]
+		response[envelope.Total] = total[0]
 	}
-	c.JSON(http.StatusOK, response)
+	c.renderJSON(http.StatusOK, response)
 }
 
 // Created retorna uma resposta de criação bem-sucedida
 func (c *Context[T]) Created(message string, data interface{}) {
-	c.JSON(http.StatusCreated, gin.H{
-		ResponseSuccess: true,
-		ResponseMessage: message,
-		ResponseData:    data,
+	envelope := c.envelope()
+	c.renderJSON(http.StatusCreated, gin.H{
+		envelope.Success: true,
+		envelope.Message: message,
+		envelope.Data:    data,
 	})
 }
 
 // Updated retorna uma resposta de atualização bem-sucedida
 func (c *Context[T]) Updated(message string, data interface{}) {
-	c.JSON(http.StatusOK, gin.H{
-		ResponseSuccess: true,
-		ResponseMessage: message,
-		ResponseData:    data,
+	envelope := c.envelope()
+	c.renderJSON(http.StatusOK, gin.H{
+		envelope.Success: true,
+		envelope.Message: message,
+		envelope.Data:    data,
+	})
+}
+
+// Accepted retorna 202 para operações assíncronas (ex: job enfileirado)
+// Se location for informado, seta o header Location apontando para o recurso de status
+func (c *Context[T]) Accepted(message string, data interface{}, location ...string) {
+	if len(location) > 0 && location[0] != "" {
+		c.Header("Location", location[0])
+	}
+
+	envelope := c.envelope()
+	c.renderJSON(http.StatusAccepted, gin.H{
+		envelope.Success: true,
+		envelope.Message: message,
+		envelope.Data:    data,
 	})
 }
 
+// envelope nomes das chaves do envelope configurados no Zendia
+func (c *Context[T]) envelope() ResponseEnvelopeConfig {
+	if c.zendia == nil {
+		return DefaultResponseEnvelope
+	}
+	return c.zendia.envelope
+}
+
+// renderJSON escreve a resposta usando o serializer configurado no Zendia
+// Sem serializer customizado usa o encoding/json padrão do gin
+func (c *Context[T]) renderJSON(code int, obj interface{}) {
+	if c.zendia == nil || c.zendia.jsonSerializer == nil {
+		c.JSON(code, obj)
+		return
+	}
+
+	data, err := c.zendia.jsonSerializer.Marshal(obj)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	c.Data(code, "application/json; charset=utf-8", data)
+}
+
+// StreamJSON escreve os itens do canal como um array JSON, sem montar a lista em memória
+// A resposta é um array puro (sem o envelope success/data) e é enviada em partes a
+// cada streamFlushEvery itens. Para quando o canal fecha ou o cliente desconecta;
+// erros depois do primeiro byte não mudam mais o status, apenas encerram o stream
+func (c *Context[T]) StreamJSON(items <-chan interface{}) error {
+	marshal := c.marshaler()
+
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", "application/json; charset=utf-8")
+
+	if _, err := c.Writer.WriteString("["); err != nil {
+		return err
+	}
+
+	count := 0
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case item, ok := <-items:
+			if !ok {
+				_, err := c.Writer.WriteString("]")
+				c.Writer.Flush()
+				return err
+			}
+
+			data, err := marshal(item)
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				if _, err := c.Writer.WriteString(","); err != nil {
+					return err
+				}
+			}
+			if _, err := c.Writer.Write(data); err != nil {
+				return err
+			}
+
+			count++
+			if count%streamFlushEvery == 0 {
+				c.Writer.Flush()
+			}
+		}
+	}
+}
+
+// StreamPaged escreve a página do cursor no envelope paginado, sem montar a lista em memória
+// O cabeçalho (success, message, total, skip, take, has_more) sai antes dos documentos, que
+// são decodificados um a um no array data e enviados a cada streamFlushEvery itens.
+// Cada documento vira T: use o Context do tipo da entidade (Context[*User]) para respeitar
+// as tags json; com Context[any] os documentos saem como bson.M. O cursor é fechado ao final.
+// Como no StreamJSON, erros depois do primeiro byte apenas encerram o stream.
+//
+//	cursor, err := col.Find(ctx, filter, options.Find().SetSkip(int64(skip)).SetLimit(int64(take)))
+//	...
+//	return c.StreamPaged(cursor, total, skip, take)
+func (c *Context[T]) StreamPaged(cursor *mongo.Cursor, total int64, skip, take int) error {
+	ctx := c.Request.Context()
+	defer cursor.Close(context.Background())
+
+	marshal := c.marshaler()
+	envelope := c.envelope()
+	header, err := marshal(gin.H{
+		envelope.Success: true,
+		envelope.Message: MsgRetrievedSuccess,
+		envelope.Total:   total,
+		ResponseSkip:     skip,
+		ResponseTake:     take,
+		ResponseHasMore:  int64(skip)+int64(take) < total,
+	})
+	if err != nil {
+		return err
+	}
+	dataKey, err := json.Marshal(envelope.Data)
+	if err != nil {
+		return err
+	}
+
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", "application/json; charset=utf-8")
+
+	// Reabre o objeto do cabeçalho para acrescentar "data":[...]
+	header = bytes.TrimSpace(header)
+	if _, err := c.Writer.Write(header[:len(header)-1]); err != nil {
+		return err
+	}
+	if _, err := c.Writer.WriteString("," + string(dataKey) + ":["); err != nil {
+		return err
+	}
+
+	count := 0
+	for cursor.Next(ctx) {
+		var doc T
+		var item interface{} = &doc
+		if any(doc) == nil {
+			item = &bson.M{}
+		}
+		if err := cursor.Decode(item); err != nil {
+			return err
+		}
+
+		data, err := marshal(item)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			if _, err := c.Writer.WriteString(","); err != nil {
+				return err
+			}
+		}
+		if _, err := c.Writer.Write(data); err != nil {
+			return err
+		}
+
+		count++
+		if count%streamFlushEvery == 0 {
+			c.Writer.Flush()
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	_, err = c.Writer.WriteString("]}")
+	c.Writer.Flush()
+	return err
+}
+
+// CacheControl permite que browsers (e CDNs, se public) guardem a resposta por maxAge
+// maxAge <= 0 gera "no-cache": a resposta pode ser guardada, mas é revalidada a cada uso.
+// Chame antes de escrever a resposta:
+//
+//	c.CacheControl(time.Hour, true) // Cache-Control: public, max-age=3600
+func (c *Context[T]) CacheControl(maxAge time.Duration, public bool) {
+	scope := "private"
+	if public {
+		scope = "public"
+	}
+	if maxAge <= 0 {
+		c.Header("Cache-Control", scope+", no-cache")
+		return
+	}
+	c.Header("Cache-Control", fmt.Sprintf("%s, max-age=%d", scope, int64(maxAge/time.Second)))
+}
+
+// CacheImmutable marca a resposta como pública e imutável por maxAge (ex: arquivos versionados)
+// O browser não revalida nem em reload enquanto estiver dentro do prazo
+func (c *Context[T]) CacheImmutable(maxAge time.Duration) {
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d, immutable", int64(maxAge/time.Second)))
+}
+
+// NoStore proíbe browsers e proxies de guardar a resposta (dados sensíveis)
+func (c *Context[T]) NoStore() {
+	c.Header("Cache-Control", "no-store")
+}
+
 // NoContent retorna uma resposta sem conteúdo
 func (c *Context[T]) NoContent() {
 	c.Status(http.StatusNoContent)
@@ -101,14 +454,53 @@ func (c *Context[T]) NoContent() {
 
 // Fail retorna uma resposta de erro padronizada
 func (c *Context[T]) Fail(code int, message string, err error) {
+	errorCode := c.errorCode
+	if errorCode == "" {
+		errorCode = errorCodeForStatus(code)
+	}
+
+	envelope := c.envelope()
 	response := gin.H{
-		ResponseSuccess: false,
-		ResponseMessage: message,
+		envelope.Success:   false,
+		envelope.Message:   message,
+		envelope.ErrorCode: errorCode,
 	}
 	if err != nil {
-		response[ResponseError] = err.Error()
+		response[envelope.Error] = err.Error()
+	}
+	var fields FieldErrors
+	if errors.As(err, &fields) {
+		response[ResponseFields] = fields
+	}
+	c.renderJSON(code, response)
+}
+
+// errorCodeForStatus error_code padrão para respostas de erro emitidas direto pelos helpers
+func errorCodeForStatus(code int) string {
+	switch code {
+	case http.StatusBadRequest:
+		return ErrorCodeBadRequest
+	case http.StatusNotFound:
+		return ErrorCodeNotFound
+	case http.StatusUnauthorized:
+		return ErrorCodeUnauthorized
+	case http.StatusForbidden:
+		return ErrorCodeForbidden
+	case http.StatusConflict:
+		return ErrorCodeConflict
+	case http.StatusUnsupportedMediaType:
+		return ErrorCodeUnsupportedMediaType
+	case StatusClientClosedRequest:
+		return ErrorCodeClientClosedRequest
+	case http.StatusUpgradeRequired:
+		return ErrorCodeUpgradeRequired
+	case http.StatusGatewayTimeout:
+		return ErrorCodeGatewayTimeout
+	case http.StatusTooManyRequests:
+		return ErrorCodeTooManyRequests
+	default:
+		return ErrorCodeInternal
 	}
-	c.JSON(code, response)
 }
 
 // BadRequest retorna um erro de requisição inválida
@@ -161,6 +553,31 @@ func (c *Context[T]) Forbidden(message string) {
 	c.Fail(http.StatusForbidden, message, nil)
 }
 
+// UnsupportedMediaType retorna um erro de Content-Type não suportado (415)
+func (c *Context[T]) UnsupportedMediaType(message string) {
+	c.Fail(http.StatusUnsupportedMediaType, message, nil)
+}
+
+// UpgradeRequired retorna 426 quando a versão do cliente não é mais suportada
+func (c *Context[T]) UpgradeRequired(message string) {
+	c.Fail(http.StatusUpgradeRequired, message, nil)
+}
+
+// GatewayTimeout retorna 504 quando uma dependência (ex: MongoDB) estourou o prazo
+func (c *Context[T]) GatewayTimeout(message string) {
+	c.Fail(http.StatusGatewayTimeout, message, nil)
+}
+
+// TooManyRequests retorna 429 quando o cliente excedeu o limite de requisições
+func (c *Context[T]) TooManyRequests(message string) {
+	c.Fail(http.StatusTooManyRequests, message, nil)
+}
+
+// ClientClosedRequest retorna 499 quando o cliente cancelou a requisição
+func (c *Context[T]) ClientClosedRequest(message string) {
+	c.Fail(StatusClientClosedRequest, message, nil)
+}
+
 // GetTenantID retorna o tenant ID do contexto
 func (c *Context[T]) GetTenantID() string {
 	return GetTenantIDFromGin(c.Context)
@@ -182,37 +599,45 @@ func (c *Context[T]) GetTenantInfo() TenantInfo {
 }
 
 // SetTenant seta o tenant ID no contexto da sessão
+// Substitui o tenant do header (TenantExtractor), mas não o do claim do token:
+// com Firebase Auth o tenant do token prevalece. Veja TenantSource.
 func (c *Context[T]) SetTenant(tenantID string) {
+	if !setIdentity(c.Context, TenantIDKey, tenantID, TenantSourceExplicit) {
+		return
+	}
 	c.Set(AuthTenantIDKey, tenantID)
-	c.Set(TenantIDKey, tenantID)
 	c.Header(HeaderTenantID, tenantID)
 	
 	// Atualiza context para auditoria
 	ctx := context.WithValue(c.Request.Context(), ContextTenantID, tenantID)
-	ctx = context.WithValue(ctx, TenantIDKey, tenantID)
 	c.Request = c.Request.WithContext(ctx)
 }
 
+// TenantSource retorna a origem do tenant da requisição (claim, explicit, header ou none)
+func (c *Context[T]) TenantSource() TenantSource {
+	return GetTenantSourceFromGin(c.Context)
+}
+
 // SetUserID seta o user ID customizado no contexto
+// Mesma precedência do SetTenant: não substitui o user ID do claim do token
 func (c *Context[T]) SetUserID(userID string) {
+	if !setIdentity(c.Context, UserIDKey, userID, TenantSourceExplicit) {
+		return
+	}
 	c.Set(AuthUserIDKey, userID)
-	c.Set(UserIDKey, userID)
 	c.Header(HeaderUserID, userID)
 	
 	// Atualiza context para auditoria
 	ctx := context.WithValue(c.Request.Context(), ContextUserID, userID)
-	ctx = context.WithValue(ctx, UserIDKey, userID)
 	c.Request = c.Request.WithContext(ctx)
 }
 
 
 
-// SetUserName seta o nome do usuário no contexto
+// SetUserName seta o nome do usuário no contexto (não substitui o nome do claim do token)
 func (c *Context[T]) SetUserName(userName string) {
+	if !setIdentity(c.Context, UserNameKey, userName, TenantSourceExplicit) {
+		return
+	}
 	c.Set(AuthNameKey, userName)
-	c.Set(UserNameKey, userName)
-	
-	// Atualiza context
-	ctx := context.WithValue(c.Request.Context(), UserNameKey, userName)
-	c.Request = c.Request.WithContext(ctx)
 }
@@ -0,0 +1,406 @@
+package zendia
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+)
+
+// CRUDRepository operações usadas pelo RegisterCRUD
+// Repository[T] satisfaz com ID uuid.UUID; SQLRepository e SQLAuditRepository com o próprio ID
+type CRUDRepository[T any, ID any] interface {
+	Create(ctx context.Context, entity T) (T, error)
+	GetByID(ctx context.Context, id ID) (T, error)
+	GetAllSkipTake(ctx context.Context, filters map[string]interface{}, pagination Pagination, opts ...*QueryOptions) ([]T, int64, error)
+	Update(ctx context.Context, id ID, entity T) (T, error)
+	Patch(ctx context.Context, id ID, fields map[string]interface{}) (T, error)
+	Delete(ctx context.Context, id ID) error
+}
+
+// crudPatchValidator repositories que validam o PATCH antes de gravar (Repository[T])
+type crudPatchValidator[T any, ID any] interface {
+	PatchValidated(ctx context.Context, id ID, fields map[string]interface{}) (T, error)
+}
+
+// CRUDVerb rotas geradas pelo RegisterCRUD, combináveis com |
+type CRUDVerb int
+
+const (
+	CRUDList   CRUDVerb = 1 << iota // GET    ""
+	CRUDGet                         // GET    "/:id"
+	CRUDCreate                      // POST   ""
+	CRUDUpdate                      // PUT    "/:id"
+	CRUDPatch                       // PATCH  "/:id"
+	CRUDDelete                      // DELETE "/:id"
+
+	CRUDReadOnly = CRUDList | CRUDGet
+	CRUDAll      = CRUDList | CRUDGet | CRUDCreate | CRUDUpdate | CRUDPatch | CRUDDelete
+)
+
+// CRUDConfig configuração do RegisterCRUD
+type CRUDConfig[ID any] struct {
+	Verbs        CRUDVerb                 // Rotas registradas (padrão: CRUDAll)
+	ParseID      func(string) (ID, error) // Converte o :id da URL; obrigatório quando ID não é uuid.UUID
+	FilterFields []string                 // Query params aceitos como filtro de igualdade na listagem
+	Pagination   PaginationConfig         // Limites e campos de ?skip=, ?take= e ?sort=
+	SelectFields []string                 // Campos JSON aceitos no ?fields= da listagem e do GET por ID
+}
+
+// RegisterCRUD registra no grupo as rotas de CRUD ligadas ao repository
+// Cada rota responde no envelope padrão e os erros do repository passam pelo Handle
+// (NotFound → 404, BadRequest → 400...). A listagem aceita ?skip=, ?take=, ?sort= e,
+// como filtro, só os query params de FilterFields; qualquer outro responde 400.
+// Com SelectFields, listagem e GET por ID aceitam ?fields=id,name (resposta parcial).
+// POST e PUT validam o body com o validator da instância. O PATCH aceita só campos json de T
+// (outro responde 400), grava com os nomes bson/db e valida a entidade resultante: com o
+// Repository Mongo via PatchValidated, com os demais aplicando o patch sobre o GetByID.
+//
+// Uso:
+//
+//	users := api.Group("/users")
+//	zendia.RegisterCRUD[*User, uuid.UUID](users, userRepo, zendia.CRUDConfig[uuid.UUID]{
+//	    Verbs:        zendia.CRUDAll &^ zendia.CRUDDelete,
+//	    FilterFields: []string{"name", "email"},
+//	    Pagination:   zendia.PaginationConfig{MaxTake: 100, SortFields: []string{"name"}},
+//	})
+func RegisterCRUD[T any, ID any](rg *RouteGroup, repo CRUDRepository[T, ID], config CRUDConfig[ID]) {
+	if config.Verbs == 0 {
+		config.Verbs = CRUDAll
+	}
+	if config.ParseID == nil {
+		config.ParseID = defaultParseID[ID]()
+	}
+	sortFields := make(map[string]bool, len(config.Pagination.SortFields))
+	for _, field := range config.Pagination.SortFields {
+		sortFields[field] = true
+	}
+	filterFields := make(map[string]bool, len(config.FilterFields))
+	for _, field := range config.FilterFields {
+		filterFields[field] = true
+	}
+
+	if config.Verbs&CRUDList != 0 {
+		rg.GET("", Handle(func(c *Context[T]) error {
+			params, apiErr := parsePageParams(c.Context, config.Pagination, sortFields)
+			if apiErr != nil {
+				return apiErr
+			}
+			filters, err := crudFilters(c, filterFields)
+			if err != nil {
+				return err
+			}
+			fields, err := c.Fields(config.SelectFields...)
+			if err != nil {
+				return err
+			}
+
+			var opts []*QueryOptions
+			queryOpts := params.QueryOptions()
+			if projection := FieldsProjection[T](fields); projection != nil {
+				if queryOpts == nil {
+					queryOpts = &QueryOptions{}
+				}
+				queryOpts.Projection = projection
+			}
+			if queryOpts != nil {
+				opts = append(opts, queryOpts)
+			}
+			items, total, err := repo.GetAllSkipTake(c.Request.Context(), filters, params.Pagination(), opts...)
+			if err != nil {
+				return err
+			}
+			if len(fields) == 0 {
+				c.Success(MsgRetrievedSuccess, items, total)
+				return nil
+			}
+			selected, err := selectFields(c.marshaler(), items, fields)
+			if err != nil {
+				return err
+			}
+			c.Success(MsgRetrievedSuccess, selected, total)
+			return nil
+		}))
+	}
+
+	if config.Verbs&CRUDGet != 0 {
+		rg.GET("/:id", Handle(func(c *Context[T]) error {
+			id, err := crudID(c, config.ParseID)
+			if err != nil {
+				return err
+			}
+			fields, err := c.Fields(config.SelectFields...)
+			if err != nil {
+				return err
+			}
+			entity, err := repo.GetByID(c.Request.Context(), id)
+			if err != nil {
+				return err
+			}
+			c.SuccessFields(MsgRetrievedByIDSuccess, entity, fields)
+			return nil
+		}))
+	}
+
+	if config.Verbs&CRUDCreate != 0 {
+		rg.POST("", Handle(func(c *Context[T]) error {
+			entity, err := bindEntity(c)
+			if err != nil {
+				return err
+			}
+			created, err := repo.Create(c.Request.Context(), entity)
+			if err != nil {
+				return err
+			}
+			c.Created(MsgCreatedSuccess, created)
+			return nil
+		}))
+	}
+
+	if config.Verbs&CRUDUpdate != 0 {
+		rg.PUT("/:id", Handle(func(c *Context[T]) error {
+			id, err := crudID(c, config.ParseID)
+			if err != nil {
+				return err
+			}
+			entity, err := bindEntity(c)
+			if err != nil {
+				return err
+			}
+			updated, err := repo.Update(c.Request.Context(), id, entity)
+			if err != nil {
+				return err
+			}
+			c.Success(MsgUpdatedSuccess, updated)
+			return nil
+		}))
+	}
+
+	if config.Verbs&CRUDPatch != 0 {
+		rg.PATCH("/:id", Handle(func(c *Context[T]) error {
+			id, err := crudID(c, config.ParseID)
+			if err != nil {
+				return err
+			}
+			patch, err := bindPatch(c)
+			if err != nil {
+				return err
+			}
+
+			var updated T
+			if validated, ok := any(repo).(crudPatchValidator[T, ID]); ok {
+				updated, err = validated.PatchValidated(c.Request.Context(), id, patch.fields(bsonName))
+			} else {
+				current, err := repo.GetByID(c.Request.Context(), id)
+				if err != nil {
+					return err
+				}
+				if err := validateEntity(c, applyPatch(patch, current)); err != nil {
+					return err
+				}
+				updated, err = repo.Patch(c.Request.Context(), id, patch.fields(sqlColumnName))
+			}
+			if err != nil {
+				return err
+			}
+			c.Success(MsgUpdatedSuccess, updated)
+			return nil
+		}))
+	}
+
+	if config.Verbs&CRUDDelete != 0 {
+		rg.DELETE("/:id", Handle(func(c *Context[T]) error {
+			id, err := crudID(c, config.ParseID)
+			if err != nil {
+				return err
+			}
+			if err := repo.Delete(c.Request.Context(), id); err != nil {
+				return err
+			}
+			c.NoContent()
+			return nil
+		}))
+	}
+}
+
+// defaultParseID parser do :id para uuid.UUID; outros tipos de ID exigem CRUDConfig.ParseID
+func defaultParseID[ID any]() func(string) (ID, error) {
+	var zero ID
+	if _, ok := any(zero).(uuid.UUID); !ok {
+		panic(fmt.Sprintf("zendia: RegisterCRUD requires CRUDConfig.ParseID for ID type %T", zero))
+	}
+	return func(raw string) (ID, error) {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return zero, err
+		}
+		return any(id).(ID), nil
+	}
+}
+
+// crudID converte o :id da URL, respondendo BadRequest quando o parser recusa
+func crudID[T any, ID any](c *Context[T], parse func(string) (ID, error)) (ID, error) {
+	id, err := parse(c.Param("id"))
+	if err != nil {
+		var zero ID
+		return zero, NewBadRequestError(fmt.Sprintf("%s: parameter '%s'", MsgInvalidID, "id"))
+	}
+	return id, nil
+}
+
+// crudFilters monta os filtros de igualdade a partir dos query params permitidos
+func crudFilters[T any](c *Context[T], allowed map[string]bool) (map[string]interface{}, error) {
+	filters := map[string]interface{}{}
+	for key, values := range c.Request.URL.Query() {
+		if key == QuerySkip || key == QueryTake || key == QuerySort || key == QueryFields {
+			continue
+		}
+		if !allowed[key] || len(values) != 1 {
+			return nil, NewBadRequestError(MsgInvalidFilterField + ": " + sanitizeLogValue(key))
+		}
+		filters[key] = values[0]
+	}
+	return filters, nil
+}
+
+// bindEntity lê o body como uma nova entidade e a valida
+// Não usa BindJSON: com T ponteiro (*User) ele validaria um **User. Aplica as tags
+// binding do gin e as validate do validator da instância, como o BindJSON.
+func bindEntity[T any](c *Context[T]) (T, error) {
+	var entity T
+	if c.Request.Body == nil {
+		return entity, NewBadRequestError("Invalid JSON data")
+	}
+	if err := json.NewDecoder(c.Request.Body).Decode(&entity); err != nil {
+		return entity, NewValidationError("Invalid JSON data", err)
+	}
+	if err := validateEntity(c, entity); err != nil {
+		return entity, err
+	}
+	c.Set(BoundBodyKey, &entity)
+	return entity, nil
+}
+
+// validateEntity aplica as tags binding do gin e as validate do validator da instância
+func validateEntity[T any](c *Context[T], entity T) error {
+	// Body "null" deixaria a entidade nil (e o validator do gin entra em panic com ela)
+	if v := reflect.ValueOf(entity); !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return NewBadRequestError("Invalid JSON data")
+	}
+	if err := binding.Validator.ValidateStruct(entity); err != nil {
+		return NewValidationError("Invalid JSON data", err)
+	}
+	if c.zendia != nil {
+		return c.zendia.GetValidator().ValidateLocale(entity, c.Locale())
+	}
+	return nil
+}
+
+// crudPatch campos do body de um PATCH já decodificados nos tipos dos campos de T
+type crudPatch struct {
+	targets []reflect.StructField
+	values  []reflect.Value
+}
+
+// bindPatch lê o body do PATCH resolvendo cada chave json para o campo de T
+// Chave sem campo correspondente ou valor de tipo incompatível vira um FieldError (400)
+func bindPatch[T any](c *Context[T]) (*crudPatch, error) {
+	var raw map[string]json.RawMessage
+	if c.Request.Body == nil {
+		return nil, NewBadRequestError("Invalid JSON data")
+	}
+	if err := json.NewDecoder(c.Request.Body).Decode(&raw); err != nil {
+		return nil, NewValidationError("Invalid JSON data", err)
+	}
+	if len(raw) == 0 {
+		return nil, NewBadRequestError(MsgEmptyPatch)
+	}
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, NewBadRequestError(MsgInvalidPatchField)
+	}
+
+	names := make([]string, 0, len(raw))
+	for name := range raw {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	patch := &crudPatch{}
+	var errs FieldErrors
+	for _, name := range names {
+		field, ok := fieldByJSONName(t, name)
+		if ok {
+			// Índice a partir da raiz, inclusive para campos de structs embutidas
+			field, ok = t.FieldByName(field.Name)
+		}
+		if !ok || !field.IsExported() {
+			errs = append(errs, FieldError{Field: sanitizeLogValue(name), Tag: "unknown", Message: MsgInvalidPatchField + ": " + sanitizeLogValue(name)})
+			continue
+		}
+		value := reflect.New(field.Type)
+		if err := json.Unmarshal(raw[name], value.Interface()); err != nil {
+			errs = append(errs, FieldError{Field: name, Tag: "type", Message: MsgInvalidPatchField + ": " + name})
+			continue
+		}
+		patch.targets = append(patch.targets, field)
+		patch.values = append(patch.values, value.Elem())
+	}
+	if len(errs) > 0 {
+		return nil, NewValidationError("Validation failed", errs)
+	}
+	return patch, nil
+}
+
+// fields monta o map do Patch com o nome de armazenamento de cada campo
+func (p *crudPatch) fields(name func(reflect.StructField) string) map[string]interface{} {
+	fields := make(map[string]interface{}, len(p.targets))
+	for i, field := range p.targets {
+		fields[name(field)] = p.values[i].Interface()
+	}
+	return fields
+}
+
+// applyPatch devolve uma cópia de entity com os campos do patch aplicados
+func applyPatch[T any](p *crudPatch, entity T) T {
+	v := reflect.ValueOf(entity)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return entity
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return entity
+	}
+
+	merged := reflect.New(v.Type())
+	merged.Elem().Set(v)
+	for i, field := range p.targets {
+		// Struct embutida por ponteiro nil: o campo não existe na cópia
+		if target, err := merged.Elem().FieldByIndexErr(field.Index); err == nil {
+			target.Set(p.values[i])
+		}
+	}
+	if reflect.TypeOf(entity).Kind() == reflect.Ptr {
+		return merged.Interface().(T)
+	}
+	return merged.Elem().Interface().(T)
+}
+
+// sqlColumnName nome da coluna do campo: a tag db ou o nome Go em minúsculas (como o SQLRepository)
+func sqlColumnName(field reflect.StructField) string {
+	if name := strings.SplitN(field.Tag.Get("db"), ",", 2)[0]; name != "" && name != "-" {
+		return name
+	}
+	return strings.ToLower(field.Name)
+}
@@ -0,0 +1,215 @@
+package zendia
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DeleteHook reações às transições de soft delete do Repository
+// Cada método recebe a entidade no estado novo (OnHardDelete, o documento removido) e o
+// ctx da operação, com o tenant e o usuário que a fizeram (GetTenantInfo). Roda depois da
+// escrita: o erro é logado e, sem WithDeleteHookRollback, não desfaz a operação.
+//
+//	type userDeleteHook struct{ sessions *SessionStore }
+//
+//	func (h userDeleteHook) OnSoftDelete(ctx context.Context, u *User) error {
+//	    return h.sessions.RevokeAll(ctx, u.ID) // usuário desativado perde as sessões
+//	}
+type DeleteHook[T any] interface {
+	OnSoftDelete(ctx context.Context, entity T) error
+	OnRestore(ctx context.Context, entity T) error
+	OnHardDelete(ctx context.Context, entity T) error
+}
+
+// DeleteHookFuncs DeleteHook a partir de funções; as nil são ignoradas
+//
+//	repo := zendia.NewRepository[*User](col, zendia.WithAudit(),
+//	    zendia.WithDeleteHook[*User](zendia.DeleteHookFuncs[*User]{
+//	        Restore: func(ctx context.Context, u *User) error { return search.Index(ctx, u) },
+//	    }))
+type DeleteHookFuncs[T any] struct {
+	SoftDelete func(ctx context.Context, entity T) error
+	Restore    func(ctx context.Context, entity T) error
+	HardDelete func(ctx context.Context, entity T) error
+}
+
+func (h DeleteHookFuncs[T]) OnSoftDelete(ctx context.Context, entity T) error {
+	if h.SoftDelete == nil {
+		return nil
+	}
+	return h.SoftDelete(ctx, entity)
+}
+
+func (h DeleteHookFuncs[T]) OnRestore(ctx context.Context, entity T) error {
+	if h.Restore == nil {
+		return nil
+	}
+	return h.Restore(ctx, entity)
+}
+
+func (h DeleteHookFuncs[T]) OnHardDelete(ctx context.Context, entity T) error {
+	if h.HardDelete == nil {
+		return nil
+	}
+	return h.HardDelete(ctx, entity)
+}
+
+// WithDeleteHook registra o hook chamado em Delete/DeleteWithReason, Restore e HardDelete
+// T deve ser o mesmo tipo de entidade do repository: o NewRepository confere na criação e
+// entra em panic com outro tipo, nunca no meio de um delete. Com hook, DeleteMany e
+// RestoreMany processam registro a registro para chamar o hook de cada um (sem atalho em lote).
+func WithDeleteHook[T any](hook DeleteHook[T]) RepositoryOption {
+	return func(c *RepositoryConfig) {
+		c.deleteHook = hook
+	}
+}
+
+// WithDeleteHookRollback faz o erro do DeleteHook desfazer a transição e voltar para o chamador
+// O soft delete é revertido para ativo, o restore volta a deletado e o hard delete
+// reinsere o documento removido. Sem esta option o erro só é logado.
+func WithDeleteHookRollback() RepositoryOption {
+	return func(c *RepositoryConfig) {
+		c.deleteHookRollback = true
+	}
+}
+
+// deleteHook hook configurado para T (nil sem WithDeleteHook)
+// O tipo é conferido no NewRepository, então aqui a asserção nunca entra em panic
+func (r *Repository[T]) deleteHook() DeleteHook[T] {
+	hook, _ := r.config.deleteHook.(DeleteHook[T])
+	return hook
+}
+
+// checkDeleteHook confere na criação do repository que o WithDeleteHook é do tipo da entidade
+func checkDeleteHook[T any](cfg RepositoryConfig) {
+	if cfg.deleteHook == nil {
+		return
+	}
+	if _, ok := cfg.deleteHook.(DeleteHook[T]); !ok {
+		var zero T
+		panic(fmt.Sprintf("zendia: WithDeleteHook type %T does not match repository entity %T", cfg.deleteHook, zero))
+	}
+}
+
+// notifyDeleteHook chama o hook da transição; com WithDeleteHookRollback o erro reverte a escrita
+func (r *Repository[T]) notifyDeleteHook(ctx context.Context, transition string, entity T, call func(context.Context, T) error, revert func(context.Context) error) error {
+	err := call(ctx, entity)
+	if err == nil {
+		return nil
+	}
+
+	log.Printf("⚠️  %s hook failed for %s %s: %v", transition, r.collection.Name(), entity.GetID(), err)
+	if !r.config.deleteHookRollback {
+		return nil
+	}
+	// O hook pode ter consumido o prazo do WithOperationTimeout: a reversão usa um ctx próprio
+	revertCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), r.revertTimeout())
+	defer cancel()
+	if revertErr := revert(revertCtx); revertErr != nil {
+		return internalError("Failed to roll back "+transition, revertErr)
+	}
+	return err
+}
+
+// revertTimeout prazo da reversão do WithDeleteHookRollback (o do WithOperationTimeout, ou DefaultDeleteHookRevertTimeout)
+func (r *Repository[T]) revertTimeout() time.Duration {
+	if r.config.opTimeout > 0 {
+		return r.config.opTimeout
+	}
+	return DefaultDeleteHookRevertTimeout
+}
+
+// deleteEach soft delete registro a registro do DeleteMany para chamar o hook de cada um
+// Percorre o cursor lendo só os _id, sem carregar todos os documentos em memória.
+// Para no primeiro erro (ex: hook com rollback), retornando quantos já foram deletados
+func (r *Repository[T]) deleteEach(ctx context.Context, filter bson.M) (int64, error) {
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return 0, internalError("Failed to delete entities", err)
+	}
+	defer cursor.Close(ctx)
+
+	var count int64
+	for cursor.Next(ctx) {
+		var entity T
+		if err := cursor.Decode(&entity); err != nil {
+			return count, internalError("Failed to decode entities", err)
+		}
+		if err := r.softDelete(ctx, entity.GetID(), ""); err != nil {
+			if isNotFoundError(err) {
+				continue // deletado em paralelo
+			}
+			return count, err
+		}
+		count++
+	}
+	if err := cursor.Err(); err != nil {
+		return count, internalError("Failed to delete entities", err)
+	}
+	return count, nil
+}
+
+// restoreEach restaura registro a registro do RestoreMany para chamar o hook de cada um
+// Ids não deletados ou de outro tenant são ignorados, como no lote
+func (r *Repository[T]) restoreEach(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	var count int64
+	for _, id := range ids {
+		if err := r.Restore(ctx, id); err != nil {
+			if isNotFoundError(err) {
+				continue
+			}
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// isNotFoundError indica um *APIError de recurso não encontrado
+func isNotFoundError(err error) bool {
+	apiErr, ok := err.(*APIError)
+	return ok && apiErr.Type == NotFoundErrorType
+}
+
+// revertSoftDelete volta a entidade a ativa depois de um OnSoftDelete com erro
+func (r *Repository[T]) revertSoftDelete(id uuid.UUID) func(context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := r.collection.UpdateOne(ctx, bson.M{"_id": r.idToBSON(id)}, bson.M{
+			"$set":   bson.M{"active": true},
+			"$unset": bson.M{"deleted": ""},
+		})
+		return err
+	}
+}
+
+// revertRestore volta a entidade a deletada, com o deleted e o updated anteriores, depois de um OnRestore com erro
+func (r *Repository[T]) revertRestore(id uuid.UUID, before bson.Raw) func(context.Context) error {
+	return func(ctx context.Context) error {
+		set := bson.M{"active": false}
+		update := bson.M{"$set": set}
+		if deleted := before.Lookup("deleted"); len(deleted.Value) > 0 {
+			set["deleted"] = deleted
+		}
+		if updated := before.Lookup("updated"); len(updated.Value) > 0 {
+			set["updated"] = updated
+		} else if r.config.audit {
+			update["$unset"] = bson.M{"updated": ""}
+		}
+		_, err := r.collection.UpdateOne(ctx, bson.M{"_id": r.idToBSON(id)}, update)
+		return err
+	}
+}
+
+// revertHardDelete reinsere o documento removido depois de um OnHardDelete com erro
+func (r *Repository[T]) revertHardDelete(doc bson.Raw) func(context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := r.collection.InsertOne(ctx, doc)
+		return err
+	}
+}
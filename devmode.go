@@ -0,0 +1,72 @@
+package zendia
+
+import (
+	"log"
+	"net/http"
+	"runtime/debug"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetDevMode inclui a causa do erro e o stack trace nas respostas 500
+// Desligado por padrão. É uma ferramenta de desenvolvimento local: expõe detalhes
+// internos ao cliente, então nunca ligue em produção. Em gin.ReleaseMode um aviso é logado.
+//
+//	app.SetDevMode(os.Getenv("APP_ENV") == "local")
+func (z *Zendia) SetDevMode(enabled bool) {
+	if enabled && gin.Mode() == gin.ReleaseMode {
+		log.Printf("[ZENDIA] ⚠️  DevMode enabled in release mode: 500 responses include error details and stack traces")
+	}
+	z.devMode = enabled
+}
+
+// IsDevMode indica se as respostas 500 incluem causa e stack trace
+func (z *Zendia) IsDevMode() bool {
+	return z.devMode
+}
+
+// isDevMode indica se a instância Zendia da requisição está em DevMode
+func isDevMode(c *gin.Context) bool {
+	if val, exists := c.Get("zendia_instance"); exists {
+		if z, ok := val.(*Zendia); ok {
+			return z.devMode
+		}
+	}
+	return false
+}
+
+// addDevDetails adiciona a causa (em causeKey) e o stack trace ao corpo de erro
+func addDevDetails(body gin.H, causeKey string, cause error) {
+	if cause != nil {
+		body[causeKey] = cause.Error()
+	}
+	body[ResponseStack] = devStack()
+}
+
+// devStack stack trace da goroutine atual, uma linha por item
+// Chamado no recovery, ainda inclui os frames do panic
+func devStack() []string {
+	return strings.Split(strings.TrimSpace(string(debug.Stack())), "\n")
+}
+
+// internalFailure responde 500 com message; em DevMode inclui a causa e o stack trace
+func (c *Context[T]) internalFailure(message string, cause error) {
+	if c.zendia == nil || !c.zendia.devMode {
+		c.InternalError(message)
+		return
+	}
+
+	errorCode := c.errorCode
+	if errorCode == "" {
+		errorCode = ErrorCodeInternal
+	}
+	envelope := c.envelope()
+	response := gin.H{
+		envelope.Success:   false,
+		envelope.Message:   message,
+		envelope.ErrorCode: errorCode,
+	}
+	addDevDetails(response, envelope.Error, cause)
+	c.renderJSON(http.StatusInternalServerError, response)
+}
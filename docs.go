@@ -18,6 +18,8 @@ type SwaggerInfo struct {
 
 // SetupSwagger configura a documentação Swagger
 func (z *Zendia) SetupSwagger(info SwaggerInfo) {
+	z.features[FeatureSwagger] = true
+
 	// Só registra se ainda não foi registrado
 	if swag.GetSwagger(swag.Name) == nil {
 		swag.Register(swag.Name, &swag.Spec{
@@ -0,0 +1,30 @@
+package zendia
+
+// Map converte uma entidade em DTO com fn
+func Map[TIn, TOut any](in TIn, fn func(TIn) TOut) TOut {
+	return fn(in)
+}
+
+// MapSlice converte cada item da lista com fn; nil vira lista vazia (serializa como [])
+func MapSlice[TIn, TOut any](in []TIn, fn func(TIn) TOut) []TOut {
+	out := make([]TOut, len(in))
+	for i, item := range in {
+		out[i] = fn(item)
+	}
+	return out
+}
+
+// SuccessMapped responde Success com o DTO gerado por fn
+// É uma função (não método) porque métodos em Go não aceitam parâmetros de tipo:
+//
+//	zendia.SuccessMapped(c, "Usuário encontrado", user, toUserDTO)
+func SuccessMapped[T, TIn, TOut any](c *Context[T], message string, data TIn, fn func(TIn) TOut) {
+	c.Success(message, fn(data))
+}
+
+// SuccessMappedSlice responde Success com a lista convertida item a item por fn
+//
+//	zendia.SuccessMappedSlice(c, "Usuários encontrados", users, toUserDTO, total)
+func SuccessMappedSlice[T, TIn, TOut any](c *Context[T], message string, data []TIn, fn func(TIn) TOut, total ...int64) {
+	c.Success(message, MapSlice(data, fn), total...)
+}
@@ -0,0 +1,77 @@
+package zendia
+
+import "github.com/gin-gonic/gin"
+
+// ResponseEnvelopeConfig nomes das chaves do envelope JSON das respostas
+// Campos vazios mantêm o nome padrão (ResponseSuccess, ResponseMessage...)
+type ResponseEnvelopeConfig struct {
+	Success   string
+	Message   string
+	Data      string
+	Error     string
+	ErrorCode string
+	Total     string
+}
+
+// DefaultResponseEnvelope nomes padrão: success, message, data, error, error_code, total
+var DefaultResponseEnvelope = ResponseEnvelopeConfig{
+	Success:   ResponseSuccess,
+	Message:   ResponseMessage,
+	Data:      ResponseData,
+	Error:     ResponseError,
+	ErrorCode: ResponseErrorCode,
+	Total:     ResponseTotal,
+}
+
+// withDefaults preenche as chaves não configuradas com os nomes padrão
+func (e ResponseEnvelopeConfig) withDefaults() ResponseEnvelopeConfig {
+	if e.Success == "" {
+		e.Success = DefaultResponseEnvelope.Success
+	}
+	if e.Message == "" {
+		e.Message = DefaultResponseEnvelope.Message
+	}
+	if e.Data == "" {
+		e.Data = DefaultResponseEnvelope.Data
+	}
+	if e.Error == "" {
+		e.Error = DefaultResponseEnvelope.Error
+	}
+	if e.ErrorCode == "" {
+		e.ErrorCode = DefaultResponseEnvelope.ErrorCode
+	}
+	if e.Total == "" {
+		e.Total = DefaultResponseEnvelope.Total
+	}
+	return e
+}
+
+// SetResponseEnvelope renomeia as chaves do envelope das respostas
+// Vale para os helpers do Context, o DefaultErrorHandler e os middlewares do framework
+//
+// Uso:
+//
+//	app.SetResponseEnvelope(zendia.ResponseEnvelopeConfig{Success: "status", Data: "payload"})
+func (z *Zendia) SetResponseEnvelope(config ResponseEnvelopeConfig) {
+	z.envelope = config.withDefaults()
+}
+
+// envelopeFromGin envelope da instância Zendia da requisição, ou o padrão
+func envelopeFromGin(c *gin.Context) ResponseEnvelopeConfig {
+	if val, exists := c.Get("zendia_instance"); exists {
+		if z, ok := val.(*Zendia); ok {
+			return z.envelope
+		}
+	}
+	return DefaultResponseEnvelope
+}
+
+// errorEnvelope monta o corpo de erro padrão para respostas abortadas em middlewares
+func errorEnvelope(c *gin.Context, apiErr *APIError) gin.H {
+	envelope := envelopeFromGin(c)
+	return gin.H{
+		envelope.Success:   false,
+		envelope.Message:   apiErr.Message,
+		envelope.ErrorCode: apiErr.ErrorCode,
+	}
+}
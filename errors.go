@@ -1,6 +1,8 @@
 package zendia
 
 import (
+	"context"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -16,27 +18,49 @@ const (
 	InternalErrorType
 	BadRequestErrorType
 	ConflictErrorType
+	UnsupportedMediaTypeErrorType
+	ClientClosedRequestErrorType
+	UpgradeRequiredErrorType
+	GatewayTimeoutErrorType
+	TooManyRequestsErrorType
 )
 
 // APIError representa um erro da API
 type APIError struct {
-	Type    ErrorType `json:"-"`
-	Message string    `json:"message"`
-	Details error     `json:"details,omitempty"`
-	Code    int       `json:"code"`
+	Type      ErrorType `json:"-"`
+	Message   string    `json:"message"`
+	Details   error     `json:"-"` // causa original, apenas para logs
+	Code      int       `json:"code"`
+	ErrorCode string    `json:"error_code"` // discriminador estável para o cliente (ex: VALIDATION_FAILED)
 }
 
 func (e *APIError) Error() string {
 	return e.Message
 }
 
+// Unwrap expõe o erro original para errors.Is/errors.As
+func (e *APIError) Unwrap() error {
+	return e.Details
+}
+
 // ErrorHandler interface para manipulação de erros
 type ErrorHandler interface {
 	Handle(c *gin.Context, err error)
 }
 
+// ErrorHandlerFunc adapta uma função comum para ErrorHandler
+type ErrorHandlerFunc func(c *gin.Context, err error)
+
+// Handle chama f(c, err)
+func (f ErrorHandlerFunc) Handle(c *gin.Context, err error) {
+	f(c, err)
+}
+
 // DefaultErrorHandler implementação padrão do manipulador de erros
-type DefaultErrorHandler struct{}
+// Com DevMode (ou Zendia.SetDevMode) respostas 500 incluem a causa e o stack trace
+type DefaultErrorHandler struct {
+	DevMode bool
+}
 
 // NewErrorHandler cria um novo manipulador de erros
 func NewErrorHandler() ErrorHandler {
@@ -45,82 +69,177 @@ func NewErrorHandler() ErrorHandler {
 
 // Handle processa erros e retorna respostas apropriadas
 func (h *DefaultErrorHandler) Handle(c *gin.Context, err error) {
+	envelope := envelopeFromGin(c)
+	devMode := h.DevMode || isDevMode(c)
 	if apiErr, ok := err.(*APIError); ok {
-		c.JSON(apiErr.Code, gin.H{
-			"success": false,
-			"error":   apiErr.Message,
-		})
+		body := gin.H{
+			envelope.Success:   false,
+			envelope.Error:     apiErr.Message,
+			envelope.ErrorCode: apiErr.ErrorCode,
+		}
+		var fields FieldErrors
+		if apiErr.Type == ValidationErrorType && errors.As(apiErr.Details, &fields) {
+			body[ResponseFields] = fields
+		}
+		// A mensagem já vai em "error"; a causa vai em "message"
+		if devMode && apiErr.Code == http.StatusInternalServerError {
+			addDevDetails(body, envelope.Message, apiErr.Details)
+		}
+		c.JSON(apiErr.Code, body)
 		return
 	}
 	
 	// Erro genérico
-	c.JSON(http.StatusInternalServerError, gin.H{
-		"success": false,
-		"error":   "Internal server error",
-	})
+	body := gin.H{
+		envelope.Success:   false,
+		envelope.Error:     MsgInternalServerError,
+		envelope.ErrorCode: ErrorCodeInternal,
+	}
+	if devMode {
+		addDevDetails(body, envelope.Message, err)
+	}
+	c.JSON(http.StatusInternalServerError, body)
 }
 
 // NewValidationError cria um erro de validação
 func NewValidationError(message string, details error) *APIError {
 	return &APIError{
-		Type:    ValidationErrorType,
-		Message: message,
-		Details: details,
-		Code:    http.StatusBadRequest,
+		Type:      ValidationErrorType,
+		Message:   message,
+		Details:   details,
+		Code:      http.StatusBadRequest,
+		ErrorCode: ErrorCodeValidation,
 	}
 }
 
 // NewNotFoundError cria um erro de recurso não encontrado
 func NewNotFoundError(message string) *APIError {
 	return &APIError{
-		Type:    NotFoundErrorType,
-		Message: message,
-		Code:    http.StatusNotFound,
+		Type:      NotFoundErrorType,
+		Message:   message,
+		Code:      http.StatusNotFound,
+		ErrorCode: ErrorCodeNotFound,
 	}
 }
 
 // NewUnauthorizedError cria um erro de não autorizado
 func NewUnauthorizedError(message string) *APIError {
 	return &APIError{
-		Type:    UnauthorizedErrorType,
-		Message: message,
-		Code:    http.StatusUnauthorized,
+		Type:      UnauthorizedErrorType,
+		Message:   message,
+		Code:      http.StatusUnauthorized,
+		ErrorCode: ErrorCodeUnauthorized,
 	}
 }
 
 // NewInternalError cria um erro interno
 func NewInternalError(message string) *APIError {
 	return &APIError{
-		Type:    InternalErrorType,
-		Message: message,
-		Code:    http.StatusInternalServerError,
+		Type:      InternalErrorType,
+		Message:   message,
+		Code:      http.StatusInternalServerError,
+		ErrorCode: ErrorCodeInternal,
+	}
+}
+
+// internalError cria um erro interno com mensagem genérica para o cliente
+// O erro original (ex: driver) fica em Details para log e errors.Is/As, sem ir para a resposta.
+// Cancelamento pelo cliente vira 499 e prazo estourado (ex: WithOperationTimeout) vira 504.
+func internalError(message string, err error) *APIError {
+	if errors.Is(err, context.Canceled) {
+		return NewClientClosedRequestError(MsgRequestCanceled)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		apiErr := NewGatewayTimeoutError(MsgOperationTimedOut)
+		apiErr.Details = err
+		return apiErr
+	}
+	return &APIError{
+		Type:      InternalErrorType,
+		Message:   message,
+		Details:   err,
+		Code:      http.StatusInternalServerError,
+		ErrorCode: ErrorCodeInternal,
 	}
 }
 
 // NewBadRequestError cria um erro de requisição inválida
 func NewBadRequestError(message string) *APIError {
 	return &APIError{
-		Type:    BadRequestErrorType,
-		Message: message,
-		Code:    http.StatusBadRequest,
+		Type:      BadRequestErrorType,
+		Message:   message,
+		Code:      http.StatusBadRequest,
+		ErrorCode: ErrorCodeBadRequest,
 	}
 }
 
 // NewConflictError cria um erro de conflito (409)
 func NewConflictError(message string) *APIError {
 	return &APIError{
-		Type:    ConflictErrorType,
-		Message: message,
-		Code:    http.StatusConflict,
+		Type:      ConflictErrorType,
+		Message:   message,
+		Code:      http.StatusConflict,
+		ErrorCode: ErrorCodeConflict,
+	}
+}
+
+// NewUnsupportedMediaTypeError cria um erro de Content-Type não suportado (415)
+func NewUnsupportedMediaTypeError(message string) *APIError {
+	return &APIError{
+		Type:      UnsupportedMediaTypeErrorType,
+		Message:   message,
+		Code:      http.StatusUnsupportedMediaType,
+		ErrorCode: ErrorCodeUnsupportedMediaType,
+	}
+}
+
+// NewClientClosedRequestError cria um erro de requisição cancelada pelo cliente (499)
+func NewClientClosedRequestError(message string) *APIError {
+	return &APIError{
+		Type:      ClientClosedRequestErrorType,
+		Message:   message,
+		Code:      StatusClientClosedRequest,
+		ErrorCode: ErrorCodeClientClosedRequest,
+	}
+}
+
+// NewUpgradeRequiredError cria um erro de versão de cliente antiga demais (426)
+func NewUpgradeRequiredError(message string) *APIError {
+	return &APIError{
+		Type:      UpgradeRequiredErrorType,
+		Message:   message,
+		Code:      http.StatusUpgradeRequired,
+		ErrorCode: ErrorCodeUpgradeRequired,
+	}
+}
+
+// NewGatewayTimeoutError cria um erro de operação que estourou o prazo (504)
+func NewGatewayTimeoutError(message string) *APIError {
+	return &APIError{
+		Type:      GatewayTimeoutErrorType,
+		Message:   message,
+		Code:      http.StatusGatewayTimeout,
+		ErrorCode: ErrorCodeGatewayTimeout,
+	}
+}
+
+// NewTooManyRequestsError cria um erro de limite de requisições excedido (429)
+func NewTooManyRequestsError(message string) *APIError {
+	return &APIError{
+		Type:      TooManyRequestsErrorType,
+		Message:   message,
+		Code:      http.StatusTooManyRequests,
+		ErrorCode: ErrorCodeTooManyRequests,
 	}
 }
 
 // NewForbiddenError cria um erro de proibido (403)
 func NewForbiddenError(message string) *APIError {
 	return &APIError{
-		Type:    UnauthorizedErrorType,
-		Message: message,
-		Code:    http.StatusForbidden,
+		Type:      UnauthorizedErrorType,
+		Message:   message,
+		Code:      http.StatusForbidden,
+		ErrorCode: ErrorCodeForbidden,
 	}
 }
 
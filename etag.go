@@ -0,0 +1,74 @@
+package zendia
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// SetETag grava o header ETag da resposta (etag vazio é ignorado)
+func (c *Context[T]) SetETag(etag string) {
+	if etag != "" {
+		c.Header("ETag", etag)
+	}
+}
+
+// NotModified responde 304 quando o If-None-Match do cliente casa com etag
+// Retorna true se a resposta já foi escrita e o handler deve só retornar nil; senão
+// grava o ETag e segue. Etag vazio (ex: listagem fora do cache) nunca casa.
+//
+//	if c.NotModified(userRepo.ListETag(ctx)) {
+//	    return nil // 304 sem ler a listagem do cache
+//	}
+//	users, err := userRepo.GetAll(ctx, nil)
+//	if err != nil {
+//	    return err
+//	}
+//	c.SetETag(userRepo.ListETag(ctx)) // ETag da listagem recém populada
+//	c.Success(zendia.MsgRetrievedSuccess, users)
+func (c *Context[T]) NotModified(etag string) bool {
+	if etag == "" {
+		return false
+	}
+	c.SetETag(etag)
+	if !etagMatches(c.GetHeader("If-None-Match"), etag) {
+		return false
+	}
+	c.AbortWithStatus(http.StatusNotModified)
+	return true
+}
+
+// etagMatches comparação fraca do If-None-Match (lista, W/ ou *) com etag
+func etagMatches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// ListETag ETag da listagem do tenant em cache (GetAll sem filtros), ou "" fora do cache
+// Deriva da chave da listagem e da versão gravada junto com ela, então só lê uma entrada
+// pequena, nunca a lista. Toda escrita pelo CachedRepository troca a versão.
+func (cr *CachedRepository[T]) ListETag(ctx context.Context) string {
+	key, ok := cr.listKey(ctx)
+	if !ok {
+		return ""
+	}
+	version, found := cr.cache.Get(ctx, listVersionKey(key))
+	if !found {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(key + ":" + string(version)))
+	return `W/"` + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// listVersionKey chave da versão da listagem em cache
+func listVersionKey(listKey string) string {
+	return listKey + ":version"
+}
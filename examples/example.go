@@ -7,8 +7,6 @@ import (
 
 	zendia "github.com/azzidev/zendiaframework"
 	"github.com/google/uuid"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // User entidade de exemplo
@@ -26,7 +24,8 @@ type User struct {
 
 func (u *User) GetID() uuid.UUID                    { return u.ID }
 func (u *User) SetID(id uuid.UUID)                  { u.ID = id }
-func (u *User) SetTenantID(s string)                { u.TenantID = uuid.MustParse(s) }
+func (u *User) GetTenantID() string                 { return u.TenantID.String() }
+func (u *User) SetTenantID(s string)                { u.TenantID, _ = uuid.Parse(s) }
 func (u *User) SetCreated(info zendia.AuditInfo)     { u.Created = info }
 func (u *User) SetUpdated(info zendia.AuditInfo)     { u.Updated = info }
 func (u *User) SetDeleted(info zendia.AuditInfo)     { u.Deleted = info }
@@ -41,10 +40,13 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017"))
+	client, cleanup, err := zendia.NewMongoClient(ctx, zendia.MongoConnectConfig{
+		URI: "mongodb://localhost:27017",
+	})
 	if err != nil {
 		log.Fatal("MongoDB connection failed:", err)
 	}
+	defer cleanup()
 	db := client.Database("zendia_example")
 
 	// Repository simples (sem auditoria, sem histórico)
@@ -108,9 +110,9 @@ func main() {
 
 	// Get user
 	api.GET("/users/:id", zendia.Handle(func(c *zendia.Context[any]) error {
-		id, err := uuid.Parse(c.Context.Param("id"))
+		id, err := c.ParamUUID("id")
 		if err != nil {
-			return zendia.NewBadRequestError("ID inválido")
+			return err
 		}
 
 		user, err := userRepo.GetByID(c.Request.Context(), id)
@@ -124,20 +126,25 @@ func main() {
 
 	// List users
 	api.GET("/users", zendia.Handle(func(c *zendia.Context[any]) error {
-		users, err := userRepo.GetAll(c.Request.Context(), map[string]interface{}{})
+		skip, take, err := c.Pagination(10, 100)
 		if err != nil {
 			return err
 		}
 
-		c.Success("Usuários encontrados", users)
+		users, total, err := userRepo.GetAllSkipTake(c.Request.Context(), map[string]interface{}{}, zendia.Pagination{Skip: skip, Take: take})
+		if err != nil {
+			return err
+		}
+
+		c.Success("Usuários encontrados", users, total)
 		return nil
 	}))
 
 	// Get history
 	api.GET("/users/:id/history", zendia.Handle(func(c *zendia.Context[any]) error {
-		id, err := uuid.Parse(c.Context.Param("id"))
+		id, err := c.ParamUUID("id")
 		if err != nil {
-			return zendia.NewBadRequestError("ID inválido")
+			return err
 		}
 
 		history, err := userRepo.GetHistory(c.Request.Context(), id)
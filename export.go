@@ -0,0 +1,110 @@
+package zendia
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// exportFlushEvery documentos escritos entre flushes do writer
+const exportFlushEvery = 100
+
+// ExportOptions opções do ExportTenant
+type ExportOptions struct {
+	IncludeDeleted bool // Inclui registros soft deleted (active=false)
+}
+
+// ExportTenant escreve todos os documentos do tenant do contexto em w como NDJSON
+// (um JSON por linha), iterando o cursor sem carregar a collection em memória.
+// Exige WithAudit e tenant no contexto: sem eles não há como garantir o isolamento.
+// Retorna quantos documentos foram escritos.
+//
+//	count, err := repo.ExportTenant(ctx, file, zendia.ExportOptions{IncludeDeleted: true})
+func (r *Repository[T]) ExportTenant(ctx context.Context, w io.Writer, opts ExportOptions) (int64, error) {
+	if !r.config.audit {
+		return 0, NewBadRequestError(MsgExportRequiresAudit)
+	}
+	if GetTenantID(ctx) == "" {
+		return 0, NewUnauthorizedError(MsgLoginRequired)
+	}
+
+	filter := bson.M{}
+	if !opts.IncludeDeleted {
+		filter["active"] = true
+	}
+	if err := r.injectTenantFilter(ctx, filter); err != nil {
+		return 0, err
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return 0, internalError("Failed to export entities", err)
+	}
+	defer cursor.Close(context.Background())
+
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	var count int64
+	for cursor.Next(ctx) {
+		var entity T
+		if err := cursor.Decode(&entity); err != nil {
+			return count, internalError("Failed to decode entities", err)
+		}
+		// Confere o tenant também no documento, além do filtro da query
+		if err := checkEntityTenant(ctx, entity); err != nil {
+			return count, internalError("Failed to export entities", err)
+		}
+		if err := encoder.Encode(entity); err != nil {
+			return count, internalError("Failed to write export", err)
+		}
+		count++
+		if flusher != nil && count%exportFlushEvery == 0 {
+			flusher.Flush()
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return count, internalError("Failed to export entities", err)
+	}
+	return count, nil
+}
+
+// AddExportEndpoint registra GET /export, que baixa os dados do tenant do contexto em NDJSON
+// ?include_deleted=true inclui os registros soft deleted. O download é em streaming;
+// um erro no meio do envio só pode ser logado, pois o status 200 já foi enviado.
+//
+// Uso:
+//
+//	users := api.Group("/users")
+//	zendia.AddExportEndpoint(users, userRepo) // GET /users/export
+func AddExportEndpoint[T MongoAuditableEntity](rg *RouteGroup, repo *Repository[T]) {
+	rg.GET("/export", Handle(func(c *Context[any]) error {
+		ctx := c.Request.Context()
+		tenantID := GetTenantID(ctx)
+		if tenantID == "" {
+			return NewUnauthorizedError(MsgLoginRequired)
+		}
+		includeDeleted, _ := strconv.ParseBool(c.Query("include_deleted"))
+
+		c.Header("Content-Type", "application/x-ndjson")
+		c.Header("Content-Disposition", `attachment; filename="export-`+tenantID+`.ndjson"`)
+
+		count, err := repo.ExportTenant(ctx, c.Writer, ExportOptions{IncludeDeleted: includeDeleted})
+		if err != nil {
+			if c.Writer.Written() {
+				log.Printf("[ZENDIA] tenant export interrupted after %d documents: %v", count, err)
+			} else {
+				// Nada foi enviado: a resposta de erro sai no envelope JSON padrão
+				c.Writer.Header().Del("Content-Type")
+				c.Writer.Header().Del("Content-Disposition")
+			}
+			return err
+		}
+		return nil
+	}))
+}
@@ -0,0 +1,198 @@
+package zendia
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// Fields lê o ?fields=id,name da resposta parcial, validando contra allowed
+// Sem o query param retorna nil (representação completa). Campos com ponto selecionam
+// objetos aninhados ("created.set_at"). Campo inválido ou fora de allowed responde 400;
+// allowed é obrigatório para que campos internos não possam ser pedidos.
+//
+//	fields, err := c.Fields("id", "name", "email")
+//	if err != nil {
+//	    return err
+//	}
+//	users, total, err := repo.GetAllSkipTake(ctx, filters, page, &zendia.QueryOptions{
+//	    Projection: zendia.FieldsProjection[*User](fields),
+//	})
+//	...
+//	c.SuccessFields(zendia.MsgRetrievedSuccess, users, fields, "password_hash")
+func (c *Context[T]) Fields(allowed ...string) ([]string, error) {
+	raw, ok := c.GetQuery(QueryFields)
+	if !ok {
+		return nil, nil
+	}
+
+	permitted := make(map[string]bool, len(allowed))
+	for _, field := range allowed {
+		permitted[field] = true
+	}
+
+	var fields []string
+	seen := map[string]bool{}
+	for _, part := range strings.Split(raw, ",") {
+		field := strings.TrimSpace(part)
+		if field == "" || seen[field] {
+			continue
+		}
+		if !isValidFieldName(field) || !permitted[field] {
+			return nil, NewBadRequestError(MsgInvalidSelectField + ": " + sanitizeLogValue(field))
+		}
+		seen[field] = true
+		fields = append(fields, field)
+	}
+	if len(fields) == 0 {
+		return nil, NewBadRequestError(MsgInvalidSelectField)
+	}
+	return fields, nil
+}
+
+// SuccessFields retorna uma resposta de sucesso só com os campos JSON de fields
+// Listas são filtradas item a item; fields vazio mantém a representação completa.
+// O mask é aplicado depois da seleção, então um campo mascarado nunca volta por ?fields=.
+func (c *Context[T]) SuccessFields(message string, data interface{}, fields []string, mask ...string) {
+	selected, err := selectFields(c.marshaler(), data, fields)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	c.SuccessMasked(message, selected, mask...)
+}
+
+// FieldsProjection projeção do MongoDB para os campos JSON de fields (nil sem campos)
+// Converte os nomes das tags json de T para os das tags bson ("id" → "_id"), inclusive
+// em structs aninhadas e embutidas com inline. Use em QueryOptions.Projection.
+func FieldsProjection[T any](fields []string) map[string]interface{} {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	projection := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		projection[bsonPath(t, strings.Split(field, "."))] = 1
+	}
+	return projection
+}
+
+// bsonPath converte o caminho de nomes json para o caminho de nomes bson em t
+// Segmentos sem campo correspondente são mantidos como estão
+func bsonPath(t reflect.Type, path []string) string {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return strings.Join(path, ".")
+	}
+
+	field, ok := fieldByJSONName(t, path[0])
+	if !ok {
+		return strings.Join(path, ".")
+	}
+	name := bsonName(field)
+	if len(path) == 1 {
+		return name
+	}
+	return name + "." + bsonPath(field.Type, path[1:])
+}
+
+// fieldByJSONName busca o campo de t com o nome json, descendo em structs embutidas
+func fieldByJSONName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		jsonName := strings.Split(field.Tag.Get("json"), ",")[0]
+		if jsonName == "-" {
+			continue
+		}
+		if field.Anonymous && jsonName == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if found, ok := fieldByJSONName(embedded, name); ok {
+					return found, true
+				}
+			}
+			continue
+		}
+		if jsonName == "" {
+			jsonName = field.Name
+		}
+		if jsonName == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// bsonName nome do campo no documento: a tag bson ou o nome Go em minúsculas (padrão do driver)
+func bsonName(field reflect.StructField) string {
+	if name := strings.Split(field.Tag.Get("bson"), ",")[0]; name != "" && name != "-" {
+		return name
+	}
+	return strings.ToLower(field.Name)
+}
+
+// selectFields serializa data e mantém só os caminhos de fields na representação genérica
+func selectFields(marshal func(interface{}) ([]byte, error), data interface{}, fields []string) (interface{}, error) {
+	if len(fields) == 0 {
+		return data, nil
+	}
+
+	raw, err := marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	paths := make([][]string, len(fields))
+	for i, field := range fields {
+		paths[i] = strings.Split(field, ".")
+	}
+	return keepPaths(generic, paths), nil
+}
+
+// keepPaths mantém em objetos (e em cada item de arrays) só os caminhos de paths
+func keepPaths(value interface{}, paths [][]string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		nested := map[string][][]string{}
+		whole := map[string]bool{}
+		for _, path := range paths {
+			if len(path) == 1 {
+				whole[path[0]] = true
+			} else {
+				nested[path[0]] = append(nested[path[0]], path[1:])
+			}
+		}
+
+		out := make(map[string]interface{}, len(whole)+len(nested))
+		for key, child := range v {
+			if whole[key] {
+				out[key] = child
+			} else if sub, ok := nested[key]; ok {
+				out[key] = keepPaths(child, sub)
+			}
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = keepPaths(item, paths)
+		}
+		return out
+	}
+	return value
+}
@@ -0,0 +1,152 @@
+package zendia
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// FilterGuard limites de complexidade para filtros e query params vindos do usuário
+// Centraliza as regras anti-DoS aplicadas pelo InputSanitizer e pelo FilterGuardMiddleware.
+// Campos zerados usam DefaultFilterGuard.
+type FilterGuard struct {
+	MaxKeys        int // Total de chaves, somando objetos aninhados
+	MaxValueLength int // Tamanho máximo de cada string
+	MaxArrayLength int // Itens por array, inclusive no nível raiz
+	MaxDepth       int // Níveis de objetos/arrays aninhados abaixo da raiz
+}
+
+// DefaultFilterGuard limites padrão: 20 chaves, strings de 1000, arrays de 100, 3 níveis
+var DefaultFilterGuard = FilterGuard{
+	MaxKeys:        20,
+	MaxValueLength: 1000,
+	MaxArrayLength: 100,
+	MaxDepth:       3,
+}
+
+func (g FilterGuard) withDefaults() FilterGuard {
+	if g.MaxKeys <= 0 {
+		g.MaxKeys = DefaultFilterGuard.MaxKeys
+	}
+	if g.MaxValueLength <= 0 {
+		g.MaxValueLength = DefaultFilterGuard.MaxValueLength
+	}
+	if g.MaxArrayLength <= 0 {
+		g.MaxArrayLength = DefaultFilterGuard.MaxArrayLength
+	}
+	if g.MaxDepth <= 0 {
+		g.MaxDepth = DefaultFilterGuard.MaxDepth
+	}
+	return g
+}
+
+// Validate confere os filtros contra os limites antes de chegarem ao repository
+// Retorna BadRequest descrevendo o primeiro limite excedido
+//
+//	if err := zendia.DefaultFilterGuard.Validate(filters); err != nil {
+//		return err
+//	}
+func (g FilterGuard) Validate(filters map[string]interface{}) error {
+	g = g.withDefaults()
+	keys := 0
+	return g.validateObject(filters, 0, &keys)
+}
+
+func (g FilterGuard) validateObject(obj map[string]interface{}, depth int, keys *int) error {
+	*keys += len(obj)
+	if *keys > g.MaxKeys {
+		return filterTooComplex("too many keys (max %d)", g.MaxKeys)
+	}
+	for key, value := range obj {
+		if len(key) > g.MaxValueLength {
+			return filterTooComplex("key too long (max %d)", g.MaxValueLength)
+		}
+		if err := g.validateValue(value, depth, keys); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g FilterGuard) validateValue(value interface{}, depth int, keys *int) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		if len(v) > g.MaxValueLength {
+			return filterTooComplex("value too long (max %d)", g.MaxValueLength)
+		}
+		return nil
+	case map[string]interface{}:
+		if depth+1 > g.MaxDepth {
+			return filterTooComplex("nesting too deep (max %d)", g.MaxDepth)
+		}
+		return g.validateObject(v, depth+1, keys)
+	case bson.M:
+		return g.validateValue(map[string]interface{}(v), depth, keys)
+	}
+
+	val := reflect.ValueOf(value)
+	switch val.Kind() {
+	case reflect.Slice, reflect.Array:
+		if val.Kind() == reflect.Array && val.Type().Elem().Kind() == reflect.Uint8 {
+			return nil // ex: uuid.UUID
+		}
+		if val.Len() > g.MaxArrayLength {
+			return filterTooComplex("array too large (max %d)", g.MaxArrayLength)
+		}
+		if depth+1 > g.MaxDepth {
+			return filterTooComplex("nesting too deep (max %d)", g.MaxDepth)
+		}
+		for i := 0; i < val.Len(); i++ {
+			if err := g.validateValue(val.Index(i).Interface(), depth+1, keys); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		// Outros tipos de map nomeados (ex: map[string]string) seguem as mesmas regras de objeto
+		if val.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		obj := make(map[string]interface{}, val.Len())
+		iter := val.MapRange()
+		for iter.Next() {
+			obj[iter.Key().String()] = iter.Value().Interface()
+		}
+		return g.validateValue(obj, depth, keys)
+	}
+	return nil
+}
+
+// FilterGuardMiddleware aplica os limites de chaves, tamanho e repetição aos query params
+// Handlers que montam filtros a partir da query recebem entradas já dentro dos limites
+func FilterGuardMiddleware(guard FilterGuard) gin.HandlerFunc {
+	guard = guard.withDefaults()
+
+	return func(c *gin.Context) {
+		query := c.Request.URL.Query()
+
+		filters := make(map[string]interface{}, len(query))
+		for key, values := range query {
+			if len(values) == 1 {
+				filters[key] = values[0]
+			} else {
+				filters[key] = values
+			}
+		}
+
+		if err := guard.Validate(filters); err != nil {
+			apiErr := err.(*APIError)
+			c.AbortWithStatusJSON(apiErr.Code, errorEnvelope(c, apiErr))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func filterTooComplex(format string, limit int) *APIError {
+	return NewBadRequestError(MsgFilterTooComplex + ": " + fmt.Sprintf(format, limit))
+}
@@ -18,15 +18,21 @@ type FirebaseAuthConfig struct {
 }
 
 // SetupFirebaseAuth configura autenticação Firebase no framework
+// Só pode haver um setup de auth por instância: uma segunda chamada instalaria outro
+// middleware global, verificando o token duas vezes, e entra em panic já no startup.
 func (z *Zendia) SetupFirebaseAuth(config FirebaseAuthConfig) {
+	if z.firebaseAuthConfig != nil {
+		panic("zendia: auth already configured: SetupFirebaseAuth can be called only once per instance")
+	}
 	z.firebaseAuthConfig = &config
+	z.PublicRoutes(config.PublicRoutes...)
 	z.Use(z.firebaseAuthMiddleware())
 }
 
 // firebaseAuthMiddleware middleware para validação de tokens Firebase
 func (z *Zendia) firebaseAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if z.isFirebasePublicRoute(c.Request.URL.Path) {
+		if z.IsPublicRoute(c.Request.URL.Path) {
 			c.Next()
 			return
 		}
@@ -34,7 +40,7 @@ func (z *Zendia) firebaseAuthMiddleware() gin.HandlerFunc {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			err := NewUnauthorizedError("Token de autenticação obrigatório")
-			c.JSON(err.Code, gin.H{"success": false, "error": err.Message})
+			c.JSON(err.Code, gin.H{z.envelope.Success: false, z.envelope.Error: err.Message})
 			c.Abort()
 			return
 		}
@@ -45,7 +51,7 @@ func (z *Zendia) firebaseAuthMiddleware() gin.HandlerFunc {
 		if err != nil {
 			log.Printf("Firebase token verification failed: %v", err)
 			apiErr := NewUnauthorizedError("Token inválido ou expirado")
-			c.JSON(apiErr.Code, gin.H{"success": false, "error": apiErr.Message})
+			c.JSON(apiErr.Code, gin.H{z.envelope.Success: false, z.envelope.Error: apiErr.Message})
 			c.Abort()
 			return
 		}
@@ -60,13 +66,14 @@ func (z *Zendia) firebaseAuthMiddleware() gin.HandlerFunc {
 		if tenantID, ok := token.Claims[ClaimTenantID].(string); ok && tenantID != "" {
 			if sanitizedTenantID := sanitizeHeaderValue(tenantID); sanitizedTenantID != "" {
 				c.Set(AuthTenantIDKey, sanitizedTenantID)
+				setIdentity(c, TenantIDKey, sanitizedTenantID, TenantSourceClaim)
 				c.Header(HeaderTenantID, sanitizedTenantID)
 			}
 		}
 		if userID, ok := token.Claims[ClaimUserUUID].(string); ok && userID != "" {
 			if sanitizedUserID := sanitizeHeaderValue(userID); sanitizedUserID != "" {
 				c.Set(AuthUserIDKey, sanitizedUserID)
-				c.Set(UserIDKey, sanitizedUserID)
+				setIdentity(c, UserIDKey, sanitizedUserID, TenantSourceClaim)
 				c.Header(HeaderUserID, sanitizedUserID)
 			}
 		}
@@ -74,45 +81,27 @@ func (z *Zendia) firebaseAuthMiddleware() gin.HandlerFunc {
 		if name, ok := token.Claims[ClaimUserName].(string); ok && name != "" {
 			if sanitizedName := sanitizeHeaderValue(name); sanitizedName != "" {
 				c.Set(AuthNameKey, sanitizedName)
-				c.Set(UserNameKey, sanitizedName)
+				setIdentity(c, UserNameKey, sanitizedName, TenantSourceClaim)
 				c.Header(HeaderUserName, sanitizedName)
 			}
 		}
 
+		if role, ok := token.Claims[ClaimRole].(string); ok && role != "" {
+			if sanitizedRole := sanitizeHeaderValue(role); sanitizedRole != "" {
+				c.Set(AuthRoleKey, sanitizedRole)
+			}
+		}
+
+		fixActionAt(c)
+
 		ctx := context.WithValue(c.Request.Context(), ContextFirebaseUID, firebaseUID)
 		ctx = context.WithValue(ctx, ContextEmail, email)
-		if tenantID, exists := c.Get(AuthTenantIDKey); exists {
-			ctx = context.WithValue(ctx, TenantIDKey, tenantID)
-		}
-		if userID, exists := c.Get(AuthUserIDKey); exists {
-			ctx = context.WithValue(ctx, UserIDKey, userID)
-		}
-		if userName, exists := c.Get(AuthNameKey); exists {
-			ctx = context.WithValue(ctx, UserNameKey, userName)
-		}
 		c.Request = c.Request.WithContext(ctx)
 
 		c.Next()
 	}
 }
 
-// isFirebasePublicRoute verifica se a rota é pública (não precisa de auth)
-func (z *Zendia) isFirebasePublicRoute(path string) bool {
-	if z.firebaseAuthConfig == nil {
-		return true
-	}
-
-	publicRoutes := []string{"/health", "/docs", "/swagger"}
-	publicRoutes = append(publicRoutes, z.firebaseAuthConfig.PublicRoutes...)
-
-	for _, route := range publicRoutes {
-		if strings.HasPrefix(path, route) {
-			return true
-		}
-	}
-	return false
-}
-
 // GetAuthUser retorna informações do usuário autenticado
 func (c *Context[T]) GetAuthUser() *AuthUser {
 	return &AuthUser{
@@ -121,9 +110,21 @@ func (c *Context[T]) GetAuthUser() *AuthUser {
 		Email:       c.GetString(AuthEmailKey),
 		Name:        c.GetString(AuthNameKey),
 		TenantID:    c.GetString(AuthTenantIDKey),
+		Role:        c.GetString(AuthRoleKey),
 	}
 }
 
+// HasRole indica se o usuário autenticado tem uma das roles (custom claim ClaimRole)
+func (c *Context[T]) HasRole(roles ...string) bool {
+	role := c.GetString(AuthRoleKey)
+	for _, allowed := range roles {
+		if role != "" && role == allowed {
+			return true
+		}
+	}
+	return false
+}
+
 // sanitizeHeaderValue sanitiza valores de header para prevenir XSS
 func sanitizeHeaderValue(value string) string {
 	value = html.EscapeString(value)
@@ -143,4 +144,5 @@ type AuthUser struct {
 	Email       string `json:"email"`
 	Name        string `json:"name"`
 	TenantID    string `json:"tenant_id"`
+	Role        string `json:"role,omitempty"`
 }
@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"net/http"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/gin-gonic/gin"
 )
 
 // HealthStatus representa o status de saúde
@@ -29,12 +32,20 @@ type HealthCheckResult struct {
 	Status  HealthStatus `json:"status"`
 	Message string       `json:"message,omitempty"`
 	Details interface{}  `json:"details,omitempty"`
+
+	// Dependencies status das dependências declaradas em AddCheck
+	Dependencies map[string]HealthStatus `json:"dependencies,omitempty"`
+	// Skipped indica que o check não foi executado porque uma dependência está DOWN
+	Skipped bool `json:"skipped,omitempty"`
 }
 
 // HealthManager gerencia verificações de saúde
 type HealthManager struct {
-	mu     sync.RWMutex
-	checks map[string]HealthCheck
+	mu      sync.RWMutex
+	checks  map[string]HealthCheck
+	deps    map[string][]string
+	metrics *HealthMetrics
+	sinks   []HealthMetricsSink
 }
 
 // DatabaseHealthCheck verificação de saúde do banco de dados
@@ -53,6 +64,37 @@ type HTTPHealthCheck struct {
 	name    string
 	url     string
 	timeout time.Duration
+	retries int
+	backoff time.Duration
+	breaker *CircuitBreaker
+}
+
+// HTTPHealthCheckOption configura o HTTPHealthCheck
+type HTTPHealthCheckOption func(*HTTPHealthCheck)
+
+// WithHTTPRetries refaz a requisição até retries vezes antes de reportar DOWN
+// A espera entre tentativas cresce linearmente (backoff, 2×backoff...); backoff <= 0 usa DefaultHTTPHealthCheckBackoff
+func WithHTTPRetries(retries int, backoff time.Duration) HTTPHealthCheckOption {
+	return func(h *HTTPHealthCheck) {
+		if retries < 0 {
+			retries = 0
+		}
+		if backoff <= 0 {
+			backoff = DefaultHTTPHealthCheckBackoff
+		}
+		h.retries = retries
+		h.backoff = backoff
+	}
+}
+
+// WithHTTPCircuitBreaker interrompe as requisições depois de falhas seguidas do serviço
+// Com o circuito aberto o check reporta DOWN sem chamar o serviço até o cooldown passar;
+// então uma única requisição de teste decide se fecha ou reabre. Cada Check (com suas
+// tentativas) conta como uma chamada. O estado vai em Details["circuit_state"]
+func WithHTTPCircuitBreaker(breaker *CircuitBreaker) HTTPHealthCheckOption {
+	return func(h *HTTPHealthCheck) {
+		h.breaker = breaker
+	}
 }
 
 // RepositoryHealthCheck verifica saúde do repository
@@ -61,18 +103,34 @@ type RepositoryHealthCheck struct {
 	repo interface{}
 }
 
+// MetricsPersistenceHealthCheck verifica se a persistência de métricas continua salvando
+type MetricsPersistenceHealthCheck struct {
+	metrics *Metrics
+}
+
 // NewHealthManager cria um novo gerenciador de saúde
 func NewHealthManager() *HealthManager {
 	return &HealthManager{
 		checks: make(map[string]HealthCheck),
+		deps:   make(map[string][]string),
 	}
 }
 
 // AddCheck adiciona uma verificação de saúde
-func (hm *HealthManager) AddCheck(check HealthCheck) {
+// dependsOn declara checks dos quais este depende: com alguma dependência DOWN o check
+// não é executado e é reportado como DOWN (Skipped)
+//
+//	hm.AddCheck(zendia.NewDatabaseHealthCheck("mongodb", ping))
+//	hm.AddCheck(zendia.NewRepositoryHealthCheck("users_repo", repo), "mongodb")
+func (hm *HealthManager) AddCheck(check HealthCheck, dependsOn ...string) {
 	hm.mu.Lock()
 	defer hm.mu.Unlock()
 	hm.checks[check.Name()] = check
+	if len(dependsOn) > 0 {
+		hm.deps[check.Name()] = dependsOn
+	} else {
+		delete(hm.deps, check.Name())
+	}
 }
 
 // RemoveCheck remove uma verificação de saúde
@@ -80,20 +138,76 @@ func (hm *HealthManager) RemoveCheck(name string) {
 	hm.mu.Lock()
 	defer hm.mu.Unlock()
 	delete(hm.checks, name)
+	delete(hm.deps, name)
 }
 
-// CheckHealth executa todas as verificações
-func (hm *HealthManager) CheckHealth(ctx context.Context) map[string]interface{} {
+// EnableMetrics passa a registrar a latência de cada check nas últimas maxSamples execuções
+// As estatísticas ficam disponíveis em Metrics()
+func (hm *HealthManager) EnableMetrics(maxSamples int) *HealthMetrics {
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+	hm.metrics = NewHealthMetrics(maxSamples)
+	return hm.metrics
+}
+
+// AddMetricsSink envia a duração e o status de cada check para um sink externo (ex: *Metrics)
+func (hm *HealthManager) AddMetricsSink(sink HealthMetricsSink) {
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+	hm.sinks = append(hm.sinks, sink)
+}
+
+// Metrics retorna média e percentis de latência por check (nil se EnableMetrics não foi chamado)
+func (hm *HealthManager) Metrics() map[string]HealthCheckStats {
 	hm.mu.RLock()
 	defer hm.mu.RUnlock()
+	if hm.metrics == nil {
+		return nil
+	}
+	return hm.metrics.Stats()
+}
 
-	results := make(map[string]HealthCheckResult)
-	overallStatus := HealthStatusUp
+// HealthReport resultado tipado de todas as verificações
+type HealthReport struct {
+	Status    HealthStatus                 `json:"status"`
+	Checks    map[string]HealthCheckResult `json:"checks"`
+	Timestamp time.Time                    `json:"timestamp"`
+}
 
-	for name, check := range hm.checks {
-		result := check.Check(ctx)
-		results[name] = result
+// Report executa todas as verificações
+// O status geral é DOWN se algum check estiver DOWN, WARN se algum estiver WARN
+func (hm *HealthManager) Report(ctx context.Context) HealthReport {
+	return hm.ReportFor(ctx)
+}
+
+// ReportFor executa só os checks informados (e as dependências deles); sem nomes executa todos
+// Nome não registrado é reportado como DOWN, para um endpoint mal configurado não passar como saudável
+func (hm *HealthManager) ReportFor(ctx context.Context, names ...string) HealthReport {
+	hm.mu.RLock()
+	defer hm.mu.RUnlock()
+
+	if len(names) == 0 {
+		names = make([]string, 0, len(hm.checks))
+		for name := range hm.checks {
+			names = append(names, name)
+		}
+	}
 
+	results := make(map[string]HealthCheckResult)
+	visiting := make(map[string]bool)
+	for _, name := range names {
+		if _, exists := hm.checks[name]; !exists {
+			results[name] = HealthCheckResult{
+				Status:  HealthStatusDown,
+				Message: "Health check not registered",
+			}
+			continue
+		}
+		hm.runCheck(ctx, name, results, visiting)
+	}
+
+	overallStatus := HealthStatusUp
+	for _, result := range results {
 		if result.Status == HealthStatusDown {
 			overallStatus = HealthStatusDown
 		} else if result.Status == HealthStatusWarn && overallStatus == HealthStatusUp {
@@ -101,10 +215,74 @@ func (hm *HealthManager) CheckHealth(ctx context.Context) map[string]interface{}
 		}
 	}
 
+	return HealthReport{
+		Status:    overallStatus,
+		Checks:    results,
+		Timestamp: time.Now(),
+	}
+}
+
+// CheckHealth executa todas as verificações
+// Mantido por compatibilidade; prefira Report, que retorna o resultado tipado
+func (hm *HealthManager) CheckHealth(ctx context.Context) map[string]interface{} {
+	report := hm.Report(ctx)
 	return map[string]interface{}{
-		"status":    overallStatus,
-		"checks":    results,
-		"timestamp": time.Now(),
+		"status":    report.Status,
+		"checks":    report.Checks,
+		"timestamp": report.Timestamp,
+	}
+}
+
+// runCheck executa um check depois das suas dependências, reaproveitando os resultados já obtidos
+// Dependências não registradas são ignoradas; num ciclo a dependência em execução é ignorada
+func (hm *HealthManager) runCheck(ctx context.Context, name string, results map[string]HealthCheckResult, visiting map[string]bool) HealthCheckResult {
+	if result, done := results[name]; done {
+		return result
+	}
+	visiting[name] = true
+	defer delete(visiting, name)
+
+	var dependencies map[string]HealthStatus
+	var downDeps []string
+	for _, dep := range hm.deps[name] {
+		if _, exists := hm.checks[dep]; !exists || visiting[dep] {
+			continue
+		}
+		depResult := hm.runCheck(ctx, dep, results, visiting)
+		if dependencies == nil {
+			dependencies = make(map[string]HealthStatus)
+		}
+		dependencies[dep] = depResult.Status
+		if depResult.Status == HealthStatusDown {
+			downDeps = append(downDeps, dep)
+		}
+	}
+
+	var result HealthCheckResult
+	if len(downDeps) > 0 {
+		result = HealthCheckResult{
+			Status:  HealthStatusDown,
+			Message: fmt.Sprintf("Skipped: dependency down (%s)", strings.Join(downDeps, ", ")),
+			Skipped: true,
+		}
+	} else {
+		start := time.Now()
+		result = hm.checks[name].Check(ctx)
+		hm.recordMetrics(name, time.Since(start), result.Status)
+	}
+	result.Dependencies = dependencies
+
+	results[name] = result
+	return result
+}
+
+// recordMetrics repassa a execução para o agregador e os sinks configurados
+func (hm *HealthManager) recordMetrics(name string, duration time.Duration, status HealthStatus) {
+	if hm.metrics != nil {
+		hm.metrics.RecordHealthCheck(name, duration, status)
+	}
+	for _, sink := range hm.sinks {
+		sink.RecordHealthCheck(name, duration, status)
 	}
 }
 
@@ -192,71 +370,268 @@ func (m *MemoryHealthCheck) Check(ctx context.Context) HealthCheckResult {
 	}
 }
 
+// NewMetricsPersistenceHealthCheck cria verificação do último save de métricas
+// WARN com o último save mais antigo que 2× PersistInterval, DOWN acima de 5×
+func NewMetricsPersistenceHealthCheck(m *Metrics) *MetricsPersistenceHealthCheck {
+	return &MetricsPersistenceHealthCheck{metrics: m}
+}
+
+func (p *MetricsPersistenceHealthCheck) Name() string {
+	return "metrics_persistence"
+}
+
+func (p *MetricsPersistenceHealthCheck) Check(ctx context.Context) HealthCheckResult {
+	p.metrics.mu.RLock()
+	enabled := p.metrics.config.EnablePersistence && p.metrics.persister != nil
+	interval := p.metrics.config.PersistInterval
+	p.metrics.mu.RUnlock()
+
+	if !enabled {
+		return HealthCheckResult{
+			Status:  HealthStatusUp,
+			Message: "Metrics persistence disabled",
+		}
+	}
+
+	lastPersist := p.metrics.LastPersist()
+	age := time.Since(lastPersist)
+	details := map[string]interface{}{
+		"last_persist": lastPersist.Format(time.RFC3339),
+		"age":          age.Round(time.Second).String(),
+		"interval":     interval.String(),
+	}
+
+	if age > 5*interval {
+		return HealthCheckResult{
+			Status:  HealthStatusDown,
+			Message: fmt.Sprintf("Metrics not persisted for %s", age.Round(time.Second)),
+			Details: details,
+		}
+	}
+
+	if age > 2*interval {
+		return HealthCheckResult{
+			Status:  HealthStatusWarn,
+			Message: fmt.Sprintf("Metrics persistence delayed: %s", age.Round(time.Second)),
+			Details: details,
+		}
+	}
+
+	return HealthCheckResult{
+		Status:  HealthStatusUp,
+		Message: "Metrics persistence healthy",
+		Details: details,
+	}
+}
+
 // AddHealthEndpoint adiciona endpoint de saúde ao grupo
 func (rg *RouteGroup) AddHealthEndpoint(healthManager *HealthManager) {
-	rg.GET("/health", Handle(func(c *Context[any]) error {
-		ctx := context.Background()
-		health := healthManager.CheckHealth(ctx)
+	rg.GET(RouteHealth, healthHandler(healthManager))
+}
 
-		status := health["status"].(HealthStatus)
-		if status == HealthStatusDown {
-			c.JSON(503, health)
-		} else {
-			c.Success("Success in get endpoint health.", health)
-		}
+// AddHealthEndpoint adiciona endpoint de saúde ao Zendia principal
+func (z *Zendia) AddHealthEndpoint(healthManager *HealthManager) {
+	z.GET(RouteHealth, healthHandler(healthManager))
+	z.features[FeatureHealth] = true
+}
+
+// AddNamedHealthEndpoint adiciona um endpoint que executa só os checks informados
+//
+//	app.AddNamedHealthEndpoint(health, "/health/db", "mongodb", "redis")
+func (rg *RouteGroup) AddNamedHealthEndpoint(healthManager *HealthManager, path string, checkNames ...string) {
+	rg.GET(path, healthHandler(healthManager, checkNames...))
+}
+
+// AddNamedHealthEndpoint adiciona um endpoint que executa só os checks informados
+func (z *Zendia) AddNamedHealthEndpoint(healthManager *HealthManager, path string, checkNames ...string) {
+	z.GET(path, healthHandler(healthManager, checkNames...))
+	z.features[FeatureHealth] = true
+}
+
+// AddProbeEndpoints adiciona os probes do Kubernetes:
+// GET /health/live responde UP sem executar checks (o processo está de pé) e
+// GET /health/ready executa readyChecks (todos se vazio), respondendo 503 quando DOWN
+func (z *Zendia) AddProbeEndpoints(healthManager *HealthManager, readyChecks ...string) {
+	z.GET(RouteHealthLive, Handle(func(c *Context[any]) error {
+		c.Success("Success in get endpoint health.", HealthReport{
+			Status:    HealthStatusUp,
+			Checks:    map[string]HealthCheckResult{},
+			Timestamp: time.Now(),
+		})
 		return nil
 	}))
+	z.GET(RouteHealthReady, healthHandler(healthManager, readyChecks...))
+	z.features[FeatureHealth] = true
 }
 
-// AddHealthEndpoint adiciona endpoint de saúde ao Zendia principal
-func (z *Zendia) AddHealthEndpoint(healthManager *HealthManager) {
-	z.GET("/health", Handle(func(c *Context[any]) error {
-		ctx := context.Background()
-		health := healthManager.CheckHealth(ctx)
+// healthHandler responde o HealthReport: 503 com o report quando DOWN, Success caso contrário
+func healthHandler(healthManager *HealthManager, checkNames ...string) gin.HandlerFunc {
+	return Handle(func(c *Context[any]) error {
+		report := healthManager.ReportFor(context.Background(), checkNames...)
 
-		status := health["status"].(HealthStatus)
-		if status == HealthStatusDown {
-			c.JSON(503, health)
+		if report.Status == HealthStatusDown {
+			c.JSON(http.StatusServiceUnavailable, report)
 		} else {
-			c.Success("Success in get endpoint health.", health)
+			c.Success("Success in get endpoint health.", report)
 		}
 		return nil
+	})
+}
+
+// Variáveis de build - preenchidas via ldflags:
+//
+//	go build -ldflags "-X github.com/azzidev/zendiaframework.BuildVersion=1.2.0 \
+//	  -X github.com/azzidev/zendiaframework.BuildCommit=$(git rev-parse HEAD) \
+//	  -X github.com/azzidev/zendiaframework.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	BuildVersion = "dev"
+	BuildCommit  = ""
+	BuildTime    = ""
+)
+
+// BuildInfo informações do build em execução
+type BuildInfo struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"git_commit,omitempty"`
+	BuildTime string `json:"build_time,omitempty"`
+}
+
+// DefaultBuildInfo retorna as informações de build preenchidas via ldflags
+func DefaultBuildInfo() BuildInfo {
+	return BuildInfo{
+		Version:   BuildVersion,
+		GitCommit: BuildCommit,
+		BuildTime: BuildTime,
+	}
+}
+
+// AddInfoEndpoint adiciona endpoint GET /info com versão, commit, build, versão do Go e uptime
+// Campos vazios em info são completados com os valores de DefaultBuildInfo
+func (z *Zendia) AddInfoEndpoint(info BuildInfo) {
+	defaults := DefaultBuildInfo()
+	if info.Version == "" {
+		info.Version = defaults.Version
+	}
+	if info.GitCommit == "" {
+		info.GitCommit = defaults.GitCommit
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = defaults.BuildTime
+	}
+
+	z.GET(RouteInfo, Handle(func(c *Context[any]) error {
+		c.Success("Build info.", map[string]interface{}{
+			"version":    info.Version,
+			"git_commit": info.GitCommit,
+			"build_time": info.BuildTime,
+			"go_version": runtime.Version(),
+			"uptime":     time.Since(z.startTime).String(),
+			"started_at": z.startTime.Format(time.RFC3339),
+		})
+		return nil
 	}))
 }
 
 // NewHTTPHealthCheck cria verificação HTTP
-func NewHTTPHealthCheck(name, url string, timeout time.Duration) *HTTPHealthCheck {
-	return &HTTPHealthCheck{
+// Sem WithHTTPRetries uma única falha já reporta DOWN
+func NewHTTPHealthCheck(name, url string, timeout time.Duration, opts ...HTTPHealthCheckOption) *HTTPHealthCheck {
+	h := &HTTPHealthCheck{
 		name:    name,
 		url:     url,
 		timeout: timeout,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 func (h *HTTPHealthCheck) Name() string {
 	return h.name
 }
 
+// Check reporta DOWN só depois de todas as tentativas falharem
+// Sucesso depois de uma falha reporta WARN, indicando que foi preciso tentar de novo
 func (h *HTTPHealthCheck) Check(ctx context.Context) HealthCheckResult {
-	client := &http.Client{Timeout: h.timeout}
-	start := time.Now()
-
-	resp, err := client.Get(h.url)
-	responseTime := time.Since(start)
-
-	if err != nil {
+	if h.breaker != nil && !h.breaker.Allow() {
 		return HealthCheckResult{
 			Status:  HealthStatusDown,
-			Message: fmt.Sprintf("HTTP request failed: %v", err),
+			Message: "Circuit breaker open: request skipped",
 			Details: map[string]interface{}{
 				"url":              h.url,
-				"response_time_ms": responseTime.Milliseconds(),
-				"error":            err.Error(),
+				"circuit_state":    h.breaker.State(),
+				"circuit_failures": h.breaker.Failures(),
 			},
 		}
 	}
-	defer resp.Body.Close()
 
+	client := &http.Client{Timeout: h.timeout}
+
+	var result HealthCheckResult
+attempts:
+	for attempt := 1; attempt <= h.retries+1; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				break attempts
+			case <-time.After(time.Duration(attempt-1) * h.backoff):
+			}
+		}
+
+		result = h.attempt(ctx, client)
+		if result.Status != HealthStatusDown {
+			if attempt > 1 {
+				result.Status = HealthStatusWarn
+				result.Message = fmt.Sprintf("HTTP service healthy after %d attempts", attempt)
+			}
+			break
+		}
+	}
+
+	details, _ := result.Details.(map[string]interface{})
+	if details != nil && h.retries > 0 {
+		details["max_attempts"] = h.retries + 1
+	}
+	if h.breaker != nil {
+		if result.Status == HealthStatusDown {
+			h.breaker.Failure()
+		} else {
+			h.breaker.Success()
+		}
+		if details != nil {
+			details["circuit_state"] = h.breaker.State()
+			details["circuit_failures"] = h.breaker.Failures()
+		}
+	}
+	return result
+}
+
+// attempt faz uma requisição GET ao serviço
+func (h *HTTPHealthCheck) attempt(ctx context.Context, client *http.Client) HealthCheckResult {
+	start := time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.url, nil)
+	if err == nil {
+		var resp *http.Response
+		resp, err = client.Do(req)
+		if err == nil {
+			defer resp.Body.Close()
+			return h.fromResponse(resp, time.Since(start))
+		}
+	}
+
+	return HealthCheckResult{
+		Status:  HealthStatusDown,
+		Message: fmt.Sprintf("HTTP request failed: %v", err),
+		Details: map[string]interface{}{
+			"url":              h.url,
+			"response_time_ms": time.Since(start).Milliseconds(),
+			"error":            err.Error(),
+		},
+	}
+}
+
+func (h *HTTPHealthCheck) fromResponse(resp *http.Response, responseTime time.Duration) HealthCheckResult {
 	if resp.StatusCode >= 400 {
 		return HealthCheckResult{
 			Status:  HealthStatusDown,
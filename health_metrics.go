@@ -0,0 +1,124 @@
+package zendia
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultHealthMetricsSamples quantidade de execuções mantidas por check
+const DefaultHealthMetricsSamples = 100
+
+// HealthMetricsSink recebe a duração e o status de cada execução de health check
+type HealthMetricsSink interface {
+	RecordHealthCheck(name string, duration time.Duration, status HealthStatus)
+}
+
+// HealthCheckStats latência e status agregados das últimas execuções de um check
+type HealthCheckStats struct {
+	Samples    int          `json:"samples"`
+	Failures   int          `json:"failures"`
+	AvgMs      float64      `json:"avg_ms"`
+	P50Ms      float64      `json:"p50_ms"`
+	P95Ms      float64      `json:"p95_ms"`
+	P99Ms      float64      `json:"p99_ms"`
+	MaxMs      float64      `json:"max_ms"`
+	LastStatus HealthStatus `json:"last_status"`
+	LastCheck  time.Time    `json:"last_check"`
+}
+
+type healthSample struct {
+	duration time.Duration
+	status   HealthStatus
+	at       time.Time
+}
+
+// HealthMetrics janela deslizante de execuções por health check
+type HealthMetrics struct {
+	mu         sync.RWMutex
+	maxSamples int
+	samples    map[string][]healthSample
+}
+
+// NewHealthMetrics cria o agregador mantendo as últimas maxSamples execuções por check
+func NewHealthMetrics(maxSamples int) *HealthMetrics {
+	if maxSamples <= 0 {
+		maxSamples = DefaultHealthMetricsSamples
+	}
+	return &HealthMetrics{
+		maxSamples: maxSamples,
+		samples:    make(map[string][]healthSample),
+	}
+}
+
+// RecordHealthCheck registra uma execução descartando a mais antiga quando a janela enche
+func (hm *HealthMetrics) RecordHealthCheck(name string, duration time.Duration, status HealthStatus) {
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	samples := append(hm.samples[name], healthSample{duration: duration, status: status, at: time.Now()})
+	if len(samples) > hm.maxSamples {
+		samples = samples[len(samples)-hm.maxSamples:]
+	}
+	hm.samples[name] = samples
+}
+
+// Stats retorna média, percentis e falhas de cada check na janela atual
+func (hm *HealthMetrics) Stats() map[string]HealthCheckStats {
+	hm.mu.RLock()
+	defer hm.mu.RUnlock()
+
+	stats := make(map[string]HealthCheckStats, len(hm.samples))
+	for name, samples := range hm.samples {
+		if len(samples) == 0 {
+			continue
+		}
+
+		durations := make([]float64, len(samples))
+		var total float64
+		failures := 0
+		for i, s := range samples {
+			ms := float64(s.duration) / float64(time.Millisecond)
+			durations[i] = ms
+			total += ms
+			if s.status == HealthStatusDown {
+				failures++
+			}
+		}
+		sort.Float64s(durations)
+
+		last := samples[len(samples)-1]
+		stats[name] = HealthCheckStats{
+			Samples:    len(samples),
+			Failures:   failures,
+			AvgMs:      total / float64(len(samples)),
+			P50Ms:      percentile(durations, 50),
+			P95Ms:      percentile(durations, 95),
+			P99Ms:      percentile(durations, 99),
+			MaxMs:      durations[len(durations)-1],
+			LastStatus: last.status,
+			LastCheck:  last.at,
+		}
+	}
+
+	return stats
+}
+
+// percentile calcula o percentil pelo método nearest-rank (sorted deve estar ordenado)
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// RecordHealthCheck registra o check na seção "health" das métricas (HealthMetricsSink)
+// Fica fora das estatísticas HTTP: não conta em total_requests nem no error_rate
+func (m *Metrics) RecordHealthCheck(name string, duration time.Duration, status HealthStatus) {
+	m.health.RecordHealthCheck(name, duration, status)
+}
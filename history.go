@@ -36,6 +36,7 @@ type FieldChange struct {
 // HistoryManager gerencia o histórico de mudanças
 type HistoryManager struct {
 	collection *mongo.Collection
+	idEncoder  IDEncoder // Formato de entity_id/tenant_id nos filtros (nil: UUIDBinaryEncoder)
 }
 
 // NewHistoryManager cria um novo gerenciador de histórico
@@ -52,9 +53,9 @@ func (hm *HistoryManager) RecordChanges(ctx context.Context, entityID uuid.UUID,
 		return nil
 	}
 
-	var tenantUUID uuid.UUID
-	if tenantInfo.TenantID != "" {
-		tenantUUID = uuid.MustParse(tenantInfo.TenantID)
+	tenantUUID, err := ParseTenantID(tenantInfo.TenantID)
+	if err != nil {
+		return err
 	}
 
 	entry := HistoryEntry{
@@ -64,28 +65,29 @@ func (hm *HistoryManager) RecordChanges(ctx context.Context, entityID uuid.UUID,
 		TenantID:   tenantUUID,
 		Trigger: TriggerInfo{
 			Name: triggerName,
-			ID:   uuid.MustParse(tenantInfo.UserID),
+			ID:   ParseUserID(tenantInfo.UserID),
 			At:   tenantInfo.ActionAt,
 			By:   tenantInfo.UserName,
 		},
 		Changes: changes,
 	}
 
-	_, err := hm.collection.InsertOne(ctx, entry)
+	_, err = hm.collection.InsertOne(ctx, entry)
 	return err
 }
 
 // GetHistory busca o histórico de uma entidade
 func (hm *HistoryManager) GetHistory(ctx context.Context, entityID uuid.UUID) ([]HistoryEntry, error) {
-	tenantInfo := GetTenantInfo(ctx)
-
 	filter := map[string]interface{}{
-		"entity_id": entityID,
+		"entity_id": hm.idToBSON(entityID),
 	}
 
-	if tenantInfo.TenantID != "" {
-		filter["tenant_id"] = uuid.MustParse(tenantInfo.TenantID)
+	// Mesma política dos repositories: sem tenant não há histórico
+	tenantUUID, err := requireTenant(ctx)
+	if err != nil {
+		return nil, err
 	}
+	filter["tenant_id"] = hm.idToBSON(tenantUUID)
 
 	cursor, err := hm.collection.Find(ctx, filter)
 	if err != nil {
@@ -93,12 +95,15 @@ func (hm *HistoryManager) GetHistory(ctx context.Context, entityID uuid.UUID) ([
 	}
 	defer cursor.Close(ctx)
 
-	var history []HistoryEntry
-	if err = cursor.All(ctx, &history); err != nil {
-		return nil, err
-	}
+	return decodeCursor[HistoryEntry](ctx, cursor)
+}
 
-	return history, nil
+// idToBSON converte um UUID com o IDEncoder do repository, como Repository.idToBSON
+func (hm *HistoryManager) idToBSON(id uuid.UUID) interface{} {
+	if hm.idEncoder == nil {
+		return UUIDBinaryEncoder(id)
+	}
+	return hm.idEncoder(id)
 }
 
 func (hm *HistoryManager) detectChanges(before, after interface{}) map[string]FieldChange {
@@ -154,3 +159,34 @@ func (hm *HistoryManager) shouldSkipField(fieldName string) bool {
 	}
 	return skipFields[fieldName]
 }
+
+// AddHistoryEndpoint registra GET /:id/history no grupo, expondo o histórico da entidade
+// O tenant do contexto é obrigatório: o histórico é sempre filtrado por ele
+//
+// Uso:
+//
+//	users := api.Group("/users")
+//	zendia.AddHistoryEndpoint(users, userRepo) // GET /users/:id/history
+func AddHistoryEndpoint[T MongoAuditableEntity](rg *RouteGroup, repo *Repository[T]) {
+	rg.GET("/:id/history", Handle(func(c *Context[any]) error {
+		if GetTenantID(c.Request.Context()) == "" {
+			return NewUnauthorizedError(MsgLoginRequired)
+		}
+
+		id, err := c.ParamUUID("id")
+		if err != nil {
+			return err
+		}
+
+		entries, err := repo.GetHistory(c.Request.Context(), id)
+		if err != nil {
+			return err
+		}
+		if entries == nil {
+			entries = []HistoryEntry{}
+		}
+
+		c.Success(MsgRetrievedSuccess, entries, int64(len(entries)))
+		return nil
+	}))
+}
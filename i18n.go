@@ -0,0 +1,118 @@
+package zendia
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LocaleKey chave do idioma negociado no gin.Context e no context.Context
+const LocaleKey = "locale"
+
+// I18nConfig idiomas atendidos pelo middleware I18n
+type I18nConfig struct {
+	Supported []string // Idiomas na ordem de preferência do servidor (padrão: DefaultLocale e "en")
+	Default   string   // Idioma sem Accept-Language compatível (padrão: o primeiro de Supported)
+}
+
+// I18n middleware que negocia o idioma pelo Accept-Language e grava no contexto
+// Respeita os q-values do cliente; "en-US" casa com "en" e "pt" com "pt-BR". Empates
+// ficam com a ordem de Supported. O idioma escolhido vai no header Content-Language
+// e é usado pelas mensagens do validator em BindJSON/BindQuery/BindURI/BindAll.
+//
+// Uso:
+//
+//	app.Use(zendia.I18n(zendia.I18nConfig{Supported: []string{"pt-BR", "en"}}))
+func I18n(config I18nConfig) gin.HandlerFunc {
+	if len(config.Supported) == 0 {
+		config.Supported = []string{DefaultLocale, "en"}
+	}
+	if config.Default == "" {
+		config.Default = config.Supported[0]
+	}
+
+	return func(c *gin.Context) {
+		locale := negotiateLocale(c.GetHeader("Accept-Language"), config.Supported)
+		if locale == "" {
+			locale = config.Default
+		}
+
+		c.Set(LocaleKey, locale)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), LocaleKey, locale))
+		c.Header("Content-Language", locale)
+		c.Next()
+	}
+}
+
+// Locale retorna o idioma negociado pelo I18n (DefaultLocale sem o middleware)
+func (c *Context[T]) Locale() string {
+	return GetLocaleFromGin(c.Context)
+}
+
+// GetLocale retorna o idioma do context.Context (DefaultLocale sem o middleware)
+func GetLocale(ctx context.Context) string {
+	if locale, ok := ctx.Value(LocaleKey).(string); ok && locale != "" {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// GetLocaleFromGin retorna o idioma do gin.Context (DefaultLocale sem o middleware)
+func GetLocaleFromGin(c *gin.Context) string {
+	if locale := c.GetString(LocaleKey); locale != "" {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// negotiateLocale escolhe entre supported o idioma de maior q-value no Accept-Language
+// Retorna "" se nenhum idioma for aceito pelo cliente
+func negotiateLocale(header string, supported []string) string {
+	if strings.TrimSpace(header) == "" {
+		return ""
+	}
+
+	qualities := parseAcceptEncoding(header)
+	best := ""
+	bestQ := 0.0
+	for _, locale := range supported {
+		// Empate mantém o primeiro, que é o preferido pelo servidor
+		if q := localeQuality(strings.ToLower(locale), qualities); q > bestQ {
+			best = locale
+			bestQ = q
+		}
+	}
+	return best
+}
+
+// localeQuality q-value do cliente para locale: tag exata, idioma base, mesma família ou "*"
+func localeQuality(locale string, qualities map[string]float64) float64 {
+	if q, ok := qualities[locale]; ok {
+		return q
+	}
+	lang := localeLanguage(locale)
+	if q, ok := qualities[lang]; ok {
+		return q
+	}
+
+	best, found := 0.0, false
+	for tag, q := range qualities {
+		if localeLanguage(tag) == lang && (!found || q > best) {
+			best, found = q, true
+		}
+	}
+	if found {
+		return best
+	}
+	return qualities["*"]
+}
+
+// localeLanguage idioma base de uma tag ("pt-BR" → "pt", "en_US" → "en")
+func localeLanguage(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		return locale[:i]
+	}
+	return locale
+}
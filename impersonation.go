@@ -0,0 +1,68 @@
+package zendia
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+)
+
+// Impersonation registro de um admin atuando em outro tenant (ver Context.ImpersonateTenant)
+type Impersonation struct {
+	FromTenantID string    `json:"fromTenantId"` // Tenant do próprio admin
+	TenantID     string    `json:"tenantId"`     // Tenant assumido
+	UserID       string    `json:"userId"`
+	Reason       string    `json:"reason"`
+	At           time.Time `json:"at"`
+}
+
+// ImpersonateTenant troca o tenant da requisição para tenantID, só para usuários admin
+// Ao contrário do SetTenant, substitui inclusive o tenant do claim do token, então exige
+// a role RoleAdmin (HasRole) e um motivo: sem a role responde Forbidden. A troca é logada
+// e fica em GetImpersonation(ctx) para a auditoria. Vale só para esta requisição: o tenant
+// mora no context dela e nada é gravado na sessão, então a próxima volta ao tenant do token.
+//
+//	if err := c.ImpersonateTenant(c.Param("tenant"), "Ticket #4521: erro no relatório"); err != nil {
+//	    return err
+//	}
+func (c *Context[T]) ImpersonateTenant(tenantID string, reason string) error {
+	if !c.HasRole(RoleAdmin) {
+		return NewForbiddenError(MsgImpersonationDenied)
+	}
+	tenantID = sanitizeHeaderValue(strings.TrimSpace(tenantID))
+	if tenantID == "" {
+		return NewBadRequestError(MsgInvalidTenantID)
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return NewBadRequestError(MsgImpersonationReason)
+	}
+
+	from := c.GetTenantID()
+	if current := GetImpersonation(c.Request.Context()); current != nil {
+		from = current.FromTenantID
+	}
+	impersonation := &Impersonation{
+		FromTenantID: from,
+		TenantID:     tenantID,
+		UserID:       GetUserIDFromGin(c.Context),
+		Reason:       reason,
+		At:           time.Now(),
+	}
+
+	setIdentity(c.Context, TenantIDKey, tenantID, TenantSourceImpersonation)
+	c.Header(HeaderTenantID, tenantID)
+	c.Set(ImpersonationKey, impersonation)
+	ctx := context.WithValue(c.Request.Context(), ContextTenantID, tenantID)
+	c.Request = c.Request.WithContext(context.WithValue(ctx, ImpersonationKey, impersonation))
+
+	log.Printf("[ZENDIA] tenant impersonation: user=%s from=%s to=%s reason=%q",
+		sanitizeLogValue(impersonation.UserID), sanitizeLogValue(from), sanitizeLogValue(tenantID), sanitizeLogValue(reason))
+	return nil
+}
+
+// GetImpersonation retorna a troca de tenant feita por ImpersonateTenant (nil sem troca)
+func GetImpersonation(ctx context.Context) *Impersonation {
+	impersonation, _ := ctx.Value(ImpersonationKey).(*Impersonation)
+	return impersonation
+}
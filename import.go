@@ -0,0 +1,233 @@
+package zendia
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ImportStatus resultado de uma linha do ImportTenant
+type ImportStatus string
+
+const (
+	ImportSuccess         ImportStatus = "success"
+	ImportValidationError ImportStatus = "validation_error" // JSON inválido, validator ou tenant de outro cliente
+	ImportDuplicate       ImportStatus = "duplicate"        // _id ou índice único já existente
+	ImportFailed          ImportStatus = "failed"           // Outro erro de escrita do MongoDB
+)
+
+// ImportLineResult resultado de uma linha do NDJSON (Line começa em 1)
+type ImportLineResult struct {
+	Line   int          `json:"line"`
+	ID     string       `json:"id,omitempty"`
+	Status ImportStatus `json:"status"`
+	Error  string       `json:"error,omitempty"`
+}
+
+// ImportReport resumo do ImportTenant: contadores e só as linhas recusadas
+// Errors guarda no máximo ImportOptions.MaxErrors linhas; as demais só entram em Failed
+// e ErrorsTruncated fica true. O resultado de toda linha vai para ImportOptions.OnResult.
+type ImportReport struct {
+	Total           int                `json:"total"`
+	Imported        int                `json:"imported"`
+	Failed          int                `json:"failed"`
+	Errors          []ImportLineResult `json:"errors"`
+	ErrorsTruncated bool               `json:"errors_truncated,omitempty"`
+
+	maxErrors int
+	onResult  func(ImportLineResult)
+}
+
+// ImportOptions opções do ImportTenant
+type ImportOptions struct {
+	BatchSize int                    // Registros por InsertMany (padrão: DefaultImportBatchSize)
+	Validator *Validator             // Validator dos registros (padrão: NewValidator())
+	MaxErrors int                    // Linhas recusadas guardadas no relatório (padrão: DefaultImportMaxErrors)
+	OnResult  func(ImportLineResult) // Chamado com o resultado de cada linha, inclusive as importadas
+}
+
+// ImportTenant lê NDJSON de reader e insere os registros no tenant do contexto em lotes
+// Cada linha é validada, recebe tenant e auditoria como no Create, e é recusada se trouxer
+// tenant_id de outro tenant. O parse é em streaming: só um lote fica em memória, e o
+// relatório guarda contadores e até MaxErrors linhas recusadas, não o resultado de cada linha.
+// Falhas de uma linha não abortam a importação; o ImportReport diz o que corrigir e reenviar.
+//
+//	report, err := repo.ImportTenant(ctx, file, zendia.ImportOptions{})
+func (r *Repository[T]) ImportTenant(ctx context.Context, reader io.Reader, opts ImportOptions) (*ImportReport, error) {
+	if !r.config.audit {
+		return nil, NewBadRequestError(MsgImportRequiresAudit)
+	}
+	tenantID := GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, NewUnauthorizedError(MsgLoginRequired)
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultImportBatchSize
+	}
+	if opts.Validator == nil {
+		opts.Validator = NewValidator()
+	}
+	if opts.MaxErrors <= 0 {
+		opts.MaxErrors = DefaultImportMaxErrors
+	}
+
+	report := &ImportReport{Errors: []ImportLineResult{}, maxErrors: opts.MaxErrors, onResult: opts.OnResult}
+	batch := make([]T, 0, opts.BatchSize)
+	lines := make([]int, 0, opts.BatchSize)
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxImportLineSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		report.Total++
+
+		// Cada linha precisa ser um objeto; "null" deixaria a entidade nil
+		var entity T
+		if data[0] != '{' || json.Unmarshal(data, &entity) != nil {
+			report.fail(line, "", ImportValidationError, MsgImportInvalidJSON)
+			continue
+		}
+		if err := opts.Validator.Validate(entity); err != nil {
+			report.fail(line, "", ImportValidationError, validationMessage(err))
+			continue
+		}
+		if te, ok := any(entity).(TenantedEntity); ok && foreignTenant(te.GetTenantID(), tenantID) {
+			report.fail(line, "", ImportValidationError, MsgImportTenantMismatch)
+			continue
+		}
+		if err := r.prepareInsert(ctx, entity); err != nil {
+			return report, err
+		}
+
+		batch = append(batch, entity)
+		lines = append(lines, line)
+		if len(batch) == opts.BatchSize {
+			if err := r.importBatch(ctx, batch, lines, report); err != nil {
+				return report, err
+			}
+			batch, lines = batch[:0], lines[:0]
+		}
+	}
+
+	if err := r.importBatch(ctx, batch, lines, report); err != nil {
+		return report, err
+	}
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return report, NewBadRequestError(MsgImportLineTooLong)
+		}
+		return report, internalError("Failed to read import", err)
+	}
+	return report, nil
+}
+
+// importBatch insere o lote sem ordem e registra o resultado de cada linha
+// Um registro duplicado não impede a inserção dos demais
+func (r *Repository[T]) importBatch(ctx context.Context, batch []T, lines []int, report *ImportReport) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(batch))
+	for i, entity := range batch {
+		docs[i] = entity
+	}
+
+	failed := map[int]mongo.BulkWriteError{}
+	if _, err := r.collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false)); err != nil {
+		var bwe mongo.BulkWriteException
+		if !errors.As(err, &bwe) || len(bwe.WriteErrors) == 0 {
+			return internalError("Failed to import entities", err)
+		}
+		for _, we := range bwe.WriteErrors {
+			failed[we.Index] = we
+		}
+	}
+
+	for i, entity := range batch {
+		id := entity.GetID().String()
+		we, ok := failed[i]
+		switch {
+		case !ok:
+			report.Imported++
+			report.notify(ImportLineResult{Line: lines[i], ID: id, Status: ImportSuccess})
+		case mongo.IsDuplicateKeyError(we.WriteError):
+			report.fail(lines[i], id, ImportDuplicate, MsgImportDuplicate)
+		default:
+			report.fail(lines[i], id, ImportFailed, sanitizeLogValue(we.Message))
+		}
+	}
+	return nil
+}
+
+// fail conta a linha recusada e a guarda enquanto houver espaço em Errors
+func (rep *ImportReport) fail(line int, id string, status ImportStatus, message string) {
+	result := ImportLineResult{Line: line, ID: id, Status: status, Error: message}
+	rep.Failed++
+	if len(rep.Errors) < rep.maxErrors {
+		rep.Errors = append(rep.Errors, result)
+	} else {
+		rep.ErrorsTruncated = true
+	}
+	rep.notify(result)
+}
+
+// notify repassa o resultado da linha para o OnResult, quando configurado
+func (rep *ImportReport) notify(result ImportLineResult) {
+	if rep.onResult != nil {
+		rep.onResult(result)
+	}
+}
+
+// foreignTenant indica se o registro traz um tenant diferente do contexto
+// Tenant vazio (ou UUID zero) é aceito e recebe o tenant do contexto
+func foreignTenant(recordTenant, tenantID string) bool {
+	if recordTenant == "" || recordTenant == uuid.Nil.String() {
+		return false
+	}
+	return !sameTenant(recordTenant, tenantID)
+}
+
+// validationMessage extrai o detalhe do erro do Validator ("name é obrigatório; ...")
+func validationMessage(err error) string {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Details != nil {
+		return apiErr.Details.Error()
+	}
+	return err.Error()
+}
+
+// AddImportEndpoint registra POST /import, que importa um NDJSON no tenant do contexto
+// Usa o validator compartilhado da instância e responde o ImportReport, mesmo com linhas recusadas.
+//
+// Uso:
+//
+//	users := api.Group("/users")
+//	zendia.AddImportEndpoint(users, userRepo) // POST /users/import
+func AddImportEndpoint[T MongoAuditableEntity](rg *RouteGroup, repo *Repository[T]) {
+	rg.POST("/import", Handle(func(c *Context[any]) error {
+		opts := ImportOptions{}
+		if c.zendia != nil {
+			opts.Validator = c.zendia.GetValidator()
+		}
+
+		report, err := repo.ImportTenant(c.Request.Context(), c.Request.Body, opts)
+		if err != nil {
+			return err
+		}
+		c.Success(MsgImportCompleted, report)
+		return nil
+	}))
+}
@@ -0,0 +1,74 @@
+package zendia
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// SuccessMasked retorna uma resposta de sucesso removendo os campos JSON indicados
+// Permite usar a mesma entidade em endpoints admin e públicos sem DTOs paralelos.
+// Caminhos com ponto navegam objetos aninhados ("created.by_id"); arrays são
+// percorridos item a item, então o mesmo mask vale para listas.
+//
+//	c.SuccessMasked("Usuário encontrado", user, "tenant_id", "deleted", "created.by_id")
+func (c *Context[T]) SuccessMasked(message string, data interface{}, mask ...string) {
+	masked, err := maskFields(c.marshaler(), data, mask...)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	c.Success(message, masked)
+}
+
+// marshaler retorna o Marshal do JSONSerializer configurado, ou encoding/json
+func (c *Context[T]) marshaler() func(interface{}) ([]byte, error) {
+	if c.zendia != nil && c.zendia.jsonSerializer != nil {
+		return c.zendia.jsonSerializer.Marshal
+	}
+	return json.Marshal
+}
+
+// maskFields serializa data e remove os caminhos de mask da representação genérica
+// Números são mantidos como json.Number para não perder precisão em int64
+func maskFields(marshal func(interface{}) ([]byte, error), data interface{}, mask ...string) (interface{}, error) {
+	if len(mask) == 0 {
+		return data, nil
+	}
+
+	raw, err := marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	for _, path := range mask {
+		removePath(generic, strings.Split(path, "."))
+	}
+	return generic, nil
+}
+
+// removePath remove o campo do caminho em objetos e em cada item de arrays
+func removePath(value interface{}, path []string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(path) == 1 {
+			delete(v, path[0])
+			return
+		}
+		if child, ok := v[path[0]]; ok {
+			removePath(child, path[1:])
+		}
+	case []interface{}:
+		for _, item := range v {
+			removePath(item, path)
+		}
+	}
+}
@@ -2,7 +2,10 @@ package zendia
 
 import (
 	"fmt"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -18,14 +21,115 @@ func Logger() gin.HandlerFunc {
 	})
 }
 
+// CORSConfig configuração de Cross-Origin Resource Sharing
+type CORSConfig struct {
+	AllowOrigins     []string      // Origens permitidas ("*" libera todas)
+	AllowMethods     []string      // Padrão: GET, POST, PUT, DELETE, PATCH, OPTIONS
+	AllowHeaders     []string      // Padrão: Origin, Content-Type, Accept, Authorization
+	ExposeHeaders    []string      // Headers da resposta legíveis pelo browser
+	AllowCredentials bool          // Envia Access-Control-Allow-Credentials; "*" em AllowOrigins passa a ser ignorado
+	MaxAge           time.Duration // Cache do preflight no browser (Access-Control-Max-Age)
+}
+
+// DefaultCORSConfig configuração padrão: todas as origens, preflight cacheado por 12h
+var DefaultCORSConfig = CORSConfig{
+	AllowOrigins: []string{"*"},
+	AllowMethods: []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"},
+	AllowHeaders: []string{"Origin", "Content-Type", "Accept", "Authorization"},
+	MaxAge:       12 * time.Hour,
+}
+
 // CORS middleware para Cross-Origin Resource Sharing
 func CORS(origin string) gin.HandlerFunc {
+	config := DefaultCORSConfig
+	config.AllowOrigins = []string{origin}
+	config.MaxAge = 0
+	return CORSWithConfig(config)
+}
+
+// CORSWithConfig middleware de CORS configurável
+//
+// Precedência: quando usado globalmente (app.Use), rotas de grupos com RouteGroup.CORS
+// são ignoradas aqui e atendidas apenas pela configuração do grupo.
+func CORSWithConfig(config CORSConfig) gin.HandlerFunc {
+	handler := corsHandler(config)
+
+	return func(c *gin.Context) {
+		if val, exists := c.Get("zendia_instance"); exists {
+			if z, ok := val.(*Zendia); ok && z.hasGroupCORS(c.FullPath()) {
+				c.Next()
+				return
+			}
+		}
+		handler(c)
+	}
+}
+
+// corsHandler aplica os headers de CORS e responde o preflight
+func corsHandler(config CORSConfig) gin.HandlerFunc {
+	if len(config.AllowMethods) == 0 {
+		config.AllowMethods = DefaultCORSConfig.AllowMethods
+	}
+	if len(config.AllowHeaders) == 0 {
+		config.AllowHeaders = DefaultCORSConfig.AllowHeaders
+	}
+
+	// Com credenciais o browser rejeita "*": só origens listadas explicitamente são refletidas
+	allowAll := false
+	origins := make(map[string]bool, len(config.AllowOrigins))
+	for _, o := range config.AllowOrigins {
+		if o == "*" {
+			allowAll = !config.AllowCredentials
+			continue
+		}
+		origins[strings.ToLower(o)] = true
+	}
+	if config.AllowCredentials && len(origins) == 0 {
+		log.Printf("⚠️  CORS with AllowCredentials has no explicit AllowOrigins; cross-origin requests will be rejected")
+	}
+
+	methods := strings.Join(config.AllowMethods, ", ")
+	headers := strings.Join(config.AllowHeaders, ", ")
+	expose := strings.Join(config.ExposeHeaders, ", ")
+	maxAge := strconv.Itoa(int(config.MaxAge.Seconds()))
+
 	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", origin)
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, PATCH, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
+		origin := c.GetHeader("Origin")
+		preflight := c.Request.Method == http.MethodOptions
 
-		if c.Request.Method == "OPTIONS" {
+		// A resposta depende da origem (inclusive quando ela é recusada): caches não podem reaproveitá-la
+		if !allowAll {
+			c.Writer.Header().Add("Vary", "Origin")
+		}
+
+		allowed := allowAll || (origin != "" && origins[strings.ToLower(origin)])
+		if !allowed {
+			if preflight && origin != "" {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if allowAll {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+		}
+		if config.AllowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+		if expose != "" {
+			c.Header("Access-Control-Expose-Headers", expose)
+		}
+		c.Header("Access-Control-Allow-Methods", methods)
+		c.Header("Access-Control-Allow-Headers", headers)
+
+		if preflight {
+			if config.MaxAge > 0 {
+				c.Header("Access-Control-Max-Age", maxAge)
+			}
 			c.AbortWithStatus(http.StatusNoContent)
 			return
 		}
@@ -33,3 +137,53 @@ func CORS(origin string) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// RequireContentType middleware que rejeita POST/PUT/PATCH com Content-Type fora da lista com 415
+// Requisições sem body são liberadas, já que não há conteúdo para interpretar
+func RequireContentType(types ...string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(types))
+	for _, t := range types {
+		allowed[strings.ToLower(t)] = true
+	}
+
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+		default:
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength == 0 {
+			c.Next()
+			return
+		}
+
+		if !allowed[strings.ToLower(c.ContentType())] {
+			apiErr := NewUnsupportedMediaTypeError("Unsupported Content-Type, expected: " + strings.Join(types, ", "))
+			c.AbortWithStatusJSON(apiErr.Code, errorEnvelope(c, apiErr))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireRole middleware que exige que o usuário autenticado tenha uma das roles informadas
+// A role vem do custom claim ClaimRole (veja SetupFirebaseAuth)
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Sem role nunca passa, mesmo que "" esteja entre as roles informadas
+		if role := c.GetString(AuthRoleKey); role != "" {
+			for _, allowed := range roles {
+				if role == allowed {
+					c.Next()
+					return
+				}
+			}
+		}
+
+		apiErr := NewForbiddenError("Permissão insuficiente para acessar este recurso")
+		c.AbortWithStatusJSON(apiErr.Code, errorEnvelope(c, apiErr))
+	}
+}
@@ -11,12 +11,26 @@ import (
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var uuidType = reflect.TypeOf(uuid.UUID{})
 
+// IDEncoder converte um UUID para o valor BSON usado nos filtros do Repository
+type IDEncoder func(uuid.UUID) interface{}
+
+// UUIDBinaryEncoder formato padrão: binary subtype 4, o mesmo gravado pelo registry de NewMongoRegistry
+func UUIDBinaryEncoder(id uuid.UUID) interface{} {
+	return primitive.Binary{Subtype: bsontype.BinaryUUID, Data: id[:]}
+}
+
+// UUIDStringEncoder para collections que guardam os IDs como string
+func UUIDStringEncoder(id uuid.UUID) interface{} {
+	return id.String()
+}
+
 type uuidCodec struct{}
 
 func (uc *uuidCodec) EncodeValue(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
@@ -79,8 +93,19 @@ type MongoConnectConfig struct {
 	MinPoolSize             uint64
 	MaxConnIdleTime         time.Duration
 	ServerSelectionTimeout  time.Duration
+	SocketTimeout           time.Duration
 }
 
+// Defaults de conexão aplicados quando o campo não é informado
+const (
+	DefaultMongoMaxPoolSize            = 10
+	DefaultMongoMinPoolSize            = 2
+	DefaultMongoMaxConnIdleTime        = 5 * time.Minute
+	DefaultMongoServerSelectionTimeout = 5 * time.Second
+	DefaultMongoSocketTimeout          = 30 * time.Second
+	DefaultMongoDisconnectTimeout      = 10 * time.Second
+)
+
 // MongoConnect cria uma conexão MongoDB com suporte nativo a UUID v4 e ObjectID.
 //
 // Uso:
@@ -90,40 +115,82 @@ type MongoConnectConfig struct {
 //	    Database: "myapp",
 //	})
 func MongoConnect(ctx context.Context, cfg MongoConnectConfig) (*mongo.Database, error) {
-	if cfg.MaxPoolSize == 0 {
-		cfg.MaxPoolSize = 10
+	client, _, err := NewMongoClient(ctx, cfg)
+	if err != nil {
+		return nil, err
 	}
-	if cfg.MinPoolSize == 0 {
-		cfg.MinPoolSize = 2
+
+	return client.Database(cfg.Database), nil
+}
+
+// NewMongoClient cria um client MongoDB com pool e timeouts de produção e retorna
+// uma função de cleanup que desconecta o client.
+//
+// Uso:
+//
+//	client, cleanup, err := zendia.NewMongoClient(ctx, zendia.MongoConnectConfig{
+//	    URI: "mongodb://localhost:27017",
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer cleanup()
+func NewMongoClient(ctx context.Context, cfg MongoConnectConfig) (*mongo.Client, func(), error) {
+	client, err := mongo.Connect(ctx, mongoClientOptions(cfg))
+	if err != nil {
+		return nil, nil, fmt.Errorf("mongodb connection failed: %w", err)
 	}
-	if cfg.MaxConnIdleTime == 0 {
-		cfg.MaxConnIdleTime = 5 * time.Minute
+
+	cleanup := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), DefaultMongoDisconnectTimeout)
+		defer cancel()
+		client.Disconnect(ctx)
 	}
-	if cfg.ServerSelectionTimeout == 0 {
-		cfg.ServerSelectionTimeout = 5 * time.Second
+
+	if err := client.Ping(ctx, nil); err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("mongodb ping failed: %w", err)
 	}
 
+	return client, cleanup, nil
+}
+
+// NewMongoRegistry registry BSON do framework: uuid.UUID como binary subtype 4
+// Use ao criar o client fora de MongoConnect/NewMongoClient para gravar no mesmo formato dos filtros
+//
+//	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetRegistry(zendia.NewMongoRegistry()))
+func NewMongoRegistry() *bsoncodec.Registry {
 	codec := &uuidCodec{}
 	reg := bson.NewRegistry()
 	reg.RegisterTypeEncoder(uuidType, codec)
 	reg.RegisterTypeDecoder(uuidType, codec)
+	return reg
+}
 
-	clientOpts := options.Client().
+// mongoClientOptions monta as options do driver aplicando os defaults do framework
+func mongoClientOptions(cfg MongoConnectConfig) *options.ClientOptions {
+	if cfg.MaxPoolSize == 0 {
+		cfg.MaxPoolSize = DefaultMongoMaxPoolSize
+	}
+	if cfg.MinPoolSize == 0 {
+		cfg.MinPoolSize = DefaultMongoMinPoolSize
+	}
+	if cfg.MaxConnIdleTime == 0 {
+		cfg.MaxConnIdleTime = DefaultMongoMaxConnIdleTime
+	}
+	if cfg.ServerSelectionTimeout == 0 {
+		cfg.ServerSelectionTimeout = DefaultMongoServerSelectionTimeout
+	}
+	if cfg.SocketTimeout == 0 {
+		cfg.SocketTimeout = DefaultMongoSocketTimeout
+	}
+
+	return options.Client().
 		ApplyURI(cfg.URI).
-		SetRegistry(reg).
+		SetRegistry(NewMongoRegistry()).
 		SetMaxPoolSize(cfg.MaxPoolSize).
 		SetMinPoolSize(cfg.MinPoolSize).
 		SetMaxConnIdleTime(cfg.MaxConnIdleTime).
-		SetServerSelectionTimeout(cfg.ServerSelectionTimeout)
-
-	client, err := mongo.Connect(ctx, clientOpts)
-	if err != nil {
-		return nil, fmt.Errorf("mongodb connection failed: %w", err)
-	}
-
-	if err := client.Ping(ctx, nil); err != nil {
-		return nil, fmt.Errorf("mongodb ping failed: %w", err)
-	}
-
-	return client.Database(cfg.Database), nil
+		SetServerSelectionTimeout(cfg.ServerSelectionTimeout).
+		SetSocketTimeout(cfg.SocketTimeout)
 }
@@ -2,16 +2,23 @@ package zendia
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"log"
-	"reflect"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
+	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 // RepositoryConfig configuração do repository
@@ -21,6 +28,22 @@ type RepositoryConfig struct {
 	historyCol *mongo.Collection
 	entityType string
 	ttlField   string
+	maxResults int
+
+	isolationKeys []string
+	idEncoder     IDEncoder
+	cursorField   string
+	readPref      *readpref.ReadPref
+	readConcern   *readconcern.ReadConcern
+	indexHint     interface{}
+	tenantHint    bool
+	idGenerator   func() uuid.UUID
+	opTimeout     time.Duration
+
+	deleteHook         interface{}
+	deleteHookRollback bool
+
+	validator *Validator
 }
 
 // RepositoryOption função para configurar o repository
@@ -49,6 +72,118 @@ func WithTTL(field string) RepositoryOption {
 	}
 }
 
+// WithMaxResults define o máximo de documentos retornados por GetAll sem paginação
+// Acima do limite a query falha com BadRequest; 0 desabilita (padrão: DefaultMaxResults)
+func WithMaxResults(max int) RepositoryOption {
+	return func(c *RepositoryConfig) {
+		c.maxResults = max
+	}
+}
+
+// WithIsolationKeys isola os documentos por chaves extras além do tenant_id, na ordem dada
+// Os valores vêm de TenantInfo.Extra; cada key é também o nome do campo no documento.
+// Sem esta option o isolamento continua apenas por tenant_id. Requer WithAudit e entidades
+// que implementem IsolatedEntity; chave ausente no contexto falha fechado (BadRequest).
+//
+//	repo := zendia.NewRepository[*User](col, zendia.WithAudit(), zendia.WithIsolationKeys("org_id"))
+func WithIsolationKeys(keys ...string) RepositoryOption {
+	return func(c *RepositoryConfig) {
+		c.isolationKeys = keys
+	}
+}
+
+// WithIDEncoder define como _id e tenant_id são convertidos nos filtros (padrão: UUIDBinaryEncoder)
+// Deve seguir o formato em que o registry do client grava os UUIDs das entidades,
+// ex: UUIDStringEncoder para collections que guardam os IDs como string
+func WithIDEncoder(encoder IDEncoder) RepositoryOption {
+	return func(c *RepositoryConfig) {
+		c.idEncoder = encoder
+	}
+}
+
+// WithCursorField define o campo de ordenação do GetAfter (padrão: "_id")
+// O _id é usado como desempate, então o campo não precisa ser único (ex: "created.set_at")
+func WithCursorField(field string) RepositoryOption {
+	return func(c *RepositoryConfig) {
+		c.cursorField = field
+	}
+}
+
+// WithReadPreference define o read preference das leituras do repository
+// Ex: readpref.SecondaryPreferred() para tirar relatórios e listagens pesadas do primário
+func WithReadPreference(rp *readpref.ReadPref) RepositoryOption {
+	return func(c *RepositoryConfig) {
+		c.readPref = rp
+	}
+}
+
+// WithReadConcern define o read concern padrão das leituras do repository
+// Ex: readconcern.Majority() para não ler dados que ainda podem sofrer rollback
+func WithReadConcern(rc *readconcern.ReadConcern) RepositoryOption {
+	return func(c *RepositoryConfig) {
+		c.readConcern = rc
+	}
+}
+
+// WithIndexHint força o índice das consultas por tenant (GetAll, GetAllSkipTake, GetAfter,
+// GetDeleted e Count): nome do índice (string) ou as chaves (bson.D). Só vale com WithAudit.
+// Útil em collections grandes com tenants desbalanceados, onde o planner pode escolher um
+// índice menos seletivo. Confira o plano com explain:
+//
+//	db.users.find({active: true, tenant_id: UUID("...")}).explain("executionStats")
+//	// winningPlan.inputStage.indexName deve ser o índice do hint
+func WithIndexHint(hint interface{}) RepositoryOption {
+	return func(c *RepositoryConfig) {
+		c.indexHint = hint
+		c.tenantHint = false
+	}
+}
+
+// WithTenantIndexHint usa como hint o índice composto criado pelo repository
+// (active, tenant_id e chaves de isolamento, ver TenantIndexKeys)
+func WithTenantIndexHint() RepositoryOption {
+	return func(c *RepositoryConfig) {
+		c.indexHint = nil
+		c.tenantHint = true
+	}
+}
+
+// WithIDGenerator define como Create, BulkCreate, Upsert e ImportTenant geram o _id
+// de entidades sem ID (padrão: uuid.New, UUIDv4). IDs ordenados por tempo, como UUIDv7,
+// são inseridos sempre no fim do índice de _id: menos page splits na B-tree e páginas
+// quentes em cache, o que acelera inserts em collections grandes.
+//
+//	// github.com/google/uuid >= v1.6
+//	repo := zendia.NewRepository[*User](col, zendia.WithIDGenerator(func() uuid.UUID {
+//	    return uuid.Must(uuid.NewV7())
+//	}))
+func WithIDGenerator(generator func() uuid.UUID) RepositoryOption {
+	return func(c *RepositoryConfig) {
+		c.idGenerator = generator
+	}
+}
+
+// WithOperationTimeout limita cada operação do repository a d, cortando a query no driver
+// Estourado o prazo a operação falha com 504 (GatewayTimeout), sem esperar o timeout
+// do servidor. Um deadline menor já presente no ctx continua valendo. Stream,
+// ExportTenant e ImportTenant não usam o limite, pois percorrem o tenant inteiro.
+//
+//	repo := zendia.NewRepository[*User](col, zendia.WithOperationTimeout(5*time.Second))
+func WithOperationTimeout(d time.Duration) RepositoryOption {
+	return func(c *RepositoryConfig) {
+		c.opTimeout = d
+	}
+}
+
+// WithValidator define o Validator usado por PatchValidated (padrão: um Validator compartilhado
+// com as mensagens padrão). Passe app.GetValidator() para reusar as validações e mensagens
+// customizadas cadastradas no framework.
+func WithValidator(v *Validator) RepositoryOption {
+	return func(c *RepositoryConfig) {
+		c.validator = v
+	}
+}
+
 // Repository implementação unificada para MongoDB
 type Repository[T MongoAuditableEntity] struct {
 	collection *mongo.Collection
@@ -64,16 +199,28 @@ type Repository[T MongoAuditableEntity] struct {
 //	repo := zendia.NewRepository[*User](collection, zendia.WithAudit())
 //	repo := zendia.NewRepository[*User](collection, zendia.WithAudit(), zendia.WithHistory(historyCol, "User"))
 func NewRepository[T MongoAuditableEntity](collection *mongo.Collection, opts ...RepositoryOption) *Repository[T] {
-	cfg := RepositoryConfig{}
+	cfg := RepositoryConfig{maxResults: DefaultMaxResults}
 	for _, opt := range opts {
 		opt(&cfg)
 	}
 
+	if collOpts := readOptions(cfg.readPref, cfg.readConcern); collOpts != nil && collection != nil {
+		cloned, err := collection.Clone(collOpts)
+		if err != nil {
+			log.Printf("⚠️  Failed to apply read options for %s: %v", collection.Name(), err)
+		} else {
+			collection = cloned
+		}
+	}
+
 	var hm *HistoryManager
 	if cfg.history && cfg.historyCol != nil {
 		hm = NewHistoryManager(cfg.historyCol)
+		hm.idEncoder = cfg.idEncoder
 	}
 
+	checkDeleteHook[T](cfg)
+
 	repo := &Repository[T]{
 		collection: collection,
 		config:     cfg,
@@ -87,13 +234,18 @@ func NewRepository[T MongoAuditableEntity](collection *mongo.Collection, opts ..
 }
 
 func (r *Repository[T]) Create(ctx context.Context, entity T) (T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	if entity.GetID() == uuid.Nil {
-		entity.SetID(uuid.New())
+		entity.SetID(r.newID())
 	}
 
 	if r.config.audit {
-		tenantInfo := GetTenantInfo(ctx)
-		entity.SetTenantID(tenantInfo.TenantID)
+		tenantInfo, err := r.stampTenant(ctx, entity)
+		if err != nil {
+			return entity, err
+		}
 
 		if ae, ok := any(entity).(AuditableEntity); ok {
 			info := r.buildAuditInfo(tenantInfo)
@@ -106,21 +258,26 @@ func (r *Repository[T]) Create(ctx context.Context, entity T) (T, error) {
 	_, err := r.collection.InsertOne(ctx, entity)
 	if err != nil {
 		var zero T
-		return zero, NewInternalError("Failed to create entity: " + err.Error())
+		return zero, internalError("Failed to create entity", err)
 	}
 
 	return entity, nil
 }
 
 func (r *Repository[T]) GetByID(ctx context.Context, id uuid.UUID) (T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	var entity T
 	filter := bson.M{
-		"_id":    id,
+		"_id":    r.idToBSON(id),
 		"active": true,
 	}
 
 	if r.config.audit {
-		r.injectTenantFilter(ctx, filter)
+		if err := r.injectTenantFilter(ctx, filter); err != nil {
+			return entity, err
+		}
 	}
 
 	err := r.collection.FindOne(ctx, filter).Decode(&entity)
@@ -128,22 +285,27 @@ func (r *Repository[T]) GetByID(ctx context.Context, id uuid.UUID) (T, error) {
 		if err == mongo.ErrNoDocuments {
 			return entity, NewNotFoundError("Entity not found")
 		}
-		return entity, NewInternalError("Failed to get entity: " + err.Error())
+		return entity, internalError("Failed to get entity", err)
 	}
 
 	return entity, nil
 }
 
 func (r *Repository[T]) GetFirst(ctx context.Context, filters map[string]interface{}) (T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	var entity T
 	filter := bson.M{"active": true}
 
 	if r.config.audit {
-		r.injectTenantFilter(ctx, filter)
+		if err := r.injectTenantFilter(ctx, filter); err != nil {
+			return entity, err
+		}
 	}
 
-	for k, v := range filters {
-		filter[k] = v
+	if err := r.mergeFilters(filter, filters); err != nil {
+		return entity, err
 	}
 
 	err := r.collection.FindOne(ctx, filter).Decode(&entity)
@@ -151,13 +313,16 @@ func (r *Repository[T]) GetFirst(ctx context.Context, filters map[string]interfa
 		if err == mongo.ErrNoDocuments {
 			return entity, NewNotFoundError("No entity found")
 		}
-		return entity, NewInternalError("Failed to get first entity: " + err.Error())
+		return entity, internalError("Failed to get first entity", err)
 	}
 
 	return entity, nil
 }
 
 func (r *Repository[T]) Update(ctx context.Context, id uuid.UUID, entity T) (T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	// Se history está habilitado, busca o estado anterior
 	var before T
 	if r.config.history && r.history != nil {
@@ -169,17 +334,21 @@ func (r *Repository[T]) Update(ctx context.Context, id uuid.UUID, entity T) (T,
 	}
 
 	if r.config.audit {
-		tenantInfo := GetTenantInfo(ctx)
-		entity.SetTenantID(tenantInfo.TenantID)
+		tenantInfo, err := r.stampTenant(ctx, entity)
+		if err != nil {
+			return entity, err
+		}
 
 		if ae, ok := any(entity).(AuditableEntity); ok {
 			ae.SetUpdated(r.buildAuditInfo(tenantInfo))
 		}
 	}
 
-	filter := bson.M{"_id": id}
+	filter := bson.M{"_id": r.idToBSON(id)}
 	if r.config.audit {
-		r.injectTenantFilter(ctx, filter)
+		if err := r.injectTenantFilter(ctx, filter); err != nil {
+			return entity, err
+		}
 	}
 
 	update := bson.M{"$set": entity}
@@ -191,7 +360,7 @@ func (r *Repository[T]) Update(ctx context.Context, id uuid.UUID, entity T) (T,
 		if err == mongo.ErrNoDocuments {
 			return updated, NewNotFoundError("Entity not found")
 		}
-		return updated, NewInternalError("Failed to update entity: " + err.Error())
+		return updated, internalError("Failed to update entity", err)
 	}
 
 	// Registra histórico se habilitado
@@ -202,7 +371,221 @@ func (r *Repository[T]) Update(ctx context.Context, id uuid.UUID, entity T) (T,
 	return updated, nil
 }
 
+// Patch atualiza apenas os campos informados ($set parcial), preservando os demais
+// Semântica de HTTP PATCH: campos omitidos não são zerados e edições parciais concorrentes
+// em campos diferentes não se sobrescrevem. Campos gerenciados (_id, tenant_id, active,
+// created/updated/deleted e chaves de isolamento) são rejeitados; a auditoria regrava updated.
+func (r *Repository[T]) Patch(ctx context.Context, id uuid.UUID, fields map[string]interface{}) (T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var zero T
+	if err := r.validatePatch(fields); err != nil {
+		return zero, err
+	}
+
+	var before T
+	if r.config.history && r.history != nil {
+		var err error
+		before, err = r.GetByID(ctx, id)
+		if err != nil {
+			return zero, err
+		}
+	}
+
+	set := bson.M{}
+	for k, v := range fields {
+		set[k] = v
+	}
+
+	filter := bson.M{"_id": r.idToBSON(id), "active": true}
+	if r.config.audit {
+		if err := r.injectTenantFilter(ctx, filter); err != nil {
+			return zero, err
+		}
+		set["updated"] = r.buildAuditInfo(GetTenantInfo(ctx))
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var updated T
+
+	err := r.collection.FindOneAndUpdate(ctx, filter, bson.M{"$set": set}, opts).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return zero, NewNotFoundError("Entity not found")
+		}
+		return zero, internalError("Failed to patch entity", err)
+	}
+
+	if r.config.history && r.history != nil {
+		r.history.RecordChanges(ctx, id, r.config.entityType, "Patch", before, updated)
+	}
+
+	return updated, nil
+}
+
+// PatchValidated aplica fields sobre uma cópia recém-buscada da entidade, valida as tags
+// validate do resultado e só então grava com Patch. Um PATCH não consegue violar
+// min/max/email: o erro volta como o do Bind no PUT (400, VALIDATION_FAILED), com
+// FieldErrors em Details (mensagens no idioma de GetLocale(ctx)). Valor de tipo
+// incompatível com o campo vira um FieldError com a tag "type".
+//
+//	user, err := userRepo.PatchValidated(ctx, id, map[string]interface{}{"email": "x"})
+//	// Details: FieldErrors{{Field: "email", Tag: "email", Message: "email deve ser um email válido"}}
+func (r *Repository[T]) PatchValidated(ctx context.Context, id uuid.UUID, fields map[string]interface{}) (T, error) {
+	var zero T
+	if err := r.validatePatch(fields); err != nil {
+		return zero, err
+	}
+
+	current, err := r.GetByID(ctx, id)
+	if err != nil {
+		return zero, err
+	}
+	merged, err := mergePatch(current, fields)
+	if err != nil {
+		return zero, NewValidationError("Validation failed", patchTypeErrors(current, fields))
+	}
+
+	v := r.config.validator
+	if v == nil {
+		v = sharedPatchValidator()
+	}
+	if err := v.ValidateLocale(merged, GetLocale(ctx)); err != nil {
+		return zero, err
+	}
+
+	return r.Patch(ctx, id, fields)
+}
+
+// patchTypeErrors aplica cada campo isoladamente para apontar os de tipo incompatível
+func patchTypeErrors[T any](entity T, fields map[string]interface{}) FieldErrors {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs FieldErrors
+	for _, key := range keys {
+		if _, err := mergePatch(entity, map[string]interface{}{key: fields[key]}); err != nil {
+			errs = append(errs, FieldError{Field: key, Tag: "type", Message: MsgInvalidPatchField + ": " + key})
+		}
+	}
+	return errs
+}
+
+// sharedPatchValidator Validator padrão do PatchValidated, criado uma única vez
+var sharedPatchValidator = sync.OnceValue(NewValidator)
+
+// mergePatch aplica fields (chaves bson, com ponto para campos aninhados) sobre uma cópia de entity
+// A ida e volta pelo BSON usa o registry do framework, então valores de tipo incompatível falham
+func mergePatch[T any](entity T, fields map[string]interface{}) (T, error) {
+	var merged T
+	reg := NewMongoRegistry()
+
+	raw, err := bson.MarshalWithRegistry(reg, entity)
+	if err != nil {
+		return merged, err
+	}
+	doc := bson.M{}
+	if err := bson.UnmarshalWithRegistry(reg, raw, &doc); err != nil {
+		return merged, err
+	}
+
+	for key, value := range fields {
+		parts := strings.Split(key, ".")
+		node := doc
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := node[part].(bson.M)
+			if !ok {
+				child = bson.M{}
+				node[part] = child
+			}
+			node = child
+		}
+		node[parts[len(parts)-1]] = value
+	}
+
+	raw, err = bson.MarshalWithRegistry(reg, doc)
+	if err != nil {
+		return merged, err
+	}
+	err = bson.UnmarshalWithRegistry(reg, raw, &merged)
+	return merged, err
+}
+
+// validatePatch valida nomes e valores do Patch antes de montar o $set
+func (r *Repository[T]) validatePatch(fields map[string]interface{}) error {
+	if len(fields) == 0 {
+		return NewBadRequestError(MsgEmptyPatch)
+	}
+	if err := DefaultFilterGuard.Validate(fields); err != nil {
+		return err
+	}
+
+	protected := []string{"_id", "tenant_id", "active", "created", "updated", "deleted"}
+	protected = append(protected, r.config.isolationKeys...)
+
+	for key, value := range fields {
+		if !isValidFieldName(key) {
+			return NewBadRequestError(MsgInvalidPatchField + ": " + key)
+		}
+		root := strings.SplitN(key, ".", 2)[0]
+		for _, p := range protected {
+			if root == p {
+				return NewBadRequestError(MsgInvalidPatchField + ": " + key)
+			}
+		}
+		if err := validatePatchValue(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validatePatchValue rejeita objetos aninhados com chaves de operador ($) ou com ponto
+func validatePatchValue(key string, value interface{}) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if strings.HasPrefix(k, "$") || strings.Contains(k, ".") {
+				return NewBadRequestError(MsgInvalidPatchField + ": " + key + "." + k)
+			}
+			if err := validatePatchValue(key+"."+k, child); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			if err := validatePatchValue(key, child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (r *Repository[T]) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.softDelete(ctx, id, "")
+}
+
+// DeleteWithReason soft delete registrando o motivo em deleted.reason (exigência de compliance)
+// O motivo é obrigatório; caracteres de controle são removidos e o texto é truncado em
+// MaxDeleteReasonLength. O motivo volta em GetDeleted e some ao restaurar.
+// Sem WithAudit grava apenas deleted.set_at e deleted.reason.
+func (r *Repository[T]) DeleteWithReason(ctx context.Context, id uuid.UUID, reason string) error {
+	reason = sanitizeReason(reason)
+	if reason == "" {
+		return NewBadRequestError(MsgDeleteReasonRequired)
+	}
+	return r.softDelete(ctx, id, reason)
+}
+
+func (r *Repository[T]) softDelete(ctx context.Context, id uuid.UUID, reason string) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	if r.config.audit {
 		entity, err := r.GetByID(ctx, id)
 		if err != nil {
@@ -211,20 +594,51 @@ func (r *Repository[T]) Delete(ctx context.Context, id uuid.UUID) error {
 
 		if ae, ok := any(entity).(AuditableEntity); ok {
 			tenantInfo := GetTenantInfo(ctx)
-			ae.SetDeleted(r.buildAuditInfo(tenantInfo))
+			info := r.buildAuditInfo(tenantInfo)
+			info.Active = false
+			info.Reason = reason
+			ae.SetDeleted(info)
 			ae.SetActive(false)
-			_, err = r.Update(ctx, id, entity)
+			deleted, err := r.Update(ctx, id, entity)
+			if err != nil {
+				return err
+			}
+			if hook := r.deleteHook(); hook != nil {
+				return r.notifyDeleteHook(ctx, "OnSoftDelete", deleted, hook.OnSoftDelete, r.revertSoftDelete(id))
+			}
+			return nil
+		}
+	}
+
+	// Sem audit (ou entidade sem AuditableEntity): soft delete simples
+	filter := bson.M{"_id": r.idToBSON(id), "active": true}
+	if r.config.audit {
+		if err := r.injectTenantFilter(ctx, filter); err != nil {
 			return err
 		}
 	}
+	fields := bson.M{"active": false}
+	if reason != "" {
+		fields["deleted"] = AuditInfo{SetAt: time.Now(), Reason: reason}
+	}
+	update := bson.M{"$set": fields}
 
-	// Sem audit: soft delete simples
-	filter := bson.M{"_id": id, "active": true}
-	update := bson.M{"$set": bson.M{"active": false}}
+	// Com hook a entidade deletada é lida na mesma escrita
+	if hook := r.deleteHook(); hook != nil {
+		var deleted T
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+		if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&deleted); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return NewNotFoundError("Entity not found or already deleted")
+			}
+			return internalError("Failed to delete entity", err)
+		}
+		return r.notifyDeleteHook(ctx, "OnSoftDelete", deleted, hook.OnSoftDelete, r.revertSoftDelete(id))
+	}
 
 	result, err := r.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
-		return NewInternalError("Failed to delete entity: " + err.Error())
+		return internalError("Failed to delete entity", err)
 	}
 	if result.ModifiedCount == 0 {
 		return NewNotFoundError("Entity not found or already deleted")
@@ -233,117 +647,305 @@ func (r *Repository[T]) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// sanitizeReason remove caracteres de controle (quebras de linha viram espaço) e limita o tamanho
+func sanitizeReason(reason string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\r' || r == '\t':
+			return ' '
+		case unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, reason)
+	cleaned = strings.TrimSpace(cleaned)
+
+	if runes := []rune(cleaned); len(runes) > MaxDeleteReasonLength {
+		cleaned = strings.TrimSpace(string(runes[:MaxDeleteReasonLength]))
+	}
+	return cleaned
+}
+
 // GetByIDs busca múltiplos documentos por lista de IDs
 func (r *Repository[T]) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	if len(ids) == 0 {
 		return []T{}, nil
 	}
 
 	filter := bson.M{
-		"_id":    bson.M{"$in": ids},
+		"_id":    bson.M{"$in": r.idsToBSON(ids)},
 		"active": true,
 	}
 
 	if r.config.audit {
-		r.injectTenantFilter(ctx, filter)
+		if err := r.injectTenantFilter(ctx, filter); err != nil {
+			return nil, err
+		}
 	}
 
 	cursor, err := r.collection.Find(ctx, filter)
 	if err != nil {
-		return nil, NewInternalError("Failed to get entities by IDs: " + err.Error())
+		return nil, internalError("Failed to get entities by IDs", err)
 	}
 	defer cursor.Close(ctx)
 
-	var entities []T
-	if err = cursor.All(ctx, &entities); err != nil {
-		return nil, NewInternalError("Failed to decode entities: " + err.Error())
+	entities, err := decodeCursor[T](ctx, cursor)
+	if err != nil {
+		return nil, internalError("Failed to decode entities", err)
 	}
 
 	return entities, nil
 }
 
 func (r *Repository[T]) GetAll(ctx context.Context, filters map[string]interface{}, opts ...*QueryOptions) ([]T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	filter := bson.M{"active": true}
 
 	if r.config.audit {
-		r.injectTenantFilter(ctx, filter)
+		if err := r.injectTenantFilter(ctx, filter); err != nil {
+			return nil, err
+		}
 	}
 
-	for k, v := range filters {
-		filter[k] = v
+	if err := r.mergeFilters(filter, filters); err != nil {
+		return nil, err
+	}
+
+	collection, err := r.readCollection(opts...)
+	if err != nil {
+		return nil, err
 	}
 
 	findOpts := options.Find()
 	r.applyQueryOptions(findOpts, opts...)
+	r.applyIndexHint(findOpts)
+	r.limitResults(findOpts)
 
-	cursor, err := r.collection.Find(ctx, filter, findOpts)
+	cursor, err := collection.Find(ctx, filter, findOpts)
 	if err != nil {
-		return nil, NewInternalError("Failed to get entities: " + err.Error())
+		return nil, internalError("Failed to get entities", err)
 	}
 	defer cursor.Close(ctx)
 
-	var entities []T
-	if err = cursor.All(ctx, &entities); err != nil {
-		return nil, NewInternalError("Failed to decode entities: " + err.Error())
+	entities, err := decodeCursor[T](ctx, cursor)
+	if err != nil {
+		return nil, internalError("Failed to decode entities", err)
+	}
+
+	if err := r.checkResultSize(len(entities)); err != nil {
+		return nil, err
 	}
 
 	return entities, nil
 }
 
 func (r *Repository[T]) GetAllSkipTake(ctx context.Context, filters map[string]interface{}, pagination Pagination, opts ...*QueryOptions) ([]T, int64, error) {
-	pagination = ResolvePagination(pagination)
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	pagination = ResolvePaginationContext(ctx, pagination)
 
 	filter := bson.M{"active": true}
 
 	if r.config.audit {
-		r.injectTenantFilter(ctx, filter)
+		if err := r.injectTenantFilter(ctx, filter); err != nil {
+			return nil, 0, err
+		}
 	}
 
-	for k, v := range filters {
-		filter[k] = v
+	if err := r.mergeFilters(filter, filters); err != nil {
+		return nil, 0, err
 	}
 
-	count, err := r.collection.CountDocuments(ctx, filter)
+	collection, err := r.readCollection(opts...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	count, err := collection.CountDocuments(ctx, filter, r.countOptions())
 	if err != nil {
-		return nil, 0, NewInternalError("Failed to count entities: " + err.Error())
+		return nil, 0, internalError("Failed to count entities", err)
 	}
 
 	findOpts := options.Find().SetSkip(int64(pagination.Skip)).SetLimit(int64(pagination.Take))
 	r.applyQueryOptions(findOpts, opts...)
+	r.applyIndexHint(findOpts)
 
-	cursor, err := r.collection.Find(ctx, filter, findOpts)
+	cursor, err := collection.Find(ctx, filter, findOpts)
 	if err != nil {
-		return nil, 0, NewInternalError("Failed to get entities: " + err.Error())
+		return nil, 0, internalError("Failed to get entities", err)
 	}
 	defer cursor.Close(ctx)
 
-	var entities []T
-	if err = cursor.All(ctx, &entities); err != nil {
-		return nil, 0, NewInternalError("Failed to decode entities: " + err.Error())
+	entities, err := decodeCursor[T](ctx, cursor)
+	if err != nil {
+		return nil, 0, internalError("Failed to decode entities", err)
 	}
 
 	return entities, count, nil
 }
 
+// GetAfter paginação por cursor (keyset): retorna até limit entidades depois do cursor
+// e o cursor da próxima página ("" quando acabou). Diferente de skip/take, não degrada
+// em collections grandes nem pula/duplica itens quando há inserções entre as páginas.
+//
+//	items, next, err := repo.GetAfter(ctx, filters, "", 50)   // primeira página
+//	items, next, err = repo.GetAfter(ctx, filters, next, 50)  // próxima
+func (r *Repository[T]) GetAfter(ctx context.Context, filters map[string]interface{}, cursor string, limit int) ([]T, string, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	limit = ResolvePaginationContext(ctx, Pagination{Take: limit}).Take
+	field := r.config.cursorField
+	if field == "" {
+		field = "_id"
+	}
+
+	filter := bson.M{"active": true}
+	if r.config.audit {
+		if err := r.injectTenantFilter(ctx, filter); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := r.mergeFilters(filter, filters); err != nil {
+		return nil, "", err
+	}
+
+	if cursor != "" {
+		after, err := keysetFilter(field, cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		filter = bson.M{"$and": bson.A{filter, after}}
+	}
+
+	sort := bson.D{{Key: field, Value: 1}}
+	if field != "_id" {
+		sort = append(sort, bson.E{Key: "_id", Value: 1})
+	}
+	// limit+1 indica se existe próxima página sem uma query extra
+	findOpts := options.Find().SetSort(sort).SetLimit(int64(limit + 1))
+	r.applyIndexHint(findOpts)
+
+	cur, err := r.collection.Find(ctx, filter, findOpts)
+	if err != nil {
+		return nil, "", internalError("Failed to get entities", err)
+	}
+	defer cur.Close(ctx)
+
+	var entities []T
+	var last bson.Raw
+	for len(entities) < limit && cur.Next(ctx) {
+		if err := ctx.Err(); err != nil {
+			return nil, "", internalError("Failed to decode entities", err)
+		}
+		var entity T
+		if err := cur.Decode(&entity); err != nil {
+			return nil, "", internalError("Failed to decode entities", err)
+		}
+		entities = append(entities, entity)
+		last = append(bson.Raw(nil), cur.Current...)
+	}
+	hasMore := len(entities) == limit && cur.Next(ctx)
+	if err := cur.Err(); err != nil {
+		return nil, "", internalError("Failed to decode entities", err)
+	}
+
+	if !hasMore {
+		return entities, "", nil
+	}
+	next, err := encodeKeysetCursor(last, field)
+	if err != nil {
+		return nil, "", internalError("Failed to encode cursor", err)
+	}
+	return entities, next, nil
+}
+
+// Stream itera o cursor documento a documento, enviando cada entidade no canal
+// Evita carregar o resultado inteiro em memória (ex: exportações). Os dois canais
+// são fechados ao fim; o canal de erros recebe no máximo um erro (query, decode,
+// cursor ou cancelamento do ctx) e deve ser lido depois que items fechar
+func (r *Repository[T]) Stream(ctx context.Context, filters map[string]interface{}) (<-chan T, <-chan error) {
+	items := make(chan T)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(items)
+
+		filter := bson.M{"active": true}
+
+		if r.config.audit {
+			if err := r.injectTenantFilter(ctx, filter); err != nil {
+				errs <- err
+				return
+			}
+		}
+
+		if err := r.mergeFilters(filter, filters); err != nil {
+			errs <- err
+			return
+		}
+
+		cursor, err := r.collection.Find(ctx, filter)
+		if err != nil {
+			errs <- internalError("Failed to get entities", err)
+			return
+		}
+		defer cursor.Close(context.Background())
+
+		for cursor.Next(ctx) {
+			var entity T
+			if err := cursor.Decode(&entity); err != nil {
+				errs <- internalError("Failed to decode entity", err)
+				return
+			}
+
+			select {
+			case items <- entity:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+
+		if err := cursor.Err(); err != nil {
+			errs <- internalError("Failed to iterate entities", err)
+		}
+	}()
+
+	return items, errs
+}
+
 func (r *Repository[T]) List(ctx context.Context, filters map[string]interface{}, opts ...*QueryOptions) ([]T, error) {
 	return r.GetAll(ctx, filters, opts...)
 }
 
 // Count retorna o total de documentos que correspondem aos filtros
 func (r *Repository[T]) Count(ctx context.Context, filters map[string]interface{}) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	filter := bson.M{"active": true}
 
 	if r.config.audit {
-		r.injectTenantFilter(ctx, filter)
+		if err := r.injectTenantFilter(ctx, filter); err != nil {
+			return 0, err
+		}
 	}
 
-	for k, v := range filters {
-		filter[k] = v
+	if err := r.mergeFilters(filter, filters); err != nil {
+		return 0, err
 	}
 
-	count, err := r.collection.CountDocuments(ctx, filter)
+	count, err := r.collection.CountDocuments(ctx, filter, r.countOptions())
 	if err != nil {
-		return 0, NewInternalError("Failed to count entities: " + err.Error())
+		return 0, internalError("Failed to count entities", err)
 	}
 
 	return count, nil
@@ -351,53 +953,132 @@ func (r *Repository[T]) Count(ctx context.Context, filters map[string]interface{
 
 // CountAll retorna o total incluindo deletados
 func (r *Repository[T]) CountAll(ctx context.Context, filters map[string]interface{}) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	filter := bson.M{}
 
 	if r.config.audit {
-		r.injectTenantFilter(ctx, filter)
+		if err := r.injectTenantFilter(ctx, filter); err != nil {
+			return 0, err
+		}
 	}
 
-	for k, v := range filters {
-		filter[k] = v
+	if err := r.mergeFilters(filter, filters); err != nil {
+		return 0, err
 	}
 
 	count, err := r.collection.CountDocuments(ctx, filter)
 	if err != nil {
-		return 0, NewInternalError("Failed to count entities: " + err.Error())
+		return 0, internalError("Failed to count entities", err)
 	}
 
 	return count, nil
 }
 
-func (r *Repository[T]) Aggregate(ctx context.Context, pipeline []interface{}) ([]T, error) {
-	matchFilter := bson.M{"active": true}
+// CountBy conta as entidades ativas do tenant agrupadas pelo valor de field, numa única agregação
+// Retorna no máximo MaxCountByGroups grupos, os de maior contagem. Valores ausentes ou null
+// ficam na chave "" e UUIDs viram string.
+//
+//	byStatus, err := userRepo.CountBy(ctx, "status", nil)
+//	// map[active:120 blocked:3 pending:17]
+func (r *Repository[T]) CountBy(ctx context.Context, field string, filters map[string]interface{}) (map[string]int64, error) {
+	if !isValidFieldName(field) {
+		return nil, NewBadRequestError(MsgInvalidFilterField + ": " + field)
+	}
+
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	filter := bson.M{"active": true}
 
 	if r.config.audit {
-		r.injectTenantFilter(ctx, matchFilter)
+		if err := r.injectTenantFilter(ctx, filter); err != nil {
+			return nil, err
+		}
 	}
 
-	auditFilter := bson.M{"$match": matchFilter}
-	fullPipeline := append([]interface{}{auditFilter}, pipeline...)
+	if err := r.mergeFilters(filter, filters); err != nil {
+		return nil, err
+	}
 
-	cursor, err := r.collection.Aggregate(ctx, fullPipeline)
+	pipeline := []interface{}{
+		bson.M{"$match": filter},
+		bson.M{"$group": bson.M{"_id": "$" + field, "count": bson.M{"$sum": 1}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+		bson.M{"$limit": MaxCountByGroups},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
 	if err != nil {
-		return nil, NewInternalError("Failed to aggregate: " + err.Error())
+		return nil, internalError("Failed to count entities by group", err)
 	}
 	defer cursor.Close(ctx)
 
-	var results []T
-	if err = cursor.All(ctx, &results); err != nil {
-		return nil, NewInternalError("Failed to decode aggregate results: " + err.Error())
+	groups, err := decodeCursor[struct {
+		Value interface{} `bson:"_id"`
+		Count int64       `bson:"count"`
+	}](ctx, cursor)
+	if err != nil {
+		return nil, internalError("Failed to decode group counts", err)
 	}
 
-	return results, nil
+	counts := make(map[string]int64, len(groups))
+	for _, g := range groups {
+		counts[groupKey(g.Value)] += g.Count
+	}
+	return counts, nil
+}
+
+// groupKey converte o _id de um $group na chave do mapa do CountBy
+func groupKey(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case primitive.Binary:
+		if id, err := uuid.FromBytes(v.Data); err == nil {
+			return id.String()
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+func (r *Repository[T]) Aggregate(ctx context.Context, pipeline []interface{}) ([]T, error) {
+	return AggregateInto[T](ctx, r, pipeline)
 }
 
 func (r *Repository[T]) AggregateRaw(ctx context.Context, pipeline []interface{}) ([]map[string]interface{}, error) {
+	return AggregateInto[map[string]interface{}](ctx, r, pipeline)
+}
+
+// AggregateInto executa o pipeline isolado por tenant decodificando os resultados em R
+// Como Aggregate, o primeiro estágio filtra active=true e o tenant do contexto. Útil quando
+// o pipeline muda o formato do documento ($group, $project), o que deixa o []T do Aggregate vazio.
+// Operadores que executam JavaScript ($where, $function, $accumulator) são recusados em qualquer estágio.
+//
+//	type StatusCount struct {
+//	    Status string `bson:"_id"`
+//	    Total  int    `bson:"total"`
+//	}
+//	counts, err := zendia.AggregateInto[StatusCount](ctx, orderRepo, []interface{}{
+//	    bson.M{"$group": bson.M{"_id": "$status", "total": bson.M{"$sum": 1}}},
+//	})
+func AggregateInto[R any, T MongoAuditableEntity](ctx context.Context, r *Repository[T], pipeline []interface{}) ([]R, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	if key := jsFilterOperator(pipeline); key != "" {
+		return nil, NewBadRequestError(MsgInvalidPipeline + ": " + key)
+	}
+
 	matchFilter := bson.M{"active": true}
 
 	if r.config.audit {
-		r.injectTenantFilter(ctx, matchFilter)
+		if err := r.injectTenantFilter(ctx, matchFilter); err != nil {
+			return nil, err
+		}
 	}
 
 	auditFilter := bson.M{"$match": matchFilter}
@@ -405,72 +1086,166 @@ func (r *Repository[T]) AggregateRaw(ctx context.Context, pipeline []interface{}
 
 	cursor, err := r.collection.Aggregate(ctx, fullPipeline)
 	if err != nil {
-		return nil, NewInternalError("Failed to aggregate: " + err.Error())
+		return nil, internalError("Failed to aggregate", err)
 	}
 	defer cursor.Close(ctx)
 
-	var results []map[string]interface{}
-	if err = cursor.All(ctx, &results); err != nil {
-		return nil, NewInternalError("Failed to decode aggregate results: " + err.Error())
+	results, err := decodeCursor[R](ctx, cursor)
+	if err != nil {
+		return nil, internalError("Failed to decode aggregate results", err)
 	}
 
 	return results, nil
 }
 
 func (r *Repository[T]) GetHistory(ctx context.Context, entityID uuid.UUID) ([]HistoryEntry, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	if r.history == nil {
 		return nil, NewBadRequestError("History not enabled for this repository")
 	}
 	return r.history.GetHistory(ctx, entityID)
 }
 
-// GetAllIncludingDeleted busca todos os registros incluindo os deletados
-func (r *Repository[T]) GetAllIncludingDeleted(ctx context.Context, filters map[string]interface{}) ([]T, error) {
+// GetAllIncludingDeleted busca todos os registros incluindo os deletados
+func (r *Repository[T]) GetAllIncludingDeleted(ctx context.Context, filters map[string]interface{}) ([]T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	filter := bson.M{}
+
+	if r.config.audit {
+		if err := r.injectTenantFilter(ctx, filter); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.mergeFilters(filter, filters); err != nil {
+		return nil, err
+	}
+
+	findOpts := options.Find()
+	r.limitResults(findOpts)
+
+	cursor, err := r.collection.Find(ctx, filter, findOpts)
+	if err != nil {
+		return nil, internalError("Failed to get entities", err)
+	}
+	defer cursor.Close(ctx)
+
+	entities, err := decodeCursor[T](ctx, cursor)
+	if err != nil {
+		return nil, internalError("Failed to decode entities", err)
+	}
+
+	if err := r.checkResultSize(len(entities)); err != nil {
+		return nil, err
+	}
+
+	return entities, nil
+}
+
+// FlaggedEntity entidade do GetAllWithDeletedFlag com a indicação de soft delete
+type FlaggedEntity[T any] struct {
+	Entity      T          `json:"entity"`
+	Deleted     bool       `json:"deleted"`
+	DeletedInfo *AuditInfo `json:"deleted_info,omitempty"` // Quem, quando e o motivo (DeleteWithReason)
+}
+
+// GetAllWithDeletedFlag busca ativos e deletados do tenant, indicando quais foram deletados
+// Para telas admin que mostram os registros deletados esmaecidos em vez de omiti-los.
+// Deleted vem do campo active=false do documento, não da entidade, então vale também
+// para entidades que não expõem o AuditInfo de deleted.
+func (r *Repository[T]) GetAllWithDeletedFlag(ctx context.Context, filters map[string]interface{}) ([]FlaggedEntity[T], error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	filter := bson.M{}
 
 	if r.config.audit {
-		r.injectTenantFilter(ctx, filter)
+		if err := r.injectTenantFilter(ctx, filter); err != nil {
+			return nil, err
+		}
 	}
 
-	for k, v := range filters {
-		filter[k] = v
+	if err := r.mergeFilters(filter, filters); err != nil {
+		return nil, err
 	}
 
-	cursor, err := r.collection.Find(ctx, filter)
+	findOpts := options.Find()
+	r.limitResults(findOpts)
+
+	cursor, err := r.collection.Find(ctx, filter, findOpts)
 	if err != nil {
-		return nil, NewInternalError("Failed to get entities: " + err.Error())
+		return nil, internalError("Failed to get entities", err)
 	}
 	defer cursor.Close(ctx)
 
-	var entities []T
-	if err = cursor.All(ctx, &entities); err != nil {
-		return nil, NewInternalError("Failed to decode entities: " + err.Error())
+	var items []FlaggedEntity[T]
+	for cursor.Next(ctx) {
+		var item FlaggedEntity[T]
+		var state struct {
+			Active  *bool      `bson:"active"`
+			Deleted *AuditInfo `bson:"deleted"`
+		}
+		if err := cursor.Decode(&item.Entity); err != nil {
+			return nil, internalError("Failed to decode entities", err)
+		}
+		if err := cursor.Decode(&state); err != nil {
+			return nil, internalError("Failed to decode entities", err)
+		}
+		item.Deleted = state.Active != nil && !*state.Active
+		if item.Deleted {
+			item.DeletedInfo = state.Deleted
+		}
+		items = append(items, item)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, internalError("Failed to decode entities", err)
 	}
 
-	return entities, nil
+	if err := r.checkResultSize(len(items)); err != nil {
+		return nil, err
+	}
+
+	return items, nil
 }
 
 // GetDeleted busca apenas registros deletados (active=false)
 func (r *Repository[T]) GetDeleted(ctx context.Context, filters map[string]interface{}) ([]T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	filter := bson.M{"active": false}
 
 	if r.config.audit {
-		r.injectTenantFilter(ctx, filter)
+		if err := r.injectTenantFilter(ctx, filter); err != nil {
+			return nil, err
+		}
 	}
 
-	for k, v := range filters {
-		filter[k] = v
+	if err := r.mergeFilters(filter, filters); err != nil {
+		return nil, err
 	}
 
-	cursor, err := r.collection.Find(ctx, filter)
+	findOpts := options.Find()
+	r.applyIndexHint(findOpts)
+	r.limitResults(findOpts)
+
+	cursor, err := r.collection.Find(ctx, filter, findOpts)
 	if err != nil {
-		return nil, NewInternalError("Failed to get deleted entities: " + err.Error())
+		return nil, internalError("Failed to get deleted entities", err)
 	}
 	defer cursor.Close(ctx)
 
-	var entities []T
-	if err = cursor.All(ctx, &entities); err != nil {
-		return nil, NewInternalError("Failed to decode deleted entities: " + err.Error())
+	entities, err := decodeCursor[T](ctx, cursor)
+	if err != nil {
+		return nil, internalError("Failed to decode deleted entities", err)
+	}
+
+	if err := r.checkResultSize(len(entities)); err != nil {
+		return nil, err
 	}
 
 	return entities, nil
@@ -478,15 +1253,36 @@ func (r *Repository[T]) GetDeleted(ctx context.Context, filters map[string]inter
 
 // HardDelete remove permanentemente do banco
 func (r *Repository[T]) HardDelete(ctx context.Context, id uuid.UUID) error {
-	filter := bson.M{"_id": id}
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	filter := bson.M{"_id": r.idToBSON(id)}
 
 	if r.config.audit {
-		r.injectTenantFilter(ctx, filter)
+		if err := r.injectTenantFilter(ctx, filter); err != nil {
+			return err
+		}
+	}
+
+	if hook := r.deleteHook(); hook != nil {
+		result := r.collection.FindOneAndDelete(ctx, filter)
+		doc, err := result.DecodeBytes()
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return NewNotFoundError("Entity not found")
+			}
+			return internalError("Failed to hard delete entity", err)
+		}
+		var removed T
+		if err := result.Decode(&removed); err != nil {
+			return internalError("Failed to decode deleted entity", err)
+		}
+		return r.notifyDeleteHook(ctx, "OnHardDelete", removed, hook.OnHardDelete, r.revertHardDelete(doc))
 	}
 
 	result, err := r.collection.DeleteOne(ctx, filter)
 	if err != nil {
-		return NewInternalError("Failed to hard delete entity: " + err.Error())
+		return internalError("Failed to hard delete entity", err)
 	}
 	if result.DeletedCount == 0 {
 		return NewNotFoundError("Entity not found")
@@ -497,22 +1293,49 @@ func (r *Repository[T]) HardDelete(ctx context.Context, id uuid.UUID) error {
 
 // Restore restaura um registro soft deleted
 func (r *Repository[T]) Restore(ctx context.Context, id uuid.UUID) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	filter := bson.M{
-		"_id":    id,
+		"_id":    r.idToBSON(id),
 		"active": false,
 	}
 
 	if r.config.audit {
-		r.injectTenantFilter(ctx, filter)
+		if err := r.injectTenantFilter(ctx, filter); err != nil {
+			return err
+		}
 	}
 
-	update := bson.M{
-		"$set": bson.M{"active": true},
+	update, updated := r.restoreUpdate(ctx)
+
+	// Com hook lê o documento anterior: o deleted removido volta se o rollback reverter
+	if hook := r.deleteHook(); hook != nil {
+		result := r.collection.FindOneAndUpdate(ctx, filter, update)
+		before, err := result.DecodeBytes()
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return NewNotFoundError("Entity not found or not deleted")
+			}
+			return internalError("Failed to restore entity", err)
+		}
+		var restored T
+		if err := result.Decode(&restored); err != nil {
+			return internalError("Failed to decode restored entity", err)
+		}
+		if ae, ok := any(restored).(AuditableEntity); ok {
+			ae.SetActive(true)
+			ae.SetDeleted(AuditInfo{})
+			if updated != nil {
+				ae.SetUpdated(*updated)
+			}
+		}
+		return r.notifyDeleteHook(ctx, "OnRestore", restored, hook.OnRestore, r.revertRestore(id, before))
 	}
 
 	result, err := r.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
-		return NewInternalError("Failed to restore entity: " + err.Error())
+		return internalError("Failed to restore entity", err)
 	}
 	if result.ModifiedCount == 0 {
 		return NewNotFoundError("Entity not found or not deleted")
@@ -521,30 +1344,94 @@ func (r *Repository[T]) Restore(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// RestoreMany restaura em lote registros soft deleted do tenant, retornando quantos foram restaurados
+// Só registros deletados (active=false) são afetados; ids inexistentes ou de outro tenant são ignorados
+func (r *Repository[T]) RestoreMany(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	filter := bson.M{
+		"_id":    bson.M{"$in": r.idsToBSON(ids)},
+		"active": false,
+	}
+
+	if r.config.audit {
+		if err := r.injectTenantFilter(ctx, filter); err != nil {
+			return 0, err
+		}
+	}
+
+	if r.deleteHook() != nil {
+		return r.restoreEach(ctx, ids)
+	}
+
+	update, _ := r.restoreUpdate(ctx)
+
+	result, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, internalError("Failed to restore entities", err)
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// restoreUpdate monta o update comum a Restore e RestoreMany
+// O deleted (quem, quando e o motivo) não fica no documento ativo; com audit o updated
+// registra quem restaurou e é retornado para refletir na entidade entregue ao hook
+func (r *Repository[T]) restoreUpdate(ctx context.Context) (bson.M, *AuditInfo) {
+	set := bson.M{"active": true}
+	update := bson.M{
+		"$set":   set,
+		"$unset": bson.M{"deleted": ""},
+	}
+
+	if !r.config.audit {
+		return update, nil
+	}
+	updated := r.buildAuditInfo(GetTenantInfo(ctx))
+	set["updated"] = updated
+	return update, &updated
+}
+
 // DeleteMany soft delete múltiplos registros
 func (r *Repository[T]) DeleteMany(ctx context.Context, filters map[string]interface{}) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	filter := bson.M{"active": true}
 
 	if r.config.audit {
-		r.injectTenantFilter(ctx, filter)
+		if err := r.injectTenantFilter(ctx, filter); err != nil {
+			return 0, err
+		}
 	}
 
-	for k, v := range filters {
-		filter[k] = v
+	if err := r.mergeFilters(filter, filters); err != nil {
+		return 0, err
+	}
+
+	if r.deleteHook() != nil {
+		return r.deleteEach(ctx, filter)
 	}
 
 	updateFields := bson.M{"active": false}
 
 	if r.config.audit {
 		tenantInfo := GetTenantInfo(ctx)
-		updateFields["deleted"] = r.buildAuditInfo(tenantInfo)
+		info := r.buildAuditInfo(tenantInfo)
+		info.Active = false
+		updateFields["deleted"] = info
 	}
 
 	update := bson.M{"$set": updateFields}
 
 	result, err := r.collection.UpdateMany(ctx, filter, update)
 	if err != nil {
-		return 0, NewInternalError("Failed to delete entities: " + err.Error())
+		return 0, internalError("Failed to delete entities", err)
 	}
 
 	return result.ModifiedCount, nil
@@ -552,14 +1439,19 @@ func (r *Repository[T]) DeleteMany(ctx context.Context, filters map[string]inter
 
 // UpdateMany atualiza múltiplos documentos que correspondem aos filtros
 func (r *Repository[T]) UpdateMany(ctx context.Context, filters map[string]interface{}, fields map[string]interface{}) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	filter := bson.M{"active": true}
 
 	if r.config.audit {
-		r.injectTenantFilter(ctx, filter)
+		if err := r.injectTenantFilter(ctx, filter); err != nil {
+			return 0, err
+		}
 	}
 
-	for k, v := range filters {
-		filter[k] = v
+	if err := r.mergeFilters(filter, filters); err != nil {
+		return 0, err
 	}
 
 	if r.config.audit {
@@ -571,7 +1463,7 @@ func (r *Repository[T]) UpdateMany(ctx context.Context, filters map[string]inter
 
 	result, err := r.collection.UpdateMany(ctx, filter, update)
 	if err != nil {
-		return 0, NewInternalError("Failed to update entities: " + err.Error())
+		return 0, internalError("Failed to update entities", err)
 	}
 
 	return result.ModifiedCount, nil
@@ -579,24 +1471,17 @@ func (r *Repository[T]) UpdateMany(ctx context.Context, filters map[string]inter
 
 // BulkCreate insere múltiplos documentos de uma vez
 func (r *Repository[T]) BulkCreate(ctx context.Context, entities []T) ([]T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	if len(entities) == 0 {
 		return entities, nil
 	}
 
 	docs := make([]interface{}, len(entities))
 	for i, entity := range entities {
-		if entity.GetID() == uuid.Nil {
-			entity.SetID(uuid.New())
-		}
-		if r.config.audit {
-			tenantInfo := GetTenantInfo(ctx)
-			entity.SetTenantID(tenantInfo.TenantID)
-			if ae, ok := any(entity).(AuditableEntity); ok {
-				info := r.buildAuditInfo(tenantInfo)
-				ae.SetCreated(info)
-				ae.SetUpdated(info)
-				ae.SetActive(true)
-			}
+		if err := r.prepareInsert(ctx, entity); err != nil {
+			return nil, err
 		}
 		entities[i] = entity
 		docs[i] = entity
@@ -604,21 +1489,64 @@ func (r *Repository[T]) BulkCreate(ctx context.Context, entities []T) ([]T, erro
 
 	_, err := r.collection.InsertMany(ctx, docs)
 	if err != nil {
-		return nil, NewInternalError("Failed to bulk create entities: " + err.Error())
+		return nil, internalError("Failed to bulk create entities", err)
 	}
 
 	return entities, nil
 }
 
+// withTimeout aplica o WithOperationTimeout ao ctx da operação
+func (r *Repository[T]) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.config.opTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, r.config.opTimeout)
+}
+
+// newID gera o ID de uma nova entidade com o gerador configurado
+func (r *Repository[T]) newID() uuid.UUID {
+	if r.config.idGenerator != nil {
+		return r.config.idGenerator()
+	}
+	return uuid.New()
+}
+
+// prepareInsert gera o ID e, com audit, carimba tenant, created/updated e active
+func (r *Repository[T]) prepareInsert(ctx context.Context, entity T) error {
+	if entity.GetID() == uuid.Nil {
+		entity.SetID(r.newID())
+	}
+	if !r.config.audit {
+		return nil
+	}
+
+	tenantInfo, err := r.stampTenant(ctx, entity)
+	if err != nil {
+		return err
+	}
+	if ae, ok := any(entity).(AuditableEntity); ok {
+		info := r.buildAuditInfo(tenantInfo)
+		ae.SetCreated(info)
+		ae.SetUpdated(info)
+		ae.SetActive(true)
+	}
+	return nil
+}
+
 // Upsert cria ou atualiza um documento baseado nos filtros
 func (r *Repository[T]) Upsert(ctx context.Context, filters map[string]interface{}, entity T) (T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	if entity.GetID() == uuid.Nil {
-		entity.SetID(uuid.New())
+		entity.SetID(r.newID())
 	}
 
 	if r.config.audit {
-		tenantInfo := GetTenantInfo(ctx)
-		entity.SetTenantID(tenantInfo.TenantID)
+		tenantInfo, err := r.stampTenant(ctx, entity)
+		if err != nil {
+			return entity, err
+		}
 		if ae, ok := any(entity).(AuditableEntity); ok {
 			info := r.buildAuditInfo(tenantInfo)
 			ae.SetUpdated(info)
@@ -628,10 +1556,12 @@ func (r *Repository[T]) Upsert(ctx context.Context, filters map[string]interface
 
 	filter := bson.M{}
 	if r.config.audit {
-		r.injectTenantFilter(ctx, filter)
+		if err := r.injectTenantFilter(ctx, filter); err != nil {
+			return entity, err
+		}
 	}
-	for k, v := range filters {
-		filter[k] = v
+	if err := r.mergeFilters(filter, filters); err != nil {
+		return entity, err
 	}
 
 	opts := options.FindOneAndUpdate().
@@ -641,7 +1571,7 @@ func (r *Repository[T]) Upsert(ctx context.Context, filters map[string]interface
 	var result T
 	err := r.collection.FindOneAndUpdate(ctx, filter, bson.M{"$set": entity}, opts).Decode(&result)
 	if err != nil {
-		return result, NewInternalError("Failed to upsert entity: " + err.Error())
+		return result, internalError("Failed to upsert entity", err)
 	}
 
 	return result, nil
@@ -649,19 +1579,24 @@ func (r *Repository[T]) Upsert(ctx context.Context, filters map[string]interface
 
 // ExistsBy verifica se existe algum documento que corresponde aos filtros
 func (r *Repository[T]) ExistsBy(ctx context.Context, filters map[string]interface{}) (bool, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	filter := bson.M{"active": true}
 
 	if r.config.audit {
-		r.injectTenantFilter(ctx, filter)
+		if err := r.injectTenantFilter(ctx, filter); err != nil {
+			return false, err
+		}
 	}
 
-	for k, v := range filters {
-		filter[k] = v
+	if err := r.mergeFilters(filter, filters); err != nil {
+		return false, err
 	}
 
 	count, err := r.collection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
 	if err != nil {
-		return false, NewInternalError("Failed to check existence: " + err.Error())
+		return false, internalError("Failed to check existence", err)
 	}
 
 	return count > 0, nil
@@ -669,26 +1604,286 @@ func (r *Repository[T]) ExistsBy(ctx context.Context, filters map[string]interfa
 
 // --- helpers ---
 
+// encodeKeysetCursor codifica o valor do campo e o _id do último documento em base64 opaco
+func encodeKeysetCursor(doc bson.Raw, field string) (string, error) {
+	id, err := doc.LookupErr("_id")
+	if err != nil {
+		return "", err
+	}
+	value, err := doc.LookupErr(strings.Split(field, ".")...)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := bson.Marshal(bson.D{{Key: "v", Value: value}, {Key: "id", Value: id}})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// keysetFilter monta o filtro "depois do cursor": field > v, ou field == v com _id > id
+func keysetFilter(field, cursor string) (bson.M, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, NewBadRequestError(MsgInvalidCursor)
+	}
+	raw := bson.Raw(data)
+	if raw.Validate() != nil {
+		return nil, NewBadRequestError(MsgInvalidCursor)
+	}
+	value, errV := raw.LookupErr("v")
+	id, errID := raw.LookupErr("id")
+	if errV != nil || errID != nil {
+		return nil, NewBadRequestError(MsgInvalidCursor)
+	}
+
+	if field == "_id" {
+		return bson.M{"_id": bson.M{"$gt": id}}, nil
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{field: bson.M{"$gt": value}},
+		bson.M{field: value, "_id": bson.M{"$gt": id}},
+	}}, nil
+}
+
+// decodeCursor decodifica o cursor documento a documento, conferindo o ctx entre eles
+// Um cliente que desconecta no meio de um scan longo interrompe a iteração na hora
+func decodeCursor[E any](ctx context.Context, cursor *mongo.Cursor) ([]E, error) {
+	var items []E
+	for cursor.Next(ctx) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var item E
+		if err := cursor.Decode(&item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return items, cursor.Err()
+}
+
 func (r *Repository[T]) buildAuditInfo(tenantInfo TenantInfo) AuditInfo {
-	var userID uuid.UUID
-	if tenantInfo.UserID != "" {
-		userID = uuid.MustParse(tenantInfo.UserID)
+	return newAuditInfo(tenantInfo)
+}
+
+// idToBSON converte um UUID (_id, tenant_id) para o valor dos filtros
+// Ponto único de conversão: todas as queries usam o mesmo formato
+func (r *Repository[T]) idToBSON(id uuid.UUID) interface{} {
+	if r.config.idEncoder == nil {
+		return UUIDBinaryEncoder(id)
 	}
-	return AuditInfo{
-		SetAt:  tenantInfo.ActionAt,
-		ByName: tenantInfo.UserName,
-		ByID:   userID,
+	return r.config.idEncoder(id)
+}
+
+// idsToBSON converte uma lista de IDs para uso em $in
+func (r *Repository[T]) idsToBSON(ids []uuid.UUID) []interface{} {
+	values := make([]interface{}, len(ids))
+	for i, id := range ids {
+		values[i] = r.idToBSON(id)
 	}
+	return values
 }
 
-func (r *Repository[T]) injectTenantFilter(ctx context.Context, filter bson.M) {
+// stampTenant grava o tenant e as chaves de isolamento do contexto na entidade
+func (r *Repository[T]) stampTenant(ctx context.Context, entity T) (TenantInfo, error) {
 	tenantInfo := GetTenantInfo(ctx)
-	if tenantInfo.TenantID != "" {
-		tenantUUID, err := uuid.Parse(tenantInfo.TenantID)
-		if err == nil {
-			filter["tenant_id"] = tenantUUID
+	if _, err := requireTenant(ctx); err != nil {
+		return tenantInfo, err
+	}
+	values, err := isolationValues(ctx, r.config.isolationKeys)
+	if err != nil {
+		return tenantInfo, err
+	}
+	entity.SetTenantID(tenantInfo.TenantID)
+
+	if len(values) > 0 {
+		ie, ok := any(entity).(IsolatedEntity)
+		if !ok {
+			return tenantInfo, NewInternalError("Entity must implement IsolatedEntity to use WithIsolationKeys")
+		}
+		for i, key := range r.config.isolationKeys {
+			ie.SetIsolationKey(key, values[i])
+		}
+	}
+	return tenantInfo, nil
+}
+
+// requireTenant tenant do contexto como UUID; ausente responde 401 e mal formatado 400
+func requireTenant(ctx context.Context) (uuid.UUID, error) {
+	tenantUUID, err := ParseTenantID(GetTenantID(ctx))
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if tenantUUID == uuid.Nil {
+		return uuid.Nil, NewUnauthorizedError(MsgLoginRequired)
+	}
+	return tenantUUID, nil
+}
+
+// filterJSOperators operadores que executam JavaScript no servidor, recusados em qualquer filtro
+var filterJSOperators = map[string]bool{"$where": true, "$function": true, "$accumulator": true}
+
+// mergeFilters copia os filtros do chamador para o filtro da query
+// Todos os métodos que recebem filters passam por aqui: operadores que executam JavaScript
+// são recusados em qualquer nível, mesmo vindos de código. Os demais ($in, $gt, $or...) seguem liberados.
+// tenant_id e as chaves de isolamento já injetados do contexto não são sobrescritos
+func (r *Repository[T]) mergeFilters(filter bson.M, filters map[string]interface{}) error {
+	if key := jsFilterOperator(filters); key != "" {
+		return NewBadRequestError(MsgInvalidFilterField + ": " + key)
+	}
+	for k, v := range filters {
+		if _, injected := filter[k]; injected && r.isTenantKey(k) {
+			continue
+		}
+		filter[k] = v
+	}
+	return nil
+}
+
+// isTenantKey indica se o campo é o tenant ou uma chave de isolamento do repository
+func (r *Repository[T]) isTenantKey(key string) bool {
+	if key == FieldTenantID {
+		return true
+	}
+	for _, isolationKey := range r.config.isolationKeys {
+		if key == isolationKey {
+			return true
+		}
+	}
+	return false
+}
+
+// jsFilterOperator retorna o primeiro operador de filterJSOperators encontrado em value, ou ""
+func jsFilterOperator(value interface{}) string {
+	return findOperator(value, filterJSOperators)
+}
+
+// findOperator percorre maps, documentos e listas em qualquer profundidade e retorna
+// a primeira chave que está em operators (comparação sem diferenciar maiúsculas), ou ""
+func findOperator(value interface{}, operators map[string]bool) string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range v {
+			if operators[strings.ToLower(key)] {
+				return key
+			}
+			if found := findOperator(nested, operators); found != "" {
+				return found
+			}
 		}
+	case bson.M:
+		return findOperator(map[string]interface{}(v), operators)
+	case bson.D:
+		for _, e := range v {
+			if operators[strings.ToLower(e.Key)] {
+				return e.Key
+			}
+			if found := findOperator(e.Value, operators); found != "" {
+				return found
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if found := findOperator(item, operators); found != "" {
+				return found
+			}
+		}
+	case bson.A:
+		return findOperator([]interface{}(v), operators)
+	}
+	return ""
+}
+
+// injectTenantFilter adiciona o tenant e as chaves de isolamento do contexto ao filtro
+// Único caminho de tenant dos métodos com audit, com a mesma política para todos: tenant
+// ausente responde 401, tenant inválido 400 e chave de isolamento ausente falha fechado.
+// Nunca executa a query sem o filtro
+func (r *Repository[T]) injectTenantFilter(ctx context.Context, filter bson.M) error {
+	tenantUUID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+	values, err := isolationValues(ctx, r.config.isolationKeys)
+	if err != nil {
+		return err
+	}
+
+	filter[FieldTenantID] = r.idToBSON(tenantUUID)
+	for i, key := range r.config.isolationKeys {
+		filter[key] = values[i]
+	}
+	return nil
+}
+
+// limitResults limita o Find a maxResults+1 para detectar quando o resultado excede o limite
+// Um Limit explícito menor que o máximo é mantido
+func (r *Repository[T]) limitResults(findOpts *options.FindOptions) {
+	if r.config.maxResults <= 0 {
+		return
+	}
+	max := int64(r.config.maxResults)
+	if findOpts.Limit != nil && *findOpts.Limit > 0 && *findOpts.Limit <= max {
+		return
+	}
+	findOpts.SetLimit(max + 1)
+}
+
+// checkResultSize retorna BadRequest quando o resultado passou de maxResults
+func (r *Repository[T]) checkResultSize(count int) error {
+	if r.config.maxResults > 0 && count > r.config.maxResults {
+		return NewBadRequestError(fmt.Sprintf("%s (max %d)", MsgTooManyResults, r.config.maxResults))
+	}
+	return nil
+}
+
+// readCollection retorna a collection com o read preference/read concern da chamada
+// Sem override nas QueryOptions usa a collection padrão (já com o padrão do repository)
+func (r *Repository[T]) readCollection(opts ...*QueryOptions) (*mongo.Collection, error) {
+	var rp *readpref.ReadPref
+	var rc *readconcern.ReadConcern
+	for _, qo := range opts {
+		if qo == nil {
+			continue
+		}
+		if qo.ReadPreference != nil {
+			rp = qo.ReadPreference
+		}
+		if qo.ReadConcern != nil {
+			rc = qo.ReadConcern
+		}
+	}
+
+	collOpts := readOptions(rp, rc)
+	if collOpts == nil {
+		return r.collection, nil
+	}
+	collection, err := r.collection.Clone(collOpts)
+	if err != nil {
+		return nil, internalError("Failed to apply read options", err)
+	}
+	return collection, nil
+}
+
+// readOptions monta as options de collection; nil quando não há nada a sobrescrever
+func readOptions(rp *readpref.ReadPref, rc *readconcern.ReadConcern) *options.CollectionOptions {
+	if rp == nil && rc == nil {
+		return nil
 	}
+	collOpts := options.Collection()
+	if rp != nil {
+		collOpts.SetReadPreference(rp)
+	}
+	if rc != nil {
+		collOpts.SetReadConcern(rc)
+	}
+	return collOpts
 }
 
 func (r *Repository[T]) applyQueryOptions(findOpts *options.FindOptions, opts ...*QueryOptions) {
@@ -711,18 +1906,56 @@ func (r *Repository[T]) applyQueryOptions(findOpts *options.FindOptions, opts ..
 	findOpts.SetSort(bson.D{{Key: order.By, Value: int(order.At)}})
 }
 
+// TenantIndexKeys chaves do índice composto criado com WithAudit: active, tenant_id
+// e as chaves de isolamento, nesta ordem (nil sem audit)
+func (r *Repository[T]) TenantIndexKeys() bson.D {
+	if !r.config.audit {
+		return nil
+	}
+	keys := bson.D{
+		{Key: "active", Value: 1},
+		{Key: "tenant_id", Value: 1},
+	}
+	for _, key := range r.config.isolationKeys {
+		keys = append(keys, bson.E{Key: key, Value: 1})
+	}
+	return keys
+}
+
+// indexHintFor hint das consultas por tenant (nil sem WithIndexHint / WithTenantIndexHint ou sem audit)
+func (r *Repository[T]) indexHintFor() interface{} {
+	if !r.config.audit {
+		return nil
+	}
+	if r.config.tenantHint {
+		return r.TenantIndexKeys()
+	}
+	return r.config.indexHint
+}
+
+// applyIndexHint aplica o hint configurado numa busca por tenant
+func (r *Repository[T]) applyIndexHint(findOpts *options.FindOptions) {
+	if hint := r.indexHintFor(); hint != nil {
+		findOpts.SetHint(hint)
+	}
+}
+
+// countOptions opções do CountDocuments com o hint configurado
+func (r *Repository[T]) countOptions() *options.CountOptions {
+	countOpts := options.Count()
+	if hint := r.indexHintFor(); hint != nil {
+		countOpts.SetHint(hint)
+	}
+	return countOpts
+}
+
 func (r *Repository[T]) ensureIndexes() {
 	ctx := context.Background()
 	var indexes []mongo.IndexModel
 
-	// Index composto active + tenant_id quando audit está habilitado
-	if r.config.audit {
-		indexes = append(indexes, mongo.IndexModel{
-			Keys: bson.D{
-				{Key: "active", Value: 1},
-				{Key: "tenant_id", Value: 1},
-			},
-		})
+	// Index composto active + tenant_id (+ chaves de isolamento) quando audit está habilitado
+	if keys := r.TenantIndexKeys(); keys != nil {
+		indexes = append(indexes, mongo.IndexModel{Keys: keys})
 	}
 
 	// TTL index
@@ -747,6 +1980,7 @@ func (r *Repository[T]) ensureIndexes() {
 // InputSanitizer sanitiza input do usuário HTTP
 type InputSanitizer struct {
 	allowedFields map[string]bool
+	guard         FilterGuard
 }
 
 // NewInputSanitizer cria um sanitizador com campos permitidos customizáveis
@@ -762,13 +1996,19 @@ func NewInputSanitizer(allowedFields ...string) *InputSanitizer {
 	for _, f := range allowedFields {
 		fields[f] = true
 	}
-	return &InputSanitizer{allowedFields: fields}
+	return &InputSanitizer{allowedFields: fields, guard: DefaultFilterGuard}
+}
+
+// WithGuard troca os limites de complexidade aplicados pelo Sanitize
+func (s *InputSanitizer) WithGuard(guard FilterGuard) *InputSanitizer {
+	s.guard = guard
+	return s
 }
 
 // Sanitize sanitiza input do usuário
 func (s *InputSanitizer) Sanitize(input map[string]interface{}) (map[string]interface{}, error) {
-	if len(input) > 20 {
-		return nil, fmt.Errorf("too many input fields")
+	if err := s.guard.Validate(input); err != nil {
+		return nil, err
 	}
 
 	sanitized := make(map[string]interface{})
@@ -792,7 +2032,11 @@ func (s *InputSanitizer) isValidField(fieldName string) bool {
 	if s.allowedFields[fieldName] {
 		return true
 	}
+	return isValidFieldName(fieldName)
+}
 
+// isValidFieldName aceita nomes de campo simples ou com ponto, sem operadores nem padrões de injeção
+func isValidFieldName(fieldName string) bool {
 	validPattern := regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.]{0,50}$`)
 	if !validPattern.MatchString(fieldName) {
 		return false
@@ -815,9 +2059,6 @@ func sanitizeFilterValue(value interface{}) (interface{}, error) {
 
 	switch v := value.(type) {
 	case string:
-		if len(v) > 1000 {
-			return nil, fmt.Errorf("string value too long")
-		}
 		dangerousPatterns := []string{"$", "javascript:", "eval(", "function("}
 		for _, pattern := range dangerousPatterns {
 			if strings.Contains(strings.ToLower(v), pattern) {
@@ -830,23 +2071,14 @@ func sanitizeFilterValue(value interface{}) (interface{}, error) {
 	case uuid.UUID:
 		return v, nil
 	case map[string]interface{}:
-		return sanitizeNestedObject(v, 1)
+		return sanitizeNestedObject(v)
 	default:
-		val := reflect.ValueOf(v)
-		if val.Kind() == reflect.Slice || val.Kind() == reflect.Array {
-			if val.Len() > 100 {
-				return nil, fmt.Errorf("array too large")
-			}
-		}
+		// Limites de tamanho e profundidade já validados pelo FilterGuard
 		return v, nil
 	}
 }
 
-func sanitizeNestedObject(obj map[string]interface{}, depth int) (map[string]interface{}, error) {
-	if depth > 3 {
-		return nil, fmt.Errorf("object nesting too deep")
-	}
-
+func sanitizeNestedObject(obj map[string]interface{}) (map[string]interface{}, error) {
 	sanitized := make(map[string]interface{})
 	for key, value := range obj {
 		sanitizedValue, err := sanitizeFilterValue(value)
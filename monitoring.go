@@ -3,8 +3,11 @@ package zendia
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"runtime/debug"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -39,6 +42,12 @@ type EndpointStats struct {
 	LastAccess    time.Time `json:"-"` // Para limpeza
 }
 
+// CacheStats hits e misses do cache de um tipo de entidade (CachedRepository)
+type CacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
 // MetricsSnapshot snapshot das métricas para persistência
 type MetricsSnapshot struct {
 	ID             string                 `bson:"_id" json:"id"`
@@ -64,11 +73,14 @@ type Metrics struct {
 	mu             sync.RWMutex
 	config         MetricsConfig
 	stats          map[string]*EndpointStats
+	cache          map[string]*CacheStats
+	health         *HealthMetrics
 	ActiveRequests int64                     `json:"active_requests"`
 	StartTime      time.Time                 `json:"start_time"`
 	lastCleanup    time.Time
 	lastPersist    time.Time
 	persister      MetricsPersister
+	logger         atomic.Pointer[slog.Logger]
 }
 
 // NewMetrics cria uma nova instância de métricas
@@ -81,6 +93,8 @@ func NewMetricsWithConfig(config MetricsConfig) *Metrics {
 	m := &Metrics{
 		config:      config,
 		stats:       make(map[string]*EndpointStats),
+		cache:       make(map[string]*CacheStats),
+		health:      NewHealthMetrics(0),
 		StartTime:   time.Now(),
 		lastCleanup: time.Now(),
 		lastPersist: time.Now(),
@@ -102,6 +116,36 @@ func (m *Metrics) SetPersister(persister MetricsPersister) {
 	m.persister = persister
 }
 
+// SetLogger define o logger das rotinas em background (limpeza e persistência)
+// Sem logger usa slog.Default(); panics recuperados saem com nível ERROR e o stack trace
+//
+//	metrics.SetLogger(slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+func (m *Metrics) SetLogger(logger *slog.Logger) {
+	m.logger.Store(logger)
+}
+
+// log retorna o logger configurado ou slog.Default()
+func (m *Metrics) log() *slog.Logger {
+	if logger := m.logger.Load(); logger != nil {
+		return logger
+	}
+	return slog.Default()
+}
+
+// runSafely executa fn recuperando panics, que são logados em vez de derrubar a rotina
+func (m *Metrics) runSafely(routine string, fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			m.log().Error("metrics routine panic recovered",
+				"routine", routine,
+				"panic", fmt.Sprint(r),
+				"stack", string(debug.Stack()),
+			)
+		}
+	}()
+	fn()
+}
+
 // DisablePersistence desabilita a persistência de métricas
 func (m *Metrics) DisablePersistence() {
 	m.mu.Lock()
@@ -117,6 +161,14 @@ func (m *Metrics) EnablePersistence() {
 	m.config.EnablePersistence = true
 }
 
+// LastPersist retorna o horário do último snapshot salvo com sucesso
+// Até o primeiro save é o horário de criação das métricas
+func (m *Metrics) LastPersist() time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lastPersist
+}
+
 // RecordRequest registra uma requisição com limites de segurança
 func (m *Metrics) RecordRequest(method, path string, duration time.Duration, statusCode int) {
 	m.mu.Lock()
@@ -145,6 +197,30 @@ func (m *Metrics) RecordRequest(method, path string, duration time.Duration, sta
 	}
 }
 
+// RecordCacheHit registra um hit do cache do tipo de entidade (CacheMetricsSink)
+func (m *Metrics) RecordCacheHit(typeName string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cacheStats(typeName).Hits++
+}
+
+// RecordCacheMiss registra um miss do cache do tipo de entidade (CacheMetricsSink)
+func (m *Metrics) RecordCacheMiss(typeName string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cacheStats(typeName).Misses++
+}
+
+// cacheStats retorna (criando) os contadores do tipo; assume o lock adquirido
+func (m *Metrics) cacheStats(typeName string) *CacheStats {
+	stats := m.cache[typeName]
+	if stats == nil {
+		stats = &CacheStats{}
+		m.cache[typeName] = stats
+	}
+	return stats
+}
+
 // IncrementActive incrementa requisições ativas
 func (m *Metrics) IncrementActive() {
 	m.mu.Lock()
@@ -185,6 +261,8 @@ func (m *Metrics) getStatsLocked() map[string]interface{} {
 		"total_errors":    totalErrs,
 		"error_rate":      errorRate,
 		"endpoints":       m.getEndpointStats(),
+		"cache":           m.getCacheStats(),
+		"health":          m.health.Stats(),
 		"memory": map[string]interface{}{
 			"endpoints_tracked": endpointCount,
 			"max_endpoints":     m.config.MaxEndpoints,
@@ -227,14 +305,7 @@ func (m *Metrics) startCleanupRoutine() {
 	defer ticker.Stop()
 	
 	for range ticker.C {
-		func() {
-			defer func() {
-				if r := recover(); r != nil {
-					fmt.Printf("Metrics cleanup panic: %v\n", r)
-				}
-			}()
-			m.cleanup()
-		}()
+		m.runSafely("cleanup", m.cleanup)
 	}
 }
 
@@ -244,21 +315,7 @@ func (m *Metrics) startPersistenceRoutine() {
 	defer ticker.Stop()
 	
 	for range ticker.C {
-		func() {
-			defer func() {
-				if r := recover(); r != nil {
-					fmt.Printf("Metrics persistence panic recovered: %v\n", r)
-				}
-			}()
-			
-			m.mu.RLock()
-			hasPersister := m.persister != nil
-			m.mu.RUnlock()
-			
-			if hasPersister {
-				m.persistMetrics()
-			}
-		}()
+		m.runSafely("persistence", m.persistMetrics)
 	}
 }
 
@@ -303,14 +360,14 @@ func (m *Metrics) persistMetrics() {
 	select {
 	case err := <-done:
 		if err != nil {
-			fmt.Printf("Failed to persist metrics: %v\n", err)
+			m.log().Error("failed to persist metrics", "error", err.Error())
 		} else {
 			m.mu.Lock()
 			m.lastPersist = time.Now()
 			m.mu.Unlock()
 		}
 	case <-time.After(10 * time.Second):
-		fmt.Printf("Metrics persistence timeout after 10s\n")
+		m.log().Warn("metrics persistence timeout", "timeout", "10s")
 	}
 }
 
@@ -383,6 +440,23 @@ func (m *Metrics) getEndpointStats() map[string]interface{} {
 	return endpoints
 }
 
+// getCacheStats hits, misses e hit rate (%) por tipo de entidade
+func (m *Metrics) getCacheStats() map[string]interface{} {
+	cache := make(map[string]interface{}, len(m.cache))
+	for typeName, stats := range m.cache {
+		hitRate := 0.0
+		if lookups := stats.Hits + stats.Misses; lookups > 0 {
+			hitRate = float64(stats.Hits) / float64(lookups) * 100
+		}
+		cache[typeName] = map[string]interface{}{
+			"hits":     stats.Hits,
+			"misses":   stats.Misses,
+			"hit_rate": hitRate,
+		}
+	}
+	return cache
+}
+
 // Monitoring middleware para coleta de métricas
 func Monitoring(metrics *Metrics) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -401,6 +475,7 @@ func Monitoring(metrics *Metrics) gin.HandlerFunc {
 func (z *Zendia) AddMonitoring() *Metrics {
 	metrics := NewMetrics()
 	z.Use(Monitoring(metrics))
+	z.features[FeatureMonitoring] = true
 	return metrics
 }
 
@@ -420,6 +495,7 @@ func (z *Zendia) AddMonitoringWithPersistence(collection *mongo.Collection) *Met
 		if err := collection.Database().Client().Ping(ctx, nil); err != nil {
 			fmt.Printf("Warning: MongoDB not available, disabling metrics persistence: %v\n", err)
 			z.Use(Monitoring(metrics))
+			z.features[FeatureMonitoring] = true
 			return metrics
 		}
 		
@@ -440,6 +516,7 @@ func (z *Zendia) AddMonitoringWithPersistence(collection *mongo.Collection) *Met
 		
 		// Adiciona endpoints de histórico
 		z.addMetricsHistoryEndpoints(metrics, persister)
+		z.features[FeatureMetricsPersistence] = true
 		
 		fmt.Println("✅ Metrics persistence enabled with MongoDB")
 	} else {
@@ -447,6 +524,7 @@ func (z *Zendia) AddMonitoringWithPersistence(collection *mongo.Collection) *Met
 	}
 	
 	z.Use(Monitoring(metrics))
+	z.features[FeatureMonitoring] = true
 	return metrics
 }
 
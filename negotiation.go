@@ -0,0 +1,235 @@
+package zendia
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Formatos de resposta negociados pelo Respond
+const (
+	MediaTypeJSON = "application/json"
+	MediaTypeXML  = "application/xml"
+	MediaTypeCSV  = "text/csv"
+)
+
+// negotiableMediaTypes formatos do Respond na ordem de preferência do servidor
+var negotiableMediaTypes = []string{MediaTypeJSON, MediaTypeXML, MediaTypeCSV}
+
+// Respond serializa a resposta conforme o header Accept: JSON, XML ou CSV
+// JSON e XML saem no envelope padrão (success, message, data); em XML as listas viram
+// <data><item>...</item></data> e os dados precisam ser structs (encoding/xml não aceita maps).
+// CSV leva só os dados, uma linha por item, com as colunas vindas das tags json (objetos
+// aninhados viram "created.set_at"). Sem Accept, com */* ou formato não suportado, responde JSON.
+//
+//	users, total, err := repo.GetAllSkipTake(ctx, filters, page)
+//	...
+//	c.Respond(http.StatusOK, users) // Accept: text/csv → planilha
+func (c *Context[T]) Respond(code int, data interface{}) {
+	envelope := c.envelope()
+	fields := []xmlField{
+		{envelope.Success, code < http.StatusBadRequest},
+		{envelope.Message, MsgSuccess},
+		{envelope.Data, data},
+	}
+
+	switch negotiateMediaType(c.GetHeader("Accept")) {
+	case MediaTypeXML:
+		body, err := xml.Marshal(xmlEnvelope(fields))
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+		c.Data(code, MediaTypeXML+"; charset=utf-8", append([]byte(xml.Header), body...))
+	case MediaTypeCSV:
+		body, err := encodeCSV(c.marshaler(), data)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+		c.Data(code, MediaTypeCSV+"; charset=utf-8", body)
+	default:
+		response := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			response[field.name] = field.value
+		}
+		c.renderJSON(code, response)
+	}
+}
+
+// negotiateMediaType escolhe o formato de maior q-value no Accept; empates ficam com JSON
+func negotiateMediaType(accept string) string {
+	if strings.TrimSpace(accept) == "" {
+		return MediaTypeJSON
+	}
+
+	qualities := parseAcceptEncoding(accept)
+	best := MediaTypeJSON
+	bestQ := 0.0
+	for _, mediaType := range negotiableMediaTypes {
+		if q := mediaTypeQuality(mediaType, qualities); q > bestQ {
+			best, bestQ = mediaType, q
+		}
+	}
+	return best
+}
+
+// mediaTypeQuality q-value do cliente para o formato: tipo exato, "tipo/*" ou "*/*"
+func mediaTypeQuality(mediaType string, qualities map[string]float64) float64 {
+	candidates := []string{mediaType}
+	if mediaType == MediaTypeXML {
+		candidates = append(candidates, "text/xml")
+	}
+	for _, candidate := range candidates {
+		if q, ok := qualities[candidate]; ok {
+			return q
+		}
+	}
+	if q, ok := qualities[mediaType[:strings.Index(mediaType, "/")]+"/*"]; ok {
+		return q
+	}
+	return qualities["*/*"]
+}
+
+// xmlField chave e valor do envelope XML
+type xmlField struct {
+	name  string
+	value interface{}
+}
+
+// xmlEnvelope envelope da resposta em XML: <response><success>...</success>...</response>
+type xmlEnvelope []xmlField
+
+func (e xmlEnvelope) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{Name: xml.Name{Local: "response"}}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, field := range e {
+		if field.value == nil {
+			continue
+		}
+		if err := encodeXMLValue(enc, field.name, field.value); err != nil {
+			return err
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
+
+// encodeXMLValue escreve value em <name>; listas viram <name><item>...</item></name>
+func encodeXMLValue(enc *xml.Encoder, name string, value interface{}) error {
+	element := xml.StartElement{Name: xml.Name{Local: name}}
+	v := reflect.ValueOf(value)
+	if (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) || v.Type().Elem().Kind() == reflect.Uint8 {
+		return enc.EncodeElement(value, element)
+	}
+
+	if err := enc.EncodeToken(element); err != nil {
+		return err
+	}
+	item := xml.StartElement{Name: xml.Name{Local: "item"}}
+	for i := 0; i < v.Len(); i++ {
+		if err := enc.EncodeElement(v.Index(i).Interface(), item); err != nil {
+			return err
+		}
+	}
+	return enc.EncodeToken(element.End())
+}
+
+// encodeCSV converte data (lista ou objeto) em CSV com cabeçalho
+// Passa pelo JSON para respeitar as tags json e o JSONSerializer configurado
+func encodeCSV(marshal func(interface{}) ([]byte, error), data interface{}) ([]byte, error) {
+	raw, err := marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	items, ok := generic.([]interface{})
+	if !ok {
+		items = []interface{}{generic}
+	}
+
+	rows := make([]map[string]string, 0, len(items))
+	seen := map[string]bool{}
+	var columns []string
+	for _, item := range items {
+		row := map[string]string{}
+		if obj, ok := item.(map[string]interface{}); ok {
+			flattenCSV("", obj, row)
+		} else if item != nil {
+			row["value"] = csvCell(item)
+		}
+		for column := range row {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		}
+		rows = append(rows, row)
+	}
+	sort.Strings(columns)
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(columns); err != nil {
+		return nil, err
+	}
+	record := make([]string, len(columns))
+	for _, row := range rows {
+		for i, column := range columns {
+			record[i] = row[column]
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+// flattenCSV achata objetos aninhados em colunas com ponto ("created.set_at")
+func flattenCSV(prefix string, obj map[string]interface{}, row map[string]string) {
+	for key, value := range obj {
+		column := key
+		if prefix != "" {
+			column = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			flattenCSV(column, nested, row)
+			continue
+		}
+		row[column] = csvCell(value)
+	}
+}
+
+// csvCell formata o valor da célula; arrays vão como JSON
+// Textos iniciados por = + - @ ganham um apóstrofo para não virarem fórmula na planilha
+func csvCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+			return "'" + v
+		}
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprint(v)
+	}
+	raw, _ := json.Marshal(value)
+	return string(raw)
+}
@@ -0,0 +1,163 @@
+package zendia
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PageParamsKey chave dos parâmetros de paginação no gin.Context
+const PageParamsKey = "page_params"
+
+// PageLimitsKey chave dos limites de página da instância no context.Context
+const PageLimitsKey = "page_limits"
+
+// PageLimits take padrão e máximo da instância (ver Zendia.SetPageLimits)
+type PageLimits struct {
+	Default int
+	Max     int
+}
+
+// DefaultPageLimits limites sem Zendia.SetPageLimits
+var DefaultPageLimits = PageLimits{Default: DefaultPageSize, Max: DefaultMaxPageSize}
+
+// WithPageLimits grava os limites no ctx, para repositories usados fora de uma requisição
+func WithPageLimits(ctx context.Context, limits PageLimits) context.Context {
+	return context.WithValue(ctx, PageLimitsKey, limits)
+}
+
+// GetPageLimits retorna os limites do ctx (DefaultPageLimits sem a instância)
+func GetPageLimits(ctx context.Context) PageLimits {
+	if limits, ok := ctx.Value(PageLimitsKey).(PageLimits); ok {
+		return limits
+	}
+	return DefaultPageLimits
+}
+
+// SortOption campo e direção de ordenação (1 ASC, -1 DESC)
+type SortOption struct {
+	Field     string `json:"field"`
+	Direction int64  `json:"direction"`
+}
+
+// PageParams skip/take/sort já validados pelo PaginationMiddleware
+type PageParams struct {
+	Skip int          `json:"skip"`
+	Take int          `json:"take"`
+	Sort []SortOption `json:"sort,omitempty"`
+}
+
+// PaginationConfig defaults e limites do PaginationMiddleware
+type PaginationConfig struct {
+	DefaultTake int      // Take sem query param (padrão: o da instância, ver Zendia.SetPageLimits)
+	MaxTake     int      // Maior take aceito (padrão: o da instância)
+	DefaultSort string   // Ordenação sem query param, no mesmo formato do ?sort= (ex: "-created.set_at")
+	SortFields  []string // Campos aceitos no ?sort=; vazio aceita qualquer nome de campo válido
+}
+
+// PaginationMiddleware lê e valida ?skip=, ?take= e ?sort= uma vez para a rota ou grupo
+// O sort aceita campos separados por vírgula, com "-" para DESC: ?sort=name,-created.set_at
+// Valores inválidos ou campos fora de SortFields respondem 400
+//
+// Uso:
+//
+//	users.GET("", zendia.PaginationMiddleware(zendia.PaginationConfig{
+//	    MaxTake:    100,
+//	    SortFields: []string{"name", "created.set_at"},
+//	}), handler)
+func PaginationMiddleware(config PaginationConfig) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(config.SortFields))
+	for _, field := range config.SortFields {
+		allowed[field] = true
+	}
+
+	return func(c *gin.Context) {
+		params, apiErr := parsePageParams(c, config, allowed)
+		if apiErr != nil {
+			c.AbortWithStatusJSON(apiErr.Code, errorEnvelope(c, apiErr))
+			return
+		}
+		c.Set(PageParamsKey, params)
+		c.Next()
+	}
+}
+
+// PageParams retorna os parâmetros lidos pelo PaginationMiddleware
+// Sem o middleware retorna skip 0 e o take padrão da instância, sem ordenação
+func (c *Context[T]) PageParams() PageParams {
+	if val, exists := c.Get(PageParamsKey); exists {
+		if params, ok := val.(PageParams); ok {
+			return params
+		}
+	}
+	return PageParams{Take: GetPageLimits(c.Request.Context()).Default}
+}
+
+// Pagination converte para o Pagination dos repositories
+func (p PageParams) Pagination() Pagination {
+	return Pagination{Skip: p.Skip, Take: p.Take}
+}
+
+// QueryOptions converte para QueryOptions usando a primeira ordenação
+// (os repositories ordenam por um único campo); nil sem ordenação
+func (p PageParams) QueryOptions() *QueryOptions {
+	if len(p.Sort) == 0 {
+		return nil
+	}
+	return &QueryOptions{Order: Order{By: p.Sort[0].Field, At: p.Sort[0].Direction}}
+}
+
+// parsePageParams lê skip/take/sort; DefaultTake e MaxTake zerados usam os limites da instância
+func parsePageParams(c *gin.Context, config PaginationConfig, allowed map[string]bool) (PageParams, *APIError) {
+	limits := GetPageLimits(c.Request.Context())
+	if config.DefaultTake <= 0 {
+		config.DefaultTake = limits.Default
+	}
+	if config.MaxTake <= 0 {
+		config.MaxTake = limits.Max
+	}
+
+	skip, err := strconv.Atoi(c.DefaultQuery(QuerySkip, "0"))
+	if err != nil || skip < 0 {
+		return PageParams{}, NewBadRequestError(MsgInvalidPagination)
+	}
+
+	take, err := strconv.Atoi(c.DefaultQuery(QueryTake, strconv.Itoa(config.DefaultTake)))
+	if err != nil || take <= 0 || take > config.MaxTake {
+		return PageParams{}, NewBadRequestError(MsgInvalidPagination)
+	}
+
+	sort, invalid := parseSort(c.DefaultQuery(QuerySort, config.DefaultSort), allowed)
+	if invalid != "" {
+		return PageParams{}, NewBadRequestError(MsgInvalidSort + ": " + invalid)
+	}
+
+	return PageParams{Skip: skip, Take: take, Sort: sort}, nil
+}
+
+// parseSort interpreta "name,-created.set_at" em SortOptions
+// Retorna o primeiro campo recusado (nome inválido ou fora de allowed), ou ""
+func parseSort(raw string, allowed map[string]bool) ([]SortOption, string) {
+	var sort []SortOption
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		option := SortOption{Field: part, Direction: 1}
+		if strings.HasPrefix(part, "-") {
+			option = SortOption{Field: part[1:], Direction: -1}
+		} else if strings.HasPrefix(part, "+") {
+			option.Field = part[1:]
+		}
+
+		if !isValidFieldName(option.Field) || (len(allowed) > 0 && !allowed[option.Field]) {
+			return nil, option.Field
+		}
+		sort = append(sort, option)
+	}
+	return sort, ""
+}
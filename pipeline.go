@@ -0,0 +1,203 @@
+package zendia
+
+import (
+	"context"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// pipelineMatchOperators operadores lógicos aceitos na raiz de um Match
+var pipelineMatchOperators = map[string]bool{"$and": true, "$or": true, "$nor": true}
+
+// pipelineMatchDenied operadores recusados em qualquer nível de um Match: além dos que
+// executam JavaScript, $expr abriria o filtro para expressões de agregação arbitrárias
+var pipelineMatchDenied = map[string]bool{"$where": true, "$function": true, "$accumulator": true, "$expr": true}
+
+// pipelineAccumulators acumuladores aceitos no Group
+var pipelineAccumulators = map[string]bool{
+	"$sum": true, "$avg": true, "$min": true, "$max": true,
+	"$first": true, "$last": true, "$push": true, "$addToSet": true, "$count": true,
+}
+
+// PipelineBuilder monta pipelines de agregação com campos validados
+// Só expõe estágios que não escapam do tenant ($lookup, $out, $merge e $unionWith ficam de fora);
+// o filtro de tenant e active é prefixado pelo repository em AggregateBuilder
+//
+// Uso:
+//
+//	p := zendia.Pipeline().
+//	    Match(map[string]interface{}{"status": "paid"}).
+//	    Group("$customer_id", map[string]interface{}{"total": map[string]interface{}{"$sum": "$amount"}}).
+//	    Sort(zendia.SortOption{Field: "total", Direction: -1}).
+//	    Limit(10)
+//	results, err := repo.AggregateBuilder(ctx, p)
+type PipelineBuilder struct {
+	stages []interface{}
+	err    error
+}
+
+// Pipeline inicia um pipeline vazio
+func Pipeline() *PipelineBuilder {
+	return &PipelineBuilder{}
+}
+
+// Match filtra os documentos; chaves da raiz são campos ou $and/$or/$nor
+// $where, $function, $accumulator e $expr são recusados em qualquer profundidade e o
+// filtro passa pelo DefaultFilterGuard (limites de chaves, tamanho e profundidade)
+func (p *PipelineBuilder) Match(filter map[string]interface{}) *PipelineBuilder {
+	for key := range filter {
+		if !pipelineMatchOperators[key] && !isValidFieldName(key) {
+			return p.fail("match field " + key)
+		}
+	}
+	if key := findOperator(filter, pipelineMatchDenied); key != "" {
+		return p.fail("match operator " + key)
+	}
+	if err := DefaultFilterGuard.Validate(filter); err != nil {
+		return p.fail("match filter too complex")
+	}
+	return p.add(bson.M{"$match": filter})
+}
+
+// Group agrupa por id ("$campo", nil ou um map de referências) com os acumuladores informados
+// Cada acumulador é {"nome": {"$sum": "$campo"}}; referências a campos são validadas
+func (p *PipelineBuilder) Group(id interface{}, accumulators map[string]interface{}) *PipelineBuilder {
+	if !validFieldRefs(id) {
+		return p.fail("group id")
+	}
+
+	group := bson.M{"_id": id}
+	for name, acc := range accumulators {
+		op, ok := acc.(map[string]interface{})
+		if !isValidFieldName(name) || strings.Contains(name, ".") || !ok || len(op) != 1 {
+			return p.fail("group accumulator " + name)
+		}
+		for operator, expr := range op {
+			if !pipelineAccumulators[operator] || !validFieldRefs(expr) {
+				return p.fail("group accumulator " + name)
+			}
+		}
+		group[name] = acc
+	}
+	return p.add(bson.M{"$group": group})
+}
+
+// Sort ordena pelos campos na ordem dada (Direction 1 ASC, -1 DESC)
+func (p *PipelineBuilder) Sort(fields ...SortOption) *PipelineBuilder {
+	if len(fields) == 0 {
+		return p.fail("empty sort")
+	}
+	sort := bson.D{}
+	for _, field := range fields {
+		if !isValidFieldName(field.Field) || (field.Direction != 1 && field.Direction != -1) {
+			return p.fail("sort field " + field.Field)
+		}
+		sort = append(sort, bson.E{Key: field.Field, Value: field.Direction})
+	}
+	return p.add(bson.M{"$sort": sort})
+}
+
+// Project inclui (1) ou exclui (0) campos, ou os renomeia com uma referência ("$campo")
+func (p *PipelineBuilder) Project(fields map[string]interface{}) *PipelineBuilder {
+	for name, value := range fields {
+		if !isValidFieldName(name) || !validFieldRefs(value) {
+			return p.fail("project field " + name)
+		}
+	}
+	return p.add(bson.M{"$project": fields})
+}
+
+// Unwind desmembra um campo array em um documento por item
+func (p *PipelineBuilder) Unwind(field string) *PipelineBuilder {
+	if !isValidFieldName(field) {
+		return p.fail("unwind field " + field)
+	}
+	return p.add(bson.M{"$unwind": "$" + field})
+}
+
+// Skip pula os n primeiros documentos
+func (p *PipelineBuilder) Skip(n int64) *PipelineBuilder {
+	if n < 0 {
+		return p.fail("negative skip")
+	}
+	return p.add(bson.M{"$skip": n})
+}
+
+// Limit limita o resultado a n documentos
+func (p *PipelineBuilder) Limit(n int64) *PipelineBuilder {
+	if n <= 0 {
+		return p.fail("limit must be positive")
+	}
+	return p.add(bson.M{"$limit": n})
+}
+
+// Count substitui o resultado por um documento {field: total}
+func (p *PipelineBuilder) Count(field string) *PipelineBuilder {
+	if !isValidFieldName(field) || strings.Contains(field, ".") {
+		return p.fail("count field " + field)
+	}
+	return p.add(bson.M{"$count": field})
+}
+
+// Stages retorna o pipeline no formato do driver, ou BadRequest com o primeiro estágio inválido
+func (p *PipelineBuilder) Stages() ([]interface{}, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return append([]interface{}(nil), p.stages...), nil
+}
+
+func (p *PipelineBuilder) add(stage bson.M) *PipelineBuilder {
+	if p.err == nil {
+		p.stages = append(p.stages, stage)
+	}
+	return p
+}
+
+func (p *PipelineBuilder) fail(reason string) *PipelineBuilder {
+	if p.err == nil {
+		p.err = NewBadRequestError(MsgInvalidPipeline + ": " + reason)
+	}
+	return p
+}
+
+// validFieldRefs valida referências "$campo" em valores, maps e listas
+// Números, booleanos e strings literais passam; operadores ($$vars, $expr...) não
+func validFieldRefs(value interface{}) bool {
+	switch v := value.(type) {
+	case nil, bool, int, int32, int64, float64:
+		return true
+	case string:
+		if strings.HasPrefix(v, "$") {
+			return isValidFieldName(v[1:])
+		}
+		return true
+	case map[string]interface{}:
+		for key, nested := range v {
+			if !isValidFieldName(key) || !validFieldRefs(nested) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		for _, item := range v {
+			if !validFieldRefs(item) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// AggregateBuilder executa o pipeline do PipelineBuilder isolado por tenant
+// Como Aggregate e AggregateRaw, o primeiro estágio filtra active=true, o tenant e as
+// chaves de isolamento do contexto; os resultados vêm como maps (o $group muda o formato)
+func (r *Repository[T]) AggregateBuilder(ctx context.Context, pipeline *PipelineBuilder) ([]map[string]interface{}, error) {
+	stages, err := pipeline.Stages()
+	if err != nil {
+		return nil, err
+	}
+	return r.AggregateRaw(ctx, stages)
+}
@@ -0,0 +1,29 @@
+package zendia
+
+import "strings"
+
+// PublicRoutes libera prefixos de rota da autenticação, em qualquer modo de auth
+// A lista começa com DefaultPublicRoutes (/health, /docs, /swagger e /public) e recebe
+// também os FirebaseAuthConfig.PublicRoutes. Middlewares de auth próprios devem consultar
+// IsPublicRoute para proteger exatamente as mesmas rotas que o SetupFirebaseAuth.
+//
+//	app.PublicRoutes("/auth", "/webhooks")
+func (z *Zendia) PublicRoutes(routes ...string) {
+	for _, route := range routes {
+		if route != "" {
+			z.publicRoutes = append(z.publicRoutes, route)
+		}
+	}
+}
+
+// IsPublicRoute verifica se o path está sob algum dos prefixos de PublicRoutes
+// O prefixo casa por segmento: "/public" libera "/public" e "/public/x", mas não "/publications"
+func (z *Zendia) IsPublicRoute(path string) bool {
+	for _, route := range z.publicRoutes {
+		route = strings.TrimSuffix(route, "/")
+		if path == route || strings.HasPrefix(path, route+"/") {
+			return true
+		}
+	}
+	return false
+}
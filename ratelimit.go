@@ -0,0 +1,138 @@
+package zendia
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitConfig configuração do RateLimit
+type RateLimitConfig struct {
+	Requests int                       // Requisições permitidas por janela e por chave (padrão: DefaultRateLimitRequests)
+	Window   time.Duration             // Duração da janela (padrão: DefaultRateLimitWindow)
+	KeyFunc  func(*gin.Context) string // Chave do cliente (padrão: c.ClientIP())
+}
+
+// RateLimit middleware que limita as requisições por cliente numa janela fixa
+// Cada chamada tem o próprio contador, então pode ser usado no app, num RouteGroup ou
+// numa rota só. Limites empilhados (grupo + rota) valem todos: o mais restrito vence.
+// Acima do limite responde 429 com Retry-After; toda resposta leva X-RateLimit-Limit
+// e X-RateLimit-Remaining. Os contadores ficam em memória, por instância.
+//
+//	api.Use(zendia.RateLimit(zendia.RateLimitConfig{Requests: 300, Window: time.Minute}))
+//	auth.POST("/login",
+//	    zendia.RateLimit(zendia.RateLimitConfig{Requests: 5, Window: time.Minute}), // por IP
+//	    zendia.RateLimit(zendia.RateLimitConfig{Requests: 3, Window: 15 * time.Minute,
+//	        KeyFunc: zendia.RateLimitKeyByIPAndField("email")}), // por IP + conta
+//	    zendia.Handle(loginHandler))
+func RateLimit(config RateLimitConfig) gin.HandlerFunc {
+	if config.Requests <= 0 {
+		config.Requests = DefaultRateLimitRequests
+	}
+	if config.Window <= 0 {
+		config.Window = DefaultRateLimitWindow
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = func(c *gin.Context) string { return c.ClientIP() }
+	}
+
+	limiter := newRateLimiter(config.Requests, config.Window)
+	limit := strconv.Itoa(config.Requests)
+
+	return func(c *gin.Context) {
+		remaining, retryAfter, allowed := limiter.allow(config.KeyFunc(c), time.Now())
+		c.Header(HeaderRateLimitLimit, limit)
+		c.Header(HeaderRateLimitRemaining, strconv.Itoa(remaining))
+
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			apiErr := NewTooManyRequestsError(MsgRateLimitExceeded)
+			c.AbortWithStatusJSON(apiErr.Code, errorEnvelope(c, apiErr))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RateLimitKeyByIPAndField chave por IP + um campo do body JSON (ex: "email" no login)
+// Contra credential stuffing limita as tentativas por conta sem bloquear o IP inteiro;
+// combine com um RateLimit só por IP para barrar quem troca de conta a cada tentativa.
+// O body continua disponível para o handler; sem o campo a chave é só o IP.
+func RateLimitKeyByIPAndField(field string) func(*gin.Context) string {
+	return func(c *gin.Context) string {
+		key := c.ClientIP()
+		if c.Request.Body == nil {
+			return key
+		}
+
+		peek, _ := io.ReadAll(io.LimitReader(c.Request.Body, DefaultRateLimitBodyPeek))
+		c.Request.Body = readCloser{io.MultiReader(bytes.NewReader(peek), c.Request.Body), c.Request.Body}
+
+		var body map[string]interface{}
+		if err := json.Unmarshal(peek, &body); err != nil {
+			return key
+		}
+		if value, ok := body[field].(string); ok && strings.TrimSpace(value) != "" {
+			key += "|" + strings.ToLower(strings.TrimSpace(value))
+		}
+		return key
+	}
+}
+
+// rateLimiter contadores de janela fixa por chave
+type rateLimiter struct {
+	mu        sync.Mutex
+	limit     int
+	window    time.Duration
+	windows   map[string]*rateWindow
+	lastSweep time.Time
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:   limit,
+		window:  window,
+		windows: make(map[string]*rateWindow),
+	}
+}
+
+// allow conta a requisição da chave, retornando as restantes e, se bloqueada, a espera
+func (l *rateLimiter) allow(key string, now time.Time) (int, time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Remove janelas vencidas no máximo uma vez por janela, para a memória não crescer
+	if now.Sub(l.lastSweep) >= l.window {
+		for k, w := range l.windows {
+			if now.Sub(w.start) >= l.window {
+				delete(l.windows, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.window {
+		w = &rateWindow{start: now}
+		l.windows[key] = w
+	}
+
+	if w.count >= l.limit {
+		return 0, w.start.Add(l.window).Sub(now), false
+	}
+	w.count++
+	return l.limit - w.count, 0, true
+}
@@ -0,0 +1,88 @@
+package zendia
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ReadOnlyRepository decorator que rejeita escritas e repassa as leituras
+// Útil para entregar a handlers de relatório um handle que comprovadamente não escreve;
+// combine com WithReadPreference para mandar as leituras para secundários.
+type ReadOnlyRepository[T any] struct {
+	base EntityRepository[T]
+}
+
+// NewReadOnlyRepository cria o decorator somente leitura
+//
+// Uso:
+//
+//	reports := zendia.NewReadOnlyRepository[*Order](
+//		zendia.NewRepository[*Order](col, zendia.WithAudit(), zendia.WithReadPreference(readpref.SecondaryPreferred())),
+//	)
+func NewReadOnlyRepository[T any](base EntityRepository[T]) *ReadOnlyRepository[T] {
+	return &ReadOnlyRepository[T]{base: base}
+}
+
+func (ro *ReadOnlyRepository[T]) Create(ctx context.Context, entity T) (T, error) {
+	var zero T
+	return zero, NewForbiddenError(MsgReadOnlyRepository)
+}
+
+func (ro *ReadOnlyRepository[T]) GetByID(ctx context.Context, id uuid.UUID) (T, error) {
+	return ro.base.GetByID(ctx, id)
+}
+
+func (ro *ReadOnlyRepository[T]) GetFirst(ctx context.Context, filters map[string]interface{}) (T, error) {
+	return ro.base.GetFirst(ctx, filters)
+}
+
+func (ro *ReadOnlyRepository[T]) Update(ctx context.Context, id uuid.UUID, entity T) (T, error) {
+	var zero T
+	return zero, NewForbiddenError(MsgReadOnlyRepository)
+}
+
+func (ro *ReadOnlyRepository[T]) Delete(ctx context.Context, id uuid.UUID) error {
+	return NewForbiddenError(MsgReadOnlyRepository)
+}
+
+func (ro *ReadOnlyRepository[T]) GetAll(ctx context.Context, filters map[string]interface{}, opts ...*QueryOptions) ([]T, error) {
+	return ro.base.GetAll(ctx, filters, opts...)
+}
+
+func (ro *ReadOnlyRepository[T]) GetAllSkipTake(ctx context.Context, filters map[string]interface{}, pagination Pagination, opts ...*QueryOptions) ([]T, int64, error) {
+	return ro.base.GetAllSkipTake(ctx, filters, pagination, opts...)
+}
+
+func (ro *ReadOnlyRepository[T]) List(ctx context.Context, filters map[string]interface{}, opts ...*QueryOptions) ([]T, error) {
+	return ro.base.List(ctx, filters, opts...)
+}
+
+// Aggregate repassa o pipeline; estágios de escrita ($out, $merge) são rejeitados
+func (ro *ReadOnlyRepository[T]) Aggregate(ctx context.Context, pipeline []interface{}) ([]T, error) {
+	if hasWriteStage(pipeline) {
+		return nil, NewForbiddenError(MsgReadOnlyRepository)
+	}
+	return ro.base.Aggregate(ctx, pipeline)
+}
+
+// hasWriteStage detecta estágios que gravam no banco; estágio que não serializa falha fechado
+func hasWriteStage(pipeline []interface{}) bool {
+	for _, stage := range pipeline {
+		raw, err := bson.Marshal(stage)
+		if err != nil {
+			return true
+		}
+		elements, err := bson.Raw(raw).Elements()
+		if err != nil {
+			return true
+		}
+		for _, element := range elements {
+			if key := element.Key(); key == "$out" || key == "$merge" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -1,16 +1,37 @@
 package zendia
 
 import (
+	"context"
 	"time"
 
 	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 // AuditInfo estrutura para informações de auditoria
+// Active indica o estado da entidade após a ação (false em deleted)
 type AuditInfo struct {
 	SetAt  time.Time `bson:"set_at" json:"set_at"`
 	ByName string    `bson:"by_name" json:"by_name"`
 	ByID   uuid.UUID `bson:"by_id" json:"by_id"`
+	Active bool      `bson:"active" json:"active"`
+	Reason string    `bson:"reason,omitempty" json:"reason,omitempty"` // Motivo da ação (ex: DeleteWithReason)
+}
+
+// newAuditInfo monta o AuditInfo da ação a partir do tenant/usuário do contexto
+// Sem ActionAt fixado usa o horário atual, para não gravar timestamp zerado
+func newAuditInfo(tenantInfo TenantInfo) AuditInfo {
+	setAt := tenantInfo.ActionAt
+	if setAt.IsZero() {
+		setAt = time.Now()
+	}
+	return AuditInfo{
+		SetAt:  setAt,
+		ByName: tenantInfo.UserName,
+		ByID:   ParseUserID(tenantInfo.UserID),
+		Active: true,
+	}
 }
 
 // AuditableEntity interface para entidades com auditoria
@@ -29,12 +50,96 @@ type MongoAuditableEntity interface {
 	SetTenantID(string)
 }
 
+// TenantedEntity interface para entidades que expõem o tenant ao qual pertencem
+// Usada para conferir o tenant de entidades que não vieram de uma query filtrada (ex: cache)
+type TenantedEntity interface {
+	GetTenantID() string
+}
+
+// IsolatedEntity interface para entidades isoladas por chaves além do tenant (WithIsolationKeys)
+// A chave é o nome do campo no documento (ex: "org_id")
+type IsolatedEntity interface {
+	SetIsolationKey(key, value string)
+	GetIsolationKey(key string) string
+}
+
+// isolationValues resolve, na ordem configurada, os valores das chaves de isolamento do contexto
+// Chave ausente falha fechado: a operação não roda sem todos os filtros
+func isolationValues(ctx context.Context, keys []string) ([]string, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	extra := GetTenantExtra(ctx)
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		value := extra[key]
+		if value == "" {
+			return nil, NewBadRequestError(MsgMissingIsolationKey + ": " + key)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// checkEntityIsolation garante que a entidade pertence às chaves de isolamento do contexto
+func checkEntityIsolation(ctx context.Context, entity interface{}, keys []string) error {
+	values, err := isolationValues(ctx, keys)
+	if err != nil || len(values) == 0 {
+		return err
+	}
+
+	ie, ok := entity.(IsolatedEntity)
+	if !ok {
+		return NewNotFoundError("Entity not found")
+	}
+	for i, key := range keys {
+		if ie.GetIsolationKey(key) != values[i] {
+			return NewNotFoundError("Entity not found")
+		}
+	}
+	return nil
+}
+
+// checkEntityTenant garante que a entidade pertence ao tenant do contexto
+// Retorna NotFound (e não Forbidden) para não revelar que o ID existe em outro tenant
+func checkEntityTenant(ctx context.Context, entity interface{}) error {
+	tenantID := GetTenantID(ctx)
+	if tenantID == "" {
+		return nil
+	}
+
+	te, ok := entity.(TenantedEntity)
+	if !ok {
+		return nil
+	}
+
+	if !sameTenant(te.GetTenantID(), tenantID) {
+		return NewNotFoundError("Entity not found")
+	}
+	return nil
+}
+
+// sameTenant compara tenant IDs, normalizando quando ambos são UUID
+func sameTenant(a, b string) bool {
+	ua, errA := uuid.Parse(a)
+	ub, errB := uuid.Parse(b)
+	if errA == nil && errB == nil {
+		return ua == ub
+	}
+	return a == b
+}
+
 // QueryOptions opções para queries
 type QueryOptions struct {
 	Order      Order
 	Limit      int64
 	Skip       int64
 	Projection map[string]interface{}
+
+	// MongoDB: sobrescrevem, só nesta chamada, o padrão do repository
+	// (WithReadPreference/WithReadConcern). Ignorados pelo SQLRepository.
+	ReadPreference *readpref.ReadPref
+	ReadConcern    *readconcern.ReadConcern
 }
 
 type Order struct {
@@ -53,9 +158,20 @@ type Between struct {
 	End   time.Time
 }
 
+// ResolvePagination aplica os limites padrão (DefaultPageSize e DefaultMaxPageSize)
 func ResolvePagination(pagination Pagination) Pagination {
+	return resolvePagination(pagination, DefaultPageLimits)
+}
+
+// ResolvePaginationContext aplica os limites do ctx (Zendia.SetPageLimits), ou os padrão
+// É o que GetAllSkipTake e GetAfter usam: take acima do máximo é reduzido ao máximo
+func ResolvePaginationContext(ctx context.Context, pagination Pagination) Pagination {
+	return resolvePagination(pagination, GetPageLimits(ctx))
+}
+
+func resolvePagination(pagination Pagination, limits PageLimits) Pagination {
 	if pagination.Take <= 0 {
-		pagination.Take = 10
+		pagination.Take = limits.Default
 	}
 
 	if pagination.Skip <= 0 {
@@ -63,8 +179,8 @@ func ResolvePagination(pagination Pagination) Pagination {
 	}
 
 	// Limite máximo de segurança
-	if pagination.Take > 1000 {
-		pagination.Take = 1000
+	if pagination.Take > limits.Max {
+		pagination.Take = limits.Max
 	}
 
 	return pagination
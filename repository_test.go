@@ -1,11 +1,77 @@
 package zendia
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
+type testEntity struct {
+	ID       uuid.UUID `bson:"_id" json:"id"`
+	Name     string    `bson:"name" json:"name"`
+	TenantID uuid.UUID `bson:"tenant_id" json:"tenant_id"`
+	Active   bool      `bson:"active" json:"active"`
+	Created  AuditInfo `bson:"created" json:"created"`
+	Updated  AuditInfo `bson:"updated" json:"updated"`
+	Deleted  AuditInfo `bson:"deleted" json:"deleted"`
+}
+
+func (e *testEntity) GetID() uuid.UUID          { return e.ID }
+func (e *testEntity) SetID(id uuid.UUID)        { e.ID = id }
+func (e *testEntity) GetTenantID() string       { return e.TenantID.String() }
+func (e *testEntity) SetTenantID(s string)      { e.TenantID, _ = uuid.Parse(s) }
+func (e *testEntity) SetCreated(info AuditInfo) { e.Created = info }
+func (e *testEntity) SetUpdated(info AuditInfo) { e.Updated = info }
+func (e *testEntity) SetDeleted(info AuditInfo) { e.Deleted = info }
+func (e *testEntity) SetActive(active bool)     { e.Active = active }
+
+// contextWithTenant cria um context como o TenantMiddleware faria
+func contextWithTenant(tenantID, userID string) context.Context {
+	ctx := context.WithValue(context.Background(), TenantIDKey, tenantID)
+	return context.WithValue(ctx, UserIDKey, userID)
+}
+
+// assertBadRequest verifica que o erro é um BadRequest da API
+func assertBadRequest(t *testing.T, err error) {
+	t.Helper()
+	apiErr, ok := err.(*APIError)
+	if assert.True(t, ok, "expected *APIError, got %v", err) {
+		assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+	}
+}
+
+// newMockMT cria o mtest mockado com o registry UUID do framework
+func newMockMT(t *testing.T) *mtest.T {
+	t.Helper()
+	return mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock).
+		ClientOptions(options.Client().SetRegistry(NewMongoRegistry())))
+}
+
+// newMockRepo cria um repositório auditado sobre a collection do subteste
+func newMockRepo(mt *mtest.T, opts ...RepositoryOption) *Repository[*testEntity] {
+	cfg := RepositoryConfig{audit: true}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &Repository[*testEntity]{collection: mt.Coll, config: cfg}
+}
+
 func TestInputSanitizer(t *testing.T) {
 	sanitizer := NewInputSanitizer("project_id", "sprint_id")
 
@@ -56,3 +122,2389 @@ func TestInputSanitizer_TooManyFields(t *testing.T) {
 	_, err := sanitizer.Sanitize(input)
 	assert.Error(t, err)
 }
+
+func TestRepository_InvalidTenantFailsClosed(t *testing.T) {
+	// Sem collection: qualquer acesso ao banco causaria panic, então o teste
+	// garante que o tenant inválido é rejeitado antes da query
+	repo := &Repository[*testEntity]{config: RepositoryConfig{audit: true}}
+	ctx := contextWithTenant("not-a-uuid", "")
+	id := uuid.New()
+	filters := map[string]interface{}{}
+
+	_, err := repo.Create(ctx, &testEntity{})
+	assertBadRequest(t, err)
+
+	_, err = repo.GetByID(ctx, id)
+	assertBadRequest(t, err)
+
+	_, err = repo.GetFirst(ctx, filters)
+	assertBadRequest(t, err)
+
+	_, err = repo.Update(ctx, id, &testEntity{})
+	assertBadRequest(t, err)
+
+	assertBadRequest(t, repo.Delete(ctx, id))
+
+	_, err = repo.GetByIDs(ctx, []uuid.UUID{id})
+	assertBadRequest(t, err)
+
+	_, err = repo.GetAll(ctx, filters)
+	assertBadRequest(t, err)
+
+	_, _, err = repo.GetAllSkipTake(ctx, filters, Pagination{})
+	assertBadRequest(t, err)
+
+	_, err = repo.Count(ctx, filters)
+	assertBadRequest(t, err)
+
+	_, err = repo.CountAll(ctx, filters)
+	assertBadRequest(t, err)
+
+	_, err = repo.Aggregate(ctx, nil)
+	assertBadRequest(t, err)
+
+	_, err = repo.AggregateRaw(ctx, nil)
+	assertBadRequest(t, err)
+
+	_, err = repo.GetAllIncludingDeleted(ctx, filters)
+	assertBadRequest(t, err)
+
+	_, err = repo.GetDeleted(ctx, filters)
+	assertBadRequest(t, err)
+
+	assertBadRequest(t, repo.HardDelete(ctx, id))
+	assertBadRequest(t, repo.Restore(ctx, id))
+
+	_, err = repo.DeleteMany(ctx, filters)
+	assertBadRequest(t, err)
+
+	_, err = repo.UpdateMany(ctx, filters, map[string]interface{}{})
+	assertBadRequest(t, err)
+
+	_, err = repo.BulkCreate(ctx, []*testEntity{{}})
+	assertBadRequest(t, err)
+
+	_, err = repo.Upsert(ctx, filters, &testEntity{})
+	assertBadRequest(t, err)
+
+	_, err = repo.ExistsBy(ctx, filters)
+	assertBadRequest(t, err)
+
+	items, errs := repo.Stream(ctx, filters)
+	for range items {
+		t.Fatal("Stream should not yield entities for an invalid tenant")
+	}
+	assertBadRequest(t, <-errs)
+}
+
+func TestHistoryManager_InvalidTenantFailsClosed(t *testing.T) {
+	hm := NewHistoryManager(nil)
+	ctx := contextWithTenant("not-a-uuid", uuid.New().String())
+
+	_, err := hm.GetHistory(ctx, uuid.New())
+	assertBadRequest(t, err)
+
+	before := &testEntity{Name: "before"}
+	after := &testEntity{Name: "after"}
+	assertBadRequest(t, hm.RecordChanges(ctx, uuid.New(), "Test", "Update", before, after))
+}
+
+func TestHistoryManager_RecordChangesOutsideRequest(t *testing.T) {
+	mt := newMockMT(t)
+	defer mt.Close()
+
+	mt.Run("trigger at falls back to now", func(mt *mtest.T) {
+		hm := NewHistoryManager(mt.Coll)
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Job sem middleware: nenhum ActionAt fixado no context
+		before := time.Now()
+		ctx := contextWithTenant(uuid.New().String(), uuid.New().String())
+		err := hm.RecordChanges(ctx, uuid.New(), "Test", "Update", &testEntity{Name: "a"}, &testEntity{Name: "b"})
+		assert.NoError(t, err)
+
+		doc := mt.GetStartedEvent().Command.Lookup("documents").Array().Index(0).Value().Document()
+		at := doc.Lookup("trigger", "at").Time()
+		assert.False(t, at.Before(before.Truncate(time.Millisecond)), "trigger.at = %v", at)
+	})
+}
+
+func TestRepository_BuildAuditInfo_NonUUIDUser(t *testing.T) {
+	repo := &Repository[*testEntity]{config: RepositoryConfig{audit: true}}
+
+	// Firebase UID não é UUID: não deve causar panic
+	info := repo.buildAuditInfo(TenantInfo{UserID: "firebase-uid-123", UserName: "João"})
+	assert.Equal(t, uuid.Nil, info.ByID)
+	assert.Equal(t, "João", info.ByName)
+	assert.True(t, info.Active)
+
+	userID := uuid.New()
+	info = repo.buildAuditInfo(TenantInfo{UserID: userID.String()})
+	assert.Equal(t, userID, info.ByID)
+}
+
+func TestCachedRepository_GetByID_ChecksTenant(t *testing.T) {
+	base := &Repository[*testEntity]{config: RepositoryConfig{audit: true}}
+	cache := NewMemoryCache(MemoryCacheConfig{CacheConfig: CacheConfig{TTL: time.Minute}})
+	repo := NewCachedRepository(base, cache, CacheConfig{}, "Test")
+
+	tenantA := uuid.New()
+	entity := &testEntity{ID: uuid.New(), Name: "cached", TenantID: tenantA}
+	data, _ := json.Marshal(entity)
+	cache.Set(context.Background(), repo.makeKey("get", entity.ID), data, 0)
+
+	// Mesmo tenant: cache hit
+	result, err := repo.GetByID(contextWithTenant(tenantA.String(), ""), entity.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "cached", result.Name)
+
+	// Outro tenant: não pode ler a entidade do cache
+	_, err = repo.GetByID(contextWithTenant(uuid.New().String(), ""), entity.ID)
+	apiErr, ok := err.(*APIError)
+	if assert.True(t, ok) {
+		assert.Equal(t, http.StatusNotFound, apiErr.Code)
+	}
+}
+
+func TestCachedRepository_GetByIDs_FetchesOnlyMisses(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock).
+		ClientOptions(options.Client().SetRegistry(NewMongoRegistry())))
+	defer mt.Close()
+
+	mt.Run("6 cached, 4 fetched", func(mt *mtest.T) {
+		base := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{audit: true}}
+		cache := NewMemoryCache(MemoryCacheConfig{CacheConfig: CacheConfig{TTL: time.Minute}, MaxSize: 100})
+		repo := NewCachedRepository(base, cache, CacheConfig{}, "Test")
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+
+		tenantID := uuid.New()
+		ctx := contextWithTenant(tenantID.String(), "")
+
+		ids := make([]uuid.UUID, 10)
+		var docs []bson.D
+		for i := range ids {
+			ids[i] = uuid.New()
+			if i < 6 {
+				data, _ := json.Marshal(&testEntity{ID: ids[i], TenantID: tenantID, Active: true})
+				cache.Set(ctx, repo.makeKey("get", ids[i]), data, 0)
+				continue
+			}
+			docs = append(docs, bson.D{
+				{Key: "_id", Value: UUIDBinaryEncoder(ids[i])},
+				{Key: "tenant_id", Value: UUIDBinaryEncoder(tenantID)},
+				{Key: "active", Value: true},
+			})
+		}
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, docs...))
+		result, err := repo.GetByIDs(ctx, ids)
+		assert.NoError(t, err)
+		if assert.Len(t, result, 10) {
+			for i, entity := range result {
+				assert.Equal(t, ids[i], entity.ID)
+			}
+		}
+
+		// Uma única query ao base, só com os 4 IDs ausentes do cache
+		events := mt.GetAllStartedEvents()
+		if assert.Len(t, events, 1) {
+			in, err := events[0].Command.Lookup("filter", "_id", "$in").Array().Values()
+			assert.NoError(t, err)
+			if assert.Len(t, in, 4) {
+				for i, v := range in {
+					assertUUIDBinary(t, v, ids[6+i], "missing id")
+				}
+			}
+		}
+
+		// Os buscados populam o cache: a segunda chamada não vai ao banco
+		mt.ClearEvents()
+		result, err = repo.GetByIDs(ctx, ids)
+		assert.NoError(t, err)
+		assert.Len(t, result, 10)
+		assert.Empty(t, mt.GetAllStartedEvents())
+
+		// Outro tenant não enxerga as entidades em cache
+		result, err = repo.GetByIDs(contextWithTenant(uuid.New().String(), ""), ids[:6])
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+}
+
+func TestCachedRepository_RecordsCacheMetrics(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock).
+		ClientOptions(options.Client().SetRegistry(NewMongoRegistry())))
+	defer mt.Close()
+
+	mt.Run("hits and misses per type", func(mt *mtest.T) {
+		metrics := NewMetrics()
+		base := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{audit: true}}
+		cache := NewMemoryCache(MemoryCacheConfig{CacheConfig: CacheConfig{TTL: time.Minute}})
+		users := NewCachedRepository(base, cache, CacheConfig{}, "User")
+		orders := NewCachedRepository(base, cache, CacheConfig{}, "Order")
+		users.AddMetricsSink(metrics)
+		orders.AddMetricsSink(metrics)
+
+		tenantID := uuid.New()
+		ctx := contextWithTenant(tenantID.String(), "")
+		cached := &testEntity{ID: uuid.New(), TenantID: tenantID}
+		data, _ := json.Marshal(cached)
+		cache.Set(ctx, users.makeKey("get", cached.ID), data, 0)
+
+		missing := uuid.New()
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		doc := bson.D{{Key: "_id", Value: UUIDBinaryEncoder(missing)}, {Key: "tenant_id", Value: UUIDBinaryEncoder(tenantID)}}
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, doc),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch),
+		)
+
+		_, err := users.GetByID(ctx, cached.ID) // hit
+		assert.NoError(t, err)
+		_, err = users.GetByID(ctx, missing) // miss, popula o cache
+		assert.NoError(t, err)
+		_, err = users.GetByIDs(ctx, []uuid.UUID{cached.ID, missing}) // 2 hits
+		assert.NoError(t, err)
+		_, err = users.GetAll(ctx, nil) // miss
+		assert.NoError(t, err)
+		_, err = orders.GetAll(ctx, nil) // miss
+		assert.NoError(t, err)
+
+		cacheStats := metrics.GetStats()["cache"].(map[string]interface{})
+		assert.Equal(t, map[string]interface{}{"hits": int64(3), "misses": int64(2), "hit_rate": 60.0}, cacheStats["User"])
+		assert.Equal(t, map[string]interface{}{"hits": int64(0), "misses": int64(1), "hit_rate": 0.0}, cacheStats["Order"])
+	})
+}
+
+func TestCachedRepository_GetAllWithOptsBypassesCache(t *testing.T) {
+	mt := newMockMT(t)
+	defer mt.Close()
+
+	mt.Run("projected then full", func(mt *mtest.T) {
+		cache := NewMemoryCache(MemoryCacheConfig{CacheConfig: CacheConfig{TTL: time.Minute}})
+		repo := NewCachedRepository(newMockRepo(mt), cache, CacheConfig{}, "Test")
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+
+		tenantID := uuid.New()
+		ctx := contextWithTenant(tenantID.String(), "")
+		id := UUIDBinaryEncoder(uuid.New())
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "_id", Value: id}}),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "_id", Value: id}, {Key: "name", Value: "Ana"}}),
+		)
+
+		projected, err := repo.GetAll(ctx, nil, &QueryOptions{Projection: map[string]interface{}{"_id": 1}})
+		assert.NoError(t, err)
+		if assert.Len(t, projected, 1) {
+			assert.Empty(t, projected[0].Name)
+		}
+		key, _ := repo.listKey(ctx)
+		_, cached := cache.Get(ctx, key)
+		assert.False(t, cached, "resultado projetado não pode ocupar a chave da listagem")
+
+		full, err := repo.GetAll(ctx, nil)
+		assert.NoError(t, err)
+		if assert.Len(t, full, 1) {
+			assert.Equal(t, "Ana", full[0].Name)
+		}
+		_, cached = cache.Get(ctx, key)
+		assert.True(t, cached)
+	})
+}
+
+func TestCachedRepository_RestoreAndHardDeleteInvalidate(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock).
+		ClientOptions(options.Client().SetRegistry(NewMongoRegistry())))
+	defer mt.Close()
+
+	mt.Run("restore reflected immediately", func(mt *mtest.T) {
+		base := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{audit: true}}
+		cache := NewMemoryCache(MemoryCacheConfig{CacheConfig: CacheConfig{TTL: time.Minute}})
+		repo := NewCachedRepository(base, cache, CacheConfig{}, "Test")
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+
+		tenantID := uuid.New()
+		ctx := contextWithTenant(tenantID.String(), "")
+		doc := func(id uuid.UUID) bson.D {
+			return bson.D{
+				{Key: "_id", Value: UUIDBinaryEncoder(id)},
+				{Key: "tenant_id", Value: UUIDBinaryEncoder(tenantID)},
+				{Key: "active", Value: true},
+			}
+		}
+		kept, restored := uuid.New(), uuid.New()
+
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, doc(kept)),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, doc(kept), doc(restored)),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, doc(restored)),
+		)
+
+		list, err := repo.GetAll(ctx, nil)
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+
+		// Restore limpa a listagem em cache: a próxima leitura vai ao banco
+		assert.NoError(t, repo.Restore(ctx, restored))
+		list, err = repo.GetAll(ctx, nil)
+		assert.NoError(t, err)
+		assert.Len(t, list, 2)
+
+		assert.NoError(t, repo.HardDelete(ctx, kept))
+		list, err = repo.GetAll(ctx, nil)
+		assert.NoError(t, err)
+		if assert.Len(t, list, 1) {
+			assert.Equal(t, restored, list[0].ID)
+		}
+	})
+}
+
+func TestFieldsProjection_MapsJSONToBSONNames(t *testing.T) {
+	assert.Nil(t, FieldsProjection[*testEntity](nil))
+	assert.Equal(t, map[string]interface{}{"_id": 1, "name": 1, "created.set_at": 1},
+		FieldsProjection[*testEntity]([]string{"id", "name", "created.set_at"}))
+	// Campos de structs embutidas com inline
+	assert.Equal(t, map[string]interface{}{"_id": 1, "org_id": 1},
+		FieldsProjection[*orgTestEntity]([]string{"id", "org_id"}))
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock).
+		ClientOptions(options.Client().SetRegistry(NewMongoRegistry())))
+	defer mt.Close()
+
+	mt.Run("projection sent to find", func(mt *mtest.T) {
+		repo := &Repository[*testEntity]{collection: mt.Coll}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.coll", mtest.FirstBatch))
+
+		_, err := repo.GetAll(context.Background(), nil, &QueryOptions{
+			Projection: FieldsProjection[*testEntity]([]string{"id", "name"}),
+		})
+		assert.NoError(t, err)
+		projection := mt.GetStartedEvent().Command.Lookup("projection").Document()
+		assert.Equal(t, int32(1), projection.Lookup("_id").Int32())
+		assert.Equal(t, int32(1), projection.Lookup("name").Int32())
+	})
+}
+
+func TestRepository_GetAllWithDeletedFlag(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock).
+		ClientOptions(options.Client().SetRegistry(NewMongoRegistry())))
+	defer mt.Close()
+
+	mt.Run("flags soft deleted with audit info", func(mt *mtest.T) {
+		repo := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{audit: true}}
+		tenantID := uuid.New()
+		ctx := contextWithTenant(tenantID.String(), "")
+		activeID, deletedID, adminID := uuid.New(), uuid.New(), uuid.New()
+		deletedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.coll", mtest.FirstBatch,
+			bson.D{
+				{Key: "_id", Value: UUIDBinaryEncoder(activeID)},
+				{Key: "name", Value: "Ana"},
+				{Key: "active", Value: true},
+			},
+			bson.D{
+				{Key: "_id", Value: UUIDBinaryEncoder(deletedID)},
+				{Key: "name", Value: "Bia"},
+				{Key: "active", Value: false},
+				{Key: "deleted", Value: bson.D{
+					{Key: "set_at", Value: deletedAt},
+					{Key: "by_id", Value: UUIDBinaryEncoder(adminID)},
+					{Key: "by_name", Value: "Admin"},
+					{Key: "active", Value: false},
+					{Key: "reason", Value: "LGPD"},
+				}},
+			}))
+
+		items, err := repo.GetAllWithDeletedFlag(ctx, nil)
+		assert.NoError(t, err)
+		if assert.Len(t, items, 2) {
+			assert.Equal(t, activeID, items[0].Entity.ID)
+			assert.False(t, items[0].Deleted)
+			assert.Nil(t, items[0].DeletedInfo)
+
+			assert.Equal(t, deletedID, items[1].Entity.ID)
+			assert.True(t, items[1].Deleted)
+			if assert.NotNil(t, items[1].DeletedInfo) {
+				assert.Equal(t, adminID, items[1].DeletedInfo.ByID)
+				assert.Equal(t, "LGPD", items[1].DeletedInfo.Reason)
+				assert.True(t, deletedAt.Equal(items[1].DeletedInfo.SetAt))
+			}
+			// A entidade também recebe o deleted
+			assert.Equal(t, adminID, items[1].Entity.Deleted.ByID)
+		}
+
+		// Filtra só pelo tenant, sem active
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		assertUUIDBinary(t, filter.Lookup("tenant_id"), tenantID, "tenant filter")
+		_, hasActive := filter.Lookup("active").BooleanOK()
+		assert.False(t, hasActive)
+	})
+}
+
+func TestSetPageLimits_EnforcedByHandlerAndRepository(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock).
+		ClientOptions(options.Client().SetRegistry(NewMongoRegistry())))
+	defer mt.Close()
+
+	mt.Run("instance max", func(mt *mtest.T) {
+		repo := &Repository[*testEntity]{collection: mt.Coll}
+		app := NewWithOptions(Options{DefaultPageSize: 5, MaxPageSize: 50})
+
+		// take pedido direto ao repository, sem passar pela validação do handler
+		var requested int
+		app.GET("/entities", PaginationMiddleware(PaginationConfig{}), Handle(func(c *Context[any]) error {
+			page := c.PageParams().Pagination()
+			if requested > 0 {
+				page.Take = requested
+			}
+			items, total, err := repo.GetAllSkipTake(c.Request.Context(), nil, page)
+			if err != nil {
+				return err
+			}
+			c.Success("ok", items, total)
+			return nil
+		}))
+
+		findLimit := func(path string, take int) (int, int64) {
+			requested = take
+			mt.ClearEvents()
+			mt.AddMockResponses(
+				mtest.CreateCursorResponse(0, "db.coll", mtest.FirstBatch, bson.D{{Key: "n", Value: 0}}),
+				mtest.CreateCursorResponse(0, "db.coll", mtest.FirstBatch),
+			)
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+			if w.Code != http.StatusOK {
+				return w.Code, 0
+			}
+			events := mt.GetAllStartedEvents()
+			return w.Code, events[len(events)-1].Command.Lookup("limit").AsInt64()
+		}
+
+		code, limit := findLimit("/entities", 0)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, int64(5), limit)
+
+		code, limit = findLimit("/entities?take=50", 0)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, int64(50), limit)
+
+		// Handler: acima do máximo responde 400
+		code, _ = findLimit("/entities?take=51", 0)
+		assert.Equal(t, http.StatusBadRequest, code)
+
+		// Repository: acima do máximo reduz ao mesmo máximo
+		code, limit = findLimit("/entities", 500)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, int64(50), limit)
+	})
+
+	// Fora de uma requisição valem os padrões
+	assert.Equal(t, DefaultPageLimits, GetPageLimits(context.Background()))
+	assert.Equal(t, Pagination{Take: DefaultMaxPageSize}, ResolvePagination(Pagination{Take: 5000}))
+	assert.Equal(t, Pagination{Take: 20}, ResolvePaginationContext(WithPageLimits(context.Background(), PageLimits{Default: 20, Max: 30}), Pagination{}))
+}
+
+func TestMongoClientOptions_Defaults(t *testing.T) {
+	opts := mongoClientOptions(MongoConnectConfig{URI: "mongodb://localhost:27017"})
+
+	assert.Equal(t, uint64(DefaultMongoMaxPoolSize), *opts.MaxPoolSize)
+	assert.Equal(t, uint64(DefaultMongoMinPoolSize), *opts.MinPoolSize)
+	assert.Equal(t, DefaultMongoMaxConnIdleTime, *opts.MaxConnIdleTime)
+	assert.Equal(t, DefaultMongoServerSelectionTimeout, *opts.ServerSelectionTimeout)
+	assert.Equal(t, DefaultMongoSocketTimeout, *opts.SocketTimeout)
+
+	opts = mongoClientOptions(MongoConnectConfig{
+		URI:           "mongodb://localhost:27017",
+		MaxPoolSize:   50,
+		SocketTimeout: time.Minute,
+	})
+
+	assert.Equal(t, uint64(50), *opts.MaxPoolSize)
+	assert.Equal(t, time.Minute, *opts.SocketTimeout)
+}
+
+func TestDecodeCursor_StopsOnCancel(t *testing.T) {
+	docs := make([]interface{}, 1000)
+	for i := range docs {
+		docs[i] = bson.M{"name": "entity"}
+	}
+
+	cursor, err := mongo.NewCursorFromDocuments(docs, nil, nil)
+	assert.NoError(t, err)
+
+	entities, err := decodeCursor[testEntity](context.Background(), cursor)
+	assert.NoError(t, err)
+	assert.Len(t, entities, 1000)
+
+	cursor, err = mongo.NewCursorFromDocuments(docs, nil, nil)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	entities, err = decodeCursor[testEntity](ctx, cursor)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, entities)
+	assert.Less(t, time.Since(start), time.Second)
+
+	apiErr := internalError("Failed to decode entities", err)
+	assert.Equal(t, ClientClosedRequestErrorType, apiErr.Type)
+	assert.Equal(t, StatusClientClosedRequest, apiErr.Code)
+}
+
+func TestRepository_MaxResults(t *testing.T) {
+	repo := &Repository[*testEntity]{config: RepositoryConfig{maxResults: 100}}
+
+	findOpts := options.Find()
+	repo.limitResults(findOpts)
+	assert.Equal(t, int64(101), *findOpts.Limit)
+
+	findOpts = options.Find().SetLimit(20)
+	repo.limitResults(findOpts)
+	assert.Equal(t, int64(20), *findOpts.Limit)
+
+	findOpts = options.Find().SetLimit(5000)
+	repo.limitResults(findOpts)
+	assert.Equal(t, int64(101), *findOpts.Limit)
+
+	assert.NoError(t, repo.checkResultSize(100))
+	assertBadRequest(t, repo.checkResultSize(101))
+
+	unlimited := &Repository[*testEntity]{config: RepositoryConfig{}}
+	findOpts = options.Find()
+	unlimited.limitResults(findOpts)
+	assert.Nil(t, findOpts.Limit)
+	assert.NoError(t, unlimited.checkResultSize(1_000_000))
+}
+
+var (
+	_ EntityRepository[*testEntity] = (*Repository[*testEntity])(nil)
+	_ EntityRepository[*testEntity] = (*CachedRepository[*testEntity])(nil)
+	_ EntityRepository[*testEntity] = (*RetryRepository[*testEntity])(nil)
+	_ EntityRepository[*testEntity] = (*ReadOnlyRepository[*testEntity])(nil)
+)
+
+// flakyRepository falha com erro transitório nas primeiras `failures` chamadas
+type flakyRepository struct {
+	EntityRepository[*testEntity]
+	failures int
+	calls    int
+	err      error
+}
+
+func (f *flakyRepository) attempt() error {
+	f.calls++
+	if f.calls <= f.failures {
+		return f.err
+	}
+	return nil
+}
+
+func (f *flakyRepository) GetByID(ctx context.Context, id uuid.UUID) (*testEntity, error) {
+	if err := f.attempt(); err != nil {
+		return nil, err
+	}
+	return &testEntity{ID: id}, nil
+}
+
+func (f *flakyRepository) Create(ctx context.Context, entity *testEntity) (*testEntity, error) {
+	if err := f.attempt(); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+func transientError() error {
+	return internalError("Failed to get entity", mongo.CommandError{
+		Code:   10107,
+		Name:   "NotWritablePrimary",
+		Labels: []string{"RetryableWriteError"},
+	})
+}
+
+func TestRetryRepository_RetriesTransientReads(t *testing.T) {
+	base := &flakyRepository{failures: 2, err: transientError()}
+	repo := NewRetryRepository[*testEntity](base, RetryConfig{InitialBackoff: time.Millisecond})
+
+	id := uuid.New()
+	entity, err := repo.GetByID(context.Background(), id)
+	assert.NoError(t, err)
+	assert.Equal(t, id, entity.ID)
+	assert.Equal(t, 3, base.calls)
+
+	// Excede MaxAttempts: devolve o último erro
+	base = &flakyRepository{failures: 5, err: transientError()}
+	repo = NewRetryRepository[*testEntity](base, RetryConfig{MaxAttempts: 2, InitialBackoff: time.Millisecond})
+	_, err = repo.GetByID(context.Background(), id)
+	assert.True(t, IsRetryableError(err))
+	assert.Equal(t, 2, base.calls)
+}
+
+func TestRetryRepository_SkipsNonRetryableAndWrites(t *testing.T) {
+	base := &flakyRepository{failures: 1, err: NewNotFoundError("Entity not found")}
+	repo := NewRetryRepository[*testEntity](base, RetryConfig{InitialBackoff: time.Millisecond})
+
+	_, err := repo.GetByID(context.Background(), uuid.New())
+	assert.Error(t, err)
+	assert.Equal(t, 1, base.calls)
+
+	base = &flakyRepository{failures: 1, err: transientError()}
+	repo = NewRetryRepository[*testEntity](base, RetryConfig{InitialBackoff: time.Millisecond})
+	_, err = repo.Create(context.Background(), &testEntity{})
+	assert.Error(t, err)
+	assert.Equal(t, 1, base.calls)
+
+	base = &flakyRepository{failures: 1, err: transientError()}
+	repo = NewRetryRepository[*testEntity](base, RetryConfig{InitialBackoff: time.Millisecond, RetryWrites: true})
+	_, err = repo.Create(context.Background(), &testEntity{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, base.calls)
+}
+
+func TestRetryRepository_HonorsCancellationBetweenAttempts(t *testing.T) {
+	base := &flakyRepository{failures: 10, err: transientError()}
+	repo := NewRetryRepository[*testEntity](base, RetryConfig{MaxAttempts: 10, InitialBackoff: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := repo.GetByID(ctx, uuid.New())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, base.calls)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+type orgTestEntity struct {
+	testEntity `bson:",inline"`
+	OrgID      string `bson:"org_id" json:"org_id"`
+}
+
+func (e *orgTestEntity) SetIsolationKey(key, value string) {
+	if key == "org_id" {
+		e.OrgID = value
+	}
+}
+
+func (e *orgTestEntity) GetIsolationKey(key string) string {
+	if key == "org_id" {
+		return e.OrgID
+	}
+	return ""
+}
+
+func TestRepository_IsolationKeys(t *testing.T) {
+	repo := &Repository[*orgTestEntity]{config: RepositoryConfig{audit: true, isolationKeys: []string{"org_id"}}}
+	tenantID := uuid.New()
+	ctx := WithTenantExtra(contextWithTenant(tenantID.String(), ""), map[string]string{"org_id": "org-a"})
+
+	filter := bson.M{"active": true}
+	assert.NoError(t, repo.injectTenantFilter(ctx, filter))
+	assert.Equal(t, bson.M{"active": true, "tenant_id": UUIDBinaryEncoder(tenantID), "org_id": "org-a"}, filter)
+
+	entity := &orgTestEntity{}
+	_, err := repo.stampTenant(ctx, entity)
+	assert.NoError(t, err)
+	assert.Equal(t, tenantID, entity.TenantID)
+	assert.Equal(t, "org-a", entity.OrgID)
+
+	// Sem org_id no contexto: falha fechado antes de qualquer query
+	noOrg := contextWithTenant(tenantID.String(), "")
+	_, err = repo.GetByID(noOrg, uuid.New())
+	assertBadRequest(t, err)
+	_, err = repo.Create(noOrg, &orgTestEntity{})
+	assertBadRequest(t, err)
+
+	// Mesmo tenant, outra organização: não lê a entidade do cache
+	cache := NewMemoryCache(MemoryCacheConfig{CacheConfig: CacheConfig{TTL: time.Minute}})
+	cached := NewCachedRepository(repo, cache, CacheConfig{}, "Org")
+	entity.ID = uuid.New()
+	data, _ := json.Marshal(entity)
+	cache.Set(context.Background(), cached.makeKey("get", entity.ID), data, 0)
+
+	result, err := cached.GetByID(ctx, entity.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "org-a", result.OrgID)
+
+	otherOrg := WithTenantExtra(contextWithTenant(tenantID.String(), ""), map[string]string{"org_id": "org-b"})
+	_, err = cached.GetByID(otherOrg, entity.ID)
+	apiErr, ok := err.(*APIError)
+	if assert.True(t, ok) {
+		assert.Equal(t, http.StatusNotFound, apiErr.Code)
+	}
+
+	keyA, _ := cached.listKey(ctx)
+	keyB, _ := cached.listKey(otherOrg)
+	assert.NotEqual(t, keyA, keyB)
+}
+
+func TestReadOnlyRepository_RejectsWrites(t *testing.T) {
+	base := &flakyRepository{}
+	repo := NewReadOnlyRepository[*testEntity](base)
+	ctx := context.Background()
+	id := uuid.New()
+
+	assertForbidden := func(err error) {
+		t.Helper()
+		apiErr, ok := err.(*APIError)
+		if assert.True(t, ok, "expected *APIError, got %v", err) {
+			assert.Equal(t, http.StatusForbidden, apiErr.Code)
+		}
+	}
+
+	_, err := repo.Create(ctx, &testEntity{})
+	assertForbidden(err)
+	_, err = repo.Update(ctx, id, &testEntity{})
+	assertForbidden(err)
+	assertForbidden(repo.Delete(ctx, id))
+	_, err = repo.Aggregate(ctx, []interface{}{bson.M{"$match": bson.M{}}, bson.D{{Key: "$out", Value: "copy"}}})
+	assertForbidden(err)
+	assert.Equal(t, 0, base.calls, "writes must not reach the base repository")
+
+	found, err := repo.GetByID(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, id, found.ID)
+	assert.Equal(t, 1, base.calls)
+}
+
+func TestRepository_ReadPreferenceAndConcern(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("defaults and per-query override", func(mt *mtest.T) {
+		repo := NewRepository[*testEntity](mt.Coll,
+			WithReadPreference(readpref.Nearest()),
+			WithReadConcern(readconcern.Local()),
+		)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+
+		lastFind := func() bson.Raw {
+			return mt.GetStartedEvent().Command
+		}
+
+		// Padrão do repository
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+		_, err := repo.GetAll(context.Background(), nil)
+		assert.NoError(t, err)
+		cmd := lastFind()
+		assert.Equal(t, "nearest", cmd.Lookup("$readPreference", "mode").StringValue())
+		assert.Equal(t, "local", cmd.Lookup("readConcern", "level").StringValue())
+
+		// Override por chamada
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+		_, err = repo.GetAll(context.Background(), nil, &QueryOptions{
+			ReadPreference: readpref.SecondaryPreferred(),
+			ReadConcern:    readconcern.Majority(),
+		})
+		assert.NoError(t, err)
+		cmd = lastFind()
+		assert.Equal(t, "secondaryPreferred", cmd.Lookup("$readPreference", "mode").StringValue())
+		assert.Equal(t, "majority", cmd.Lookup("readConcern", "level").StringValue())
+	})
+}
+
+// assertUUIDBinary verifica que o valor é o UUID em binary subtype 4
+func assertUUIDBinary(t *testing.T, value bson.RawValue, id uuid.UUID, path string) {
+	t.Helper()
+	subtype, data, ok := value.BinaryOK()
+	if assert.True(t, ok, "%s: expected binary, got %s", path, value.Type) {
+		assert.Equal(t, bsontype.BinaryUUID, subtype, path)
+		assert.Equal(t, id[:], data, path)
+	}
+}
+
+func TestRepository_UUIDEncodingConsistent(t *testing.T) {
+	mt := newMockMT(t)
+	defer mt.Close()
+
+	mt.Run("every path uses subtype 4", func(mt *mtest.T) {
+		repo := newMockRepo(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		tenantID := uuid.New()
+		ctx := contextWithTenant(tenantID.String(), "")
+		id := uuid.New()
+
+		command := func() bson.Raw { return mt.GetStartedEvent().Command }
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		_, err := repo.Create(ctx, &testEntity{ID: id})
+		assert.NoError(t, err)
+		cmd := command()
+		assertUUIDBinary(t, cmd.Lookup("documents", "0", "_id"), id, "create _id")
+		assertUUIDBinary(t, cmd.Lookup("documents", "0", "tenant_id"), tenantID, "create tenant_id")
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+		_, _ = repo.GetByID(ctx, id)
+		cmd = command()
+		assertUUIDBinary(t, cmd.Lookup("filter", "_id"), id, "get _id")
+		assertUUIDBinary(t, cmd.Lookup("filter", "tenant_id"), tenantID, "get tenant_id")
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+		_, err = repo.GetByIDs(ctx, []uuid.UUID{id})
+		assert.NoError(t, err)
+		assertUUIDBinary(t, command().Lookup("filter", "_id", "$in", "0"), id, "get by ids")
+
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: bson.D{{Key: "_id", Value: UUIDBinaryEncoder(id)}}}})
+		_, err = repo.Update(ctx, id, &testEntity{ID: id})
+		assert.NoError(t, err)
+		cmd = command()
+		assertUUIDBinary(t, cmd.Lookup("query", "_id"), id, "update _id")
+		assertUUIDBinary(t, cmd.Lookup("query", "tenant_id"), tenantID, "update tenant_id")
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+		assert.NoError(t, repo.HardDelete(ctx, id))
+		cmd = command()
+		assertUUIDBinary(t, cmd.Lookup("deletes", "0", "q", "_id"), id, "hard delete _id")
+		assertUUIDBinary(t, cmd.Lookup("deletes", "0", "q", "tenant_id"), tenantID, "hard delete tenant_id")
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+		assert.NoError(t, repo.Restore(ctx, id))
+		cmd = command()
+		assertUUIDBinary(t, cmd.Lookup("updates", "0", "q", "_id"), id, "restore _id")
+		assertUUIDBinary(t, cmd.Lookup("updates", "0", "q", "tenant_id"), tenantID, "restore tenant_id")
+	})
+
+	mt.Run("history filters use the configured encoder", func(mt *mtest.T) {
+		repo := NewRepository[*testEntity](mt.Coll, WithHistory(mt.Coll, "Test"), WithIDEncoder(UUIDStringEncoder))
+		mt.ClearEvents()
+		tenantID, id := uuid.New(), uuid.New()
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, mt.Coll.Database().Name()+"."+mt.Coll.Name(), mtest.FirstBatch))
+		_, err := repo.GetHistory(contextWithTenant(tenantID.String(), ""), id)
+		assert.NoError(t, err)
+		filter := mt.GetStartedEvent().Command.Lookup("filter")
+		assert.Equal(t, id.String(), filter.Document().Lookup("entity_id").StringValue())
+		assert.Equal(t, tenantID.String(), filter.Document().Lookup("tenant_id").StringValue())
+	})
+
+	var cfg RepositoryConfig
+	WithIDEncoder(UUIDStringEncoder)(&cfg)
+	id := uuid.New()
+	repo := &Repository[*testEntity]{config: cfg}
+	assert.Equal(t, id.String(), repo.idToBSON(id))
+}
+
+func TestRepository_GetAfter(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock).
+		ClientOptions(options.Client().SetRegistry(NewMongoRegistry())))
+	defer mt.Close()
+
+	mt.Run("keyset pages", func(mt *mtest.T) {
+		repo := &Repository[*testEntity]{collection: mt.Coll}
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		ctx := context.Background()
+
+		ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+		docs := make([]bson.D, len(ids))
+		for i, id := range ids {
+			docs[i] = bson.D{{Key: "_id", Value: UUIDBinaryEncoder(id)}, {Key: "active", Value: true}}
+		}
+
+		// Primeira página: limit+1 documentos indica que há próxima
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, docs...))
+		page, next, err := repo.GetAfter(ctx, nil, "", 2)
+		assert.NoError(t, err)
+		assert.Len(t, page, 2)
+		assert.NotEmpty(t, next)
+		cmd := mt.GetStartedEvent().Command
+		assert.Equal(t, int32(3), cmd.Lookup("limit").AsInt32())
+
+		// Segunda página: filtra _id > último da página anterior
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, docs[2]))
+		page, next, err = repo.GetAfter(ctx, nil, next, 2)
+		assert.NoError(t, err)
+		assert.Len(t, page, 1)
+		assert.Empty(t, next)
+		cmd = mt.GetStartedEvent().Command
+		assertUUIDBinary(t, cmd.Lookup("filter", "$and", "1", "_id", "$gt"), ids[1], "keyset _id")
+
+		_, _, err = repo.GetAfter(ctx, nil, "not a cursor!", 2)
+		assertBadRequest(t, err)
+	})
+
+	after, err := keysetFilter("created.set_at", mustCursor(t, bson.D{
+		{Key: "created", Value: bson.D{{Key: "set_at", Value: int32(7)}}},
+		{Key: "_id", Value: "b"},
+	}, "created.set_at"))
+	assert.NoError(t, err)
+	or := after["$or"].(bson.A)
+	if assert.Len(t, or, 2) {
+		assert.Contains(t, or[0], "created.set_at")
+		assert.Contains(t, or[1], "_id")
+	}
+}
+
+func TestRepository_Patch(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock).
+		ClientOptions(options.Client().SetRegistry(NewMongoRegistry())))
+	defer mt.Close()
+
+	mt.Run("sets only provided fields", func(mt *mtest.T) {
+		repo := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{audit: true}}
+		id, tenantID := uuid.New(), uuid.New()
+		ctx := context.WithValue(context.Background(), TenantIDKey, tenantID.String())
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{
+			{Key: "_id", Value: UUIDBinaryEncoder(id)},
+			{Key: "name", Value: "Bia"},
+			{Key: "tenant_id", Value: UUIDBinaryEncoder(tenantID)},
+			{Key: "active", Value: true},
+			{Key: "created", Value: bson.D{{Key: "by_name", Value: "ana"}}},
+		}}))
+
+		patched, err := repo.Patch(ctx, id, map[string]interface{}{"name": "Bia"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Bia", patched.Name)
+		assert.Equal(t, "ana", patched.Created.ByName)
+
+		cmd := mt.GetStartedEvent().Command
+		set, err := cmd.Lookup("update", "$set").Document().Elements()
+		assert.NoError(t, err)
+		keys := make([]string, len(set))
+		for i, e := range set {
+			keys[i] = e.Key()
+		}
+		// Campos não informados (created, tenant_id, ...) ficam fora do $set
+		assert.ElementsMatch(t, []string{"name", "updated"}, keys)
+		assertUUIDBinary(t, cmd.Lookup("query", "tenant_id"), tenantID, "tenant filter")
+	})
+
+	repo := &Repository[*testEntity]{}
+	ctx := context.Background()
+	for _, fields := range []map[string]interface{}{
+		{},
+		{"tenant_id": uuid.New()},
+		{"created.by": "x"},
+		{"$set": "x"},
+		{"profile": map[string]interface{}{"$where": "1"}},
+	} {
+		_, err := repo.Patch(ctx, uuid.New(), fields)
+		assertBadRequest(t, err)
+	}
+}
+
+// validatedEntity testEntity com tags validate para o PatchValidated
+type validatedEntity struct {
+	testEntity `bson:",inline"`
+	Email      string `bson:"email" json:"email" validate:"required,email"`
+	Age        int    `bson:"age" json:"age" validate:"gte=0,lte=130"`
+}
+
+func TestRepository_PatchValidated(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock).
+		ClientOptions(options.Client().SetRegistry(NewMongoRegistry())))
+	defer mt.Close()
+
+	id := uuid.New()
+	current := bson.D{
+		{Key: "_id", Value: UUIDBinaryEncoder(id)},
+		{Key: "email", Value: "ana@example.com"},
+		{Key: "age", Value: 30},
+		{Key: "active", Value: true},
+	}
+
+	mt.Run("rejects invalid partial value", func(mt *mtest.T) {
+		repo := &Repository[*validatedEntity]{collection: mt.Coll}
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+
+		for _, tt := range []struct {
+			fields   map[string]interface{}
+			expected FieldErrors
+		}{
+			{map[string]interface{}{"email": "not-an-email"}, FieldErrors{{Field: "email", Tag: "email", Message: "email deve ser um email válido"}}},
+			{map[string]interface{}{"age": 200}, FieldErrors{{Field: "age", Tag: "lte", Message: "age deve ser menor ou igual a 130"}}},
+			{map[string]interface{}{"email": "", "age": -1}, FieldErrors{
+				{Field: "email", Tag: "required", Message: "email é obrigatório"},
+				{Field: "age", Tag: "gte", Message: "age deve ser maior ou igual a 0"},
+			}},
+			{map[string]interface{}{"age": "thirty", "email": "bia@example.com"}, FieldErrors{{Field: "age", Tag: "type", Message: MsgInvalidPatchField + ": age"}}},
+		} {
+			mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, current))
+			mt.ClearEvents()
+
+			_, err := repo.PatchValidated(context.Background(), id, tt.fields)
+			var apiErr *APIError
+			if assert.ErrorAs(t, err, &apiErr) {
+				assert.Equal(t, ValidationErrorType, apiErr.Type)
+				assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+				assert.Equal(t, tt.expected, apiErr.Details)
+			}
+			// Só o find da entidade atual: nada é gravado
+			assert.Equal(t, "find", mt.GetStartedEvent().CommandName)
+			assert.Nil(t, mt.GetStartedEvent())
+		}
+	})
+
+	mt.Run("persists valid patch", func(mt *mtest.T) {
+		repo := &Repository[*validatedEntity]{collection: mt.Coll}
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+
+		patchedDoc := append(bson.D{}, current...)
+		patchedDoc[1] = bson.E{Key: "email", Value: "bia@example.com"}
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, current),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: patchedDoc}),
+		)
+
+		patched, err := repo.PatchValidated(context.Background(), id, map[string]interface{}{"email": "bia@example.com"})
+		assert.NoError(t, err)
+		assert.Equal(t, "bia@example.com", patched.Email)
+		assert.Equal(t, 30, patched.Age)
+
+		assert.Equal(t, "find", mt.GetStartedEvent().CommandName)
+		set, err := mt.GetStartedEvent().Command.Lookup("update", "$set").Document().Elements()
+		assert.NoError(t, err)
+		assert.Len(t, set, 1)
+		assert.Equal(t, "email", set[0].Key())
+	})
+
+	_, err := (&Repository[*validatedEntity]{}).PatchValidated(context.Background(), id, map[string]interface{}{"tenant_id": "x"})
+	assertBadRequest(t, err)
+}
+
+type crudMongoEntity struct {
+	testEntity `bson:",inline"`
+	Nickname   string `bson:"nick_name" json:"nickname" validate:"max=5"`
+}
+
+func TestRegisterCRUD_PatchValidatesWithBSONNames(t *testing.T) {
+	mt := newMockMT(t)
+	defer mt.Close()
+
+	id := uuid.New()
+	current := bson.D{
+		{Key: "_id", Value: UUIDBinaryEncoder(id)},
+		{Key: "nick_name", Value: "ana"},
+		{Key: "active", Value: true},
+	}
+
+	mt.Run("patch route", func(mt *mtest.T) {
+		repo := &Repository[*crudMongoEntity]{collection: mt.Coll}
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		app := New()
+		RegisterCRUD[*crudMongoEntity, uuid.UUID](app.Group("/things"), repo, CRUDConfig[uuid.UUID]{Verbs: CRUDPatch})
+
+		patch := func(body string) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("PATCH", "/things/"+id.String(), strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			app.ServeHTTP(w, req)
+			return w
+		}
+
+		// Campo json "nickname" gravado como "nick_name"
+		patchedDoc := bson.D{current[0], {Key: "nick_name", Value: "bia"}, current[2]}
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, current),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: patchedDoc}),
+		)
+		w := patch(`{"nickname":"bia"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "find", mt.GetStartedEvent().CommandName)
+		set, err := mt.GetStartedEvent().Command.Lookup("update", "$set").Document().Elements()
+		assert.NoError(t, err)
+		if assert.Len(t, set, 1) {
+			assert.Equal(t, "nick_name", set[0].Key())
+		}
+
+		// Violação da tag validate não chega ao banco
+		mt.ClearEvents()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, current))
+		w = patch(`{"nickname":"muito longo"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"tag":"max"`)
+		assert.Equal(t, "find", mt.GetStartedEvent().CommandName)
+		assert.Nil(t, mt.GetStartedEvent())
+
+		// Nome bson ou campo inexistente no JSON é recusado antes de qualquer query
+		for _, body := range []string{`{"nick_name":"bia"}`, `{"stray":1}`, `{"nickname":5}`} {
+			w = patch(body)
+			assert.Equal(t, http.StatusBadRequest, w.Code, body)
+		}
+		assert.Nil(t, mt.GetStartedEvent())
+	})
+}
+
+func TestRepository_RestoreMany(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock).
+		ClientOptions(options.Client().SetRegistry(NewMongoRegistry())))
+	defer mt.Close()
+
+	mt.Run("restores tenant records", func(mt *mtest.T) {
+		repo := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{audit: true}}
+		tenantID := uuid.New()
+		ctx := context.WithValue(context.Background(), TenantIDKey, tenantID.String())
+		ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 3}, bson.E{Key: "nModified", Value: 3}))
+		restored, err := repo.RestoreMany(ctx, ids)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), restored)
+
+		update := mt.GetStartedEvent().Command.Lookup("updates", "0")
+		assert.True(t, update.Document().Lookup("multi").Boolean())
+
+		query := update.Document().Lookup("q").Document()
+		assert.False(t, query.Lookup("active").Boolean())
+		assertUUIDBinary(t, query.Lookup("tenant_id"), tenantID, "tenant filter")
+		in, err := query.Lookup("_id", "$in").Array().Values()
+		assert.NoError(t, err)
+		if assert.Len(t, in, 3) {
+			assertUUIDBinary(t, in[2], ids[2], "restored id")
+		}
+
+		u := update.Document().Lookup("u").Document()
+		assert.True(t, u.Lookup("$set", "active").Boolean())
+		assert.NotEqual(t, bsontype.Type(0), u.Lookup("$set", "updated").Type)
+		assert.NotEqual(t, bsontype.Type(0), u.Lookup("$unset", "deleted").Type)
+	})
+
+	mt.Run("matches Restore update", func(mt *mtest.T) {
+		for _, audit := range []bool{true, false} {
+			repo := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{audit: audit}}
+			ctx := contextWithTenant(uuid.New().String(), uuid.New().String())
+
+			mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+			assert.NoError(t, repo.Restore(ctx, uuid.New()))
+			single := mt.GetStartedEvent().Command.Lookup("updates", "0", "u").Document()
+
+			mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+			_, err := repo.RestoreMany(ctx, []uuid.UUID{uuid.New()})
+			assert.NoError(t, err)
+			many := mt.GetStartedEvent().Command.Lookup("updates", "0", "u").Document()
+
+			for _, u := range []bson.Raw{single, many} {
+				assert.True(t, u.Lookup("$set", "active").Boolean())
+				assert.NotEqual(t, bsontype.Type(0), u.Lookup("$unset", "deleted").Type, "deleted always unset")
+				_, err := u.LookupErr("$set", "updated")
+				assert.Equal(t, audit, err == nil, "updated stamped only with audit")
+			}
+		}
+	})
+
+	mt.Run("rejects invalid tenant", func(mt *mtest.T) {
+		repo := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{audit: true}}
+		ctx := context.WithValue(context.Background(), TenantIDKey, "not-a-uuid")
+
+		_, err := repo.RestoreMany(ctx, []uuid.UUID{uuid.New()})
+		assertBadRequest(t, err)
+	})
+
+	restored, err := (&Repository[*testEntity]{}).RestoreMany(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Zero(t, restored)
+}
+
+func TestRepository_DeleteWithReason(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock).
+		ClientOptions(options.Client().SetRegistry(NewMongoRegistry())))
+	defer mt.Close()
+
+	mt.Run("stores reason in deleted audit info", func(mt *mtest.T) {
+		repo := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{audit: true}}
+		id, tenantID := uuid.New(), uuid.New()
+		ctx := context.WithValue(context.Background(), TenantIDKey, tenantID.String())
+		doc := bson.D{
+			{Key: "_id", Value: UUIDBinaryEncoder(id)},
+			{Key: "tenant_id", Value: UUIDBinaryEncoder(tenantID)},
+			{Key: "active", Value: true},
+		}
+
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "db.coll", mtest.FirstBatch, doc),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: doc}),
+		)
+
+		err := repo.DeleteWithReason(ctx, id, "  Solicitação LGPD\n#123\x00  ")
+		assert.NoError(t, err)
+
+		events := mt.GetAllStartedEvents()
+		deleted := events[len(events)-1].Command.Lookup("update", "$set", "deleted").Document()
+		assert.Equal(t, "Solicitação LGPD #123", deleted.Lookup("reason").StringValue())
+		assert.False(t, deleted.Lookup("active").Boolean())
+	})
+
+	mt.Run("restore removes reason", func(mt *mtest.T) {
+		repo := &Repository[*testEntity]{collection: mt.Coll}
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+		assert.NoError(t, repo.Restore(context.Background(), uuid.New()))
+
+		update := mt.GetStartedEvent().Command.Lookup("updates", "0", "u").Document()
+		assert.True(t, update.Lookup("$set", "active").Boolean())
+		_, err := update.LookupErr("$unset", "deleted")
+		assert.NoError(t, err, "deleted (and its reason) must be unset on restore")
+	})
+
+	mt.Run("without audit", func(mt *mtest.T) {
+		repo := &Repository[*testEntity]{collection: mt.Coll}
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+		assert.NoError(t, repo.DeleteWithReason(context.Background(), uuid.New(), "duplicado"))
+
+		set := mt.GetStartedEvent().Command.Lookup("updates", "0", "u", "$set").Document()
+		assert.False(t, set.Lookup("active").Boolean())
+		assert.Equal(t, "duplicado", set.Lookup("deleted", "reason").StringValue())
+	})
+
+	repo := &Repository[*testEntity]{}
+	assertBadRequest(t, repo.DeleteWithReason(context.Background(), uuid.New(), " \x01\t "))
+
+	long := sanitizeReason(strings.Repeat("é", MaxDeleteReasonLength+10))
+	assert.Equal(t, MaxDeleteReasonLength, len([]rune(long)))
+}
+
+func TestRepository_IndexHint(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock).
+		ClientOptions(options.Client().SetRegistry(NewMongoRegistry())))
+	defer mt.Close()
+
+	repoWith := func(mt *mtest.T, opts ...RepositoryOption) *Repository[*testEntity] {
+		cfg := RepositoryConfig{audit: true}
+		for _, opt := range opts {
+			opt(&cfg)
+		}
+		return &Repository[*testEntity]{collection: mt.Coll, config: cfg}
+	}
+	ctx := contextWithTenant(uuid.New().String(), "")
+
+	mt.Run("tenant index keys", func(mt *mtest.T) {
+		repo := repoWith(mt, WithIsolationKeys("org_id"), WithTenantIndexHint())
+		ctx := WithTenantExtra(ctx, map[string]string{"org_id": "org-1"})
+
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "db.coll", mtest.FirstBatch, bson.D{{Key: "n", Value: 2}}),
+			mtest.CreateCursorResponse(0, "db.coll", mtest.FirstBatch),
+		)
+		_, _, err := repo.GetAllSkipTake(ctx, nil, Pagination{})
+		assert.NoError(t, err)
+
+		events := mt.GetAllStartedEvents()
+		if assert.Len(t, events, 2) {
+			countHint := events[0].Command.Lookup("hint").Document()
+			findHint := events[1].Command.Lookup("hint").Document()
+			for _, hint := range []bson.Raw{countHint, findHint} {
+				keys, _ := hint.Elements()
+				if assert.Len(t, keys, 3) {
+					assert.Equal(t, "active", keys[0].Key())
+					assert.Equal(t, "tenant_id", keys[1].Key())
+					assert.Equal(t, "org_id", keys[2].Key())
+				}
+			}
+		}
+	})
+
+	mt.Run("named index", func(mt *mtest.T) {
+		repo := repoWith(mt, WithIndexHint("active_1_tenant_id_1"))
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.coll", mtest.FirstBatch))
+		_, err := repo.GetDeleted(ctx, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "active_1_tenant_id_1", mt.GetStartedEvent().Command.Lookup("hint").StringValue())
+	})
+
+	mt.Run("no hint without audit", func(mt *mtest.T) {
+		repo := repoWith(mt, WithTenantIndexHint())
+		repo.config.audit = false
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.coll", mtest.FirstBatch))
+		_, err := repo.GetAll(context.Background(), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, bsontype.Type(0), mt.GetStartedEvent().Command.Lookup("hint").Type)
+		assert.Nil(t, repo.TenantIndexKeys())
+	})
+}
+
+func TestRepository_AggregateBuilder(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock).
+		ClientOptions(options.Client().SetRegistry(NewMongoRegistry())))
+	defer mt.Close()
+
+	mt.Run("prepends tenant match", func(mt *mtest.T) {
+		repo := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{audit: true}}
+		tenantID := uuid.New()
+		ctx := contextWithTenant(tenantID.String(), "")
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.coll", mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: "c1"}, {Key: "total", Value: 30}}))
+
+		results, err := repo.AggregateBuilder(ctx, Pipeline().
+			Match(map[string]interface{}{"status": "paid"}).
+			Group("$customer_id", map[string]interface{}{"total": map[string]interface{}{"$sum": "$amount"}}).
+			Sort(SortOption{Field: "total", Direction: -1}).
+			Limit(10))
+		assert.NoError(t, err)
+		if assert.Len(t, results, 1) {
+			assert.Equal(t, "c1", results[0]["_id"])
+		}
+
+		stages, err := mt.GetStartedEvent().Command.Lookup("pipeline").Array().Values()
+		assert.NoError(t, err)
+		if assert.Len(t, stages, 5) {
+			first := stages[0].Document().Lookup("$match").Document()
+			assert.True(t, first.Lookup("active").Boolean())
+			assertUUIDBinary(t, first.Lookup("tenant_id"), tenantID, "tenant match")
+			assert.Equal(t, "paid", stages[1].Document().Lookup("$match", "status").StringValue())
+			assert.Equal(t, "$customer_id", stages[2].Document().Lookup("$group", "_id").StringValue())
+			assert.Equal(t, int64(10), stages[4].Document().Lookup("$limit").Int64())
+		}
+	})
+
+	repo := &Repository[*testEntity]{config: RepositoryConfig{audit: true}}
+	ctx := contextWithTenant(uuid.New().String(), "")
+	for name, p := range map[string]*PipelineBuilder{
+		"where":       Pipeline().Match(map[string]interface{}{"$where": "sleep(1000)"}),
+		"and where":   Pipeline().Match(map[string]interface{}{"$and": []interface{}{bson.M{"$where": "sleep(1000)"}}}),
+		"or expr":     Pipeline().Match(map[string]interface{}{"$or": []interface{}{bson.M{"$expr": bson.M{"$function": bson.M{"body": "x"}}}}}),
+		"expr ref":    Pipeline().Group("$$ROOT", nil),
+		"accumulator": Pipeline().Group(nil, map[string]interface{}{"n": map[string]interface{}{"$function": "x"}}),
+		"sort":        Pipeline().Sort(SortOption{Field: "a$b", Direction: 1}),
+		"unwind":      Pipeline().Unwind("$items"),
+		"limit":       Pipeline().Limit(0),
+		"first error": Pipeline().Limit(-1).Match(map[string]interface{}{"ok": true}),
+	} {
+		_, err := repo.AggregateBuilder(ctx, p)
+		assertBadRequest(t, err)
+		_, err = p.Stages()
+		assert.Error(t, err, name)
+	}
+
+	// Pipelines montados à mão também não executam JavaScript
+	_, err := repo.AggregateRaw(ctx, []interface{}{
+		bson.M{"$match": bson.M{"$or": bson.A{bson.M{"$expr": bson.M{"$function": bson.M{"body": "sleep(1000)"}}}}}},
+	})
+	assertBadRequest(t, err)
+	_, err = AggregateInto[map[string]interface{}](ctx, repo, []interface{}{
+		bson.D{{Key: "$group", Value: bson.M{"_id": nil, "n": bson.M{"$accumulator": bson.M{}}}}},
+	})
+	assertBadRequest(t, err)
+}
+
+func TestAggregateInto_DecodesIntoResultType(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock).
+		ClientOptions(options.Client().SetRegistry(NewMongoRegistry())))
+	defer mt.Close()
+
+	mt.Run("group by field into count struct", func(mt *mtest.T) {
+		type nameCount struct {
+			Name  string `bson:"_id"`
+			Total int    `bson:"total"`
+		}
+		repo := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{audit: true}}
+		tenantID := uuid.New()
+		ctx := contextWithTenant(tenantID.String(), "")
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.coll", mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: "Ana"}, {Key: "total", Value: 3}},
+			bson.D{{Key: "_id", Value: "Bia"}, {Key: "total", Value: 1}}))
+
+		counts, err := AggregateInto[nameCount](ctx, repo, []interface{}{
+			bson.M{"$group": bson.M{"_id": "$name", "total": bson.M{"$sum": 1}}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []nameCount{{"Ana", 3}, {"Bia", 1}}, counts)
+
+		stages, err := mt.GetStartedEvent().Command.Lookup("pipeline").Array().Values()
+		assert.NoError(t, err)
+		if assert.Len(t, stages, 2) {
+			assertUUIDBinary(t, stages[0].Document().Lookup("$match", "tenant_id"), tenantID, "tenant match")
+			assert.Equal(t, "$name", stages[1].Document().Lookup("$group", "_id").StringValue())
+		}
+	})
+
+	// Sem tenant falha fechado, como o Aggregate
+	repo := &Repository[*testEntity]{config: RepositoryConfig{audit: true}}
+	_, err := AggregateInto[bson.M](context.Background(), repo, nil)
+	apiErr, ok := err.(*APIError)
+	if assert.True(t, ok, "expected *APIError, got %v", err) {
+		assert.Equal(t, http.StatusUnauthorized, apiErr.Code)
+	}
+}
+
+func TestRepository_CountBy(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock).
+		ClientOptions(options.Client().SetRegistry(NewMongoRegistry())))
+	defer mt.Close()
+
+	mt.Run("groups tenant records by field", func(mt *mtest.T) {
+		repo := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{audit: true}}
+		tenantID, ownerID := uuid.New(), uuid.New()
+		ctx := contextWithTenant(tenantID.String(), "")
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.coll", mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: "active"}, {Key: "count", Value: 120}},
+			bson.D{{Key: "_id", Value: UUIDBinaryEncoder(ownerID)}, {Key: "count", Value: int64(4)}},
+			bson.D{{Key: "_id", Value: nil}, {Key: "count", Value: 2}}))
+
+		counts, err := repo.CountBy(ctx, "status", map[string]interface{}{"name": "Ana"})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int64{"active": 120, ownerID.String(): 4, "": 2}, counts)
+
+		stages, err := mt.GetStartedEvent().Command.Lookup("pipeline").Array().Values()
+		assert.NoError(t, err)
+		if assert.Len(t, stages, 4) {
+			match := stages[0].Document()
+			assertUUIDBinary(t, match.Lookup("$match", "tenant_id"), tenantID, "tenant match")
+			assert.Equal(t, "Ana", match.Lookup("$match", "name").StringValue())
+			assert.Equal(t, "$status", stages[1].Document().Lookup("$group", "_id").StringValue())
+			assert.EqualValues(t, MaxCountByGroups, stages[3].Document().Lookup("$limit").AsInt64())
+		}
+	})
+
+	repo := &Repository[*testEntity]{}
+	for _, field := range []string{"", "$status", "status; drop", "$where"} {
+		_, err := repo.CountBy(context.Background(), field, nil)
+		assertBadRequest(t, err)
+	}
+}
+
+func TestRepository_ExportTenant(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock).
+		ClientOptions(options.Client().SetRegistry(NewMongoRegistry())))
+	defer mt.Close()
+
+	tenantID := uuid.New()
+	doc := func(name string, tenant uuid.UUID, active bool) bson.D {
+		return bson.D{
+			{Key: "_id", Value: UUIDBinaryEncoder(uuid.New())},
+			{Key: "name", Value: name},
+			{Key: "tenant_id", Value: UUIDBinaryEncoder(tenant)},
+			{Key: "active", Value: active},
+		}
+	}
+
+	mt.Run("endpoint streams ndjson", func(mt *mtest.T) {
+		repo := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{audit: true}}
+		app := New()
+		app.SetTenantExtractor(HeaderTenantExtractor(nil))
+		AddExportEndpoint(app.Group("/users"), repo)
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.coll", mtest.FirstBatch,
+			doc("Ana", tenantID, true), doc("Bia", tenantID, false)))
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/users/export?include_deleted=true", nil)
+		req.Header.Set(HeaderTenantID, tenantID.String())
+		app.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), tenantID.String())
+
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		if assert.Len(t, lines, 2) {
+			var second testEntity
+			assert.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+			assert.Equal(t, "Bia", second.Name)
+		}
+
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		assertUUIDBinary(t, filter.Lookup("tenant_id"), tenantID, "tenant filter")
+		assert.Equal(t, bsontype.Type(0), filter.Lookup("active").Type)
+	})
+
+	mt.Run("stops on foreign tenant document", func(mt *mtest.T) {
+		repo := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{audit: true}}
+		ctx := contextWithTenant(tenantID.String(), "")
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.coll", mtest.FirstBatch,
+			doc("Ana", tenantID, true), doc("Eve", uuid.New(), true)))
+
+		var buf bytes.Buffer
+		count, err := repo.ExportTenant(ctx, &buf, ExportOptions{})
+		assert.Error(t, err)
+		assert.Equal(t, int64(1), count)
+		assert.NotContains(t, buf.String(), "Eve")
+		assert.True(t, mt.GetStartedEvent().Command.Lookup("filter", "active").Boolean())
+	})
+
+	var buf bytes.Buffer
+	_, err := (&Repository[*testEntity]{}).ExportTenant(contextWithTenant(tenantID.String(), ""), &buf, ExportOptions{})
+	assertBadRequest(t, err)
+
+	_, err = (&Repository[*testEntity]{config: RepositoryConfig{audit: true}}).ExportTenant(context.Background(), &buf, ExportOptions{})
+	apiErr, ok := err.(*APIError)
+	if assert.True(t, ok) {
+		assert.Equal(t, http.StatusUnauthorized, apiErr.Code)
+	}
+}
+
+// importEntity entidade com um campo obrigatório para o validator
+type importEntity struct {
+	ID       uuid.UUID `bson:"_id" json:"id"`
+	Name     string    `bson:"name" json:"name"`
+	Code     string    `bson:"code" json:"code" validate:"required"`
+	TenantID uuid.UUID `bson:"tenant_id" json:"tenant_id"`
+	Active   bool      `bson:"active" json:"active"`
+	Created  AuditInfo `bson:"created" json:"created"`
+	Updated  AuditInfo `bson:"updated" json:"updated"`
+}
+
+func (e *importEntity) GetID() uuid.UUID          { return e.ID }
+func (e *importEntity) SetID(id uuid.UUID)        { e.ID = id }
+func (e *importEntity) GetTenantID() string       { return e.TenantID.String() }
+func (e *importEntity) SetTenantID(s string)      { e.TenantID, _ = uuid.Parse(s) }
+func (e *importEntity) SetCreated(info AuditInfo) { e.Created = info }
+func (e *importEntity) SetUpdated(info AuditInfo) { e.Updated = info }
+func (e *importEntity) SetDeleted(info AuditInfo) {}
+func (e *importEntity) SetActive(active bool)     { e.Active = active }
+
+func TestRepository_ImportTenant(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock).
+		ClientOptions(options.Client().SetRegistry(NewMongoRegistry())))
+	defer mt.Close()
+
+	tenantID := uuid.New()
+	ctx := contextWithTenant(tenantID.String(), uuid.New().String())
+
+	mt.Run("reports each line", func(mt *mtest.T) {
+		repo := &Repository[*importEntity]{collection: mt.Coll, config: RepositoryConfig{audit: true}}
+		input := strings.Join([]string{
+			`{"name":"Ana","code":"A1"}`,
+			`{"name":"Sem código"}`,
+			``,
+			`não é json`,
+			`{"name":"Eve","code":"E1","tenant_id":"` + uuid.New().String() + `"}`,
+			`{"name":"Bia","code":"B1","tenant_id":"` + tenantID.String() + `"}`,
+			`{"name":"Duplicado","code":"D1"}`,
+		}, "\n")
+
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 2, Code: 11000, Message: "E11000 duplicate key"}))
+		statuses := map[int]ImportStatus{}
+		report, err := repo.ImportTenant(ctx, strings.NewReader(input), ImportOptions{
+			OnResult: func(result ImportLineResult) { statuses[result.Line] = result.Status },
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, 6, report.Total)
+		assert.Equal(t, 2, report.Imported)
+		assert.Equal(t, 4, report.Failed)
+		assert.Equal(t, map[int]ImportStatus{
+			1: ImportSuccess, 2: ImportValidationError, 4: ImportValidationError,
+			5: ImportValidationError, 6: ImportSuccess, 7: ImportDuplicate,
+		}, statuses)
+
+		// O relatório guarda só as linhas recusadas
+		var failedLines []int
+		for _, result := range report.Errors {
+			failedLines = append(failedLines, result.Line)
+		}
+		assert.ElementsMatch(t, []int{2, 4, 5, 7}, failedLines)
+		assert.False(t, report.ErrorsTruncated)
+
+		started := mt.GetStartedEvent()
+		assert.Equal(t, "insert", started.CommandName)
+		assert.False(t, started.Command.Lookup("ordered").Boolean())
+		docs, _ := started.Command.Lookup("documents").Array().Values()
+		if assert.Len(t, docs, 3) {
+			for _, doc := range docs {
+				assertUUIDBinary(t, doc.Document().Lookup("tenant_id"), tenantID, "stamped tenant")
+				assert.True(t, doc.Document().Lookup("active").Boolean())
+			}
+		}
+	})
+
+	mt.Run("flushes in batches", func(mt *mtest.T) {
+		repo := &Repository[*importEntity]{collection: mt.Coll, config: RepositoryConfig{audit: true}}
+		input := `{"name":"A","code":"1"}` + "\n" + `{"name":"B","code":"2"}` + "\n" + `{"name":"C","code":"3"}`
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse())
+		report, err := repo.ImportTenant(ctx, strings.NewReader(input), ImportOptions{BatchSize: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, report.Imported)
+		assert.Len(t, mt.GetAllStartedEvents(), 2)
+	})
+
+	mt.Run("caps stored errors", func(mt *mtest.T) {
+		repo := &Repository[*importEntity]{collection: mt.Coll, config: RepositoryConfig{audit: true}}
+		input := strings.Repeat(`{"name":"Sem código"}`+"\n", 5)
+
+		report, err := repo.ImportTenant(ctx, strings.NewReader(input), ImportOptions{MaxErrors: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, 5, report.Failed)
+		assert.Len(t, report.Errors, 2)
+		assert.True(t, report.ErrorsTruncated)
+		assert.Empty(t, mt.GetAllStartedEvents())
+	})
+
+	_, err := (&Repository[*importEntity]{}).ImportTenant(ctx, strings.NewReader(""), ImportOptions{})
+	assertBadRequest(t, err)
+
+	_, err = (&Repository[*importEntity]{config: RepositoryConfig{audit: true}}).ImportTenant(context.Background(), strings.NewReader(""), ImportOptions{})
+	apiErr, ok := err.(*APIError)
+	if assert.True(t, ok) {
+		assert.Equal(t, http.StatusUnauthorized, apiErr.Code)
+	}
+}
+
+func TestRepository_RejectsJSFilterOperators(t *testing.T) {
+	repo := &Repository[*testEntity]{}
+	ctx := context.Background()
+
+	for name, filters := range map[string]map[string]interface{}{
+		"root":   {"$where": "sleep(1000)"},
+		"nested": {"$or": []interface{}{map[string]interface{}{"name": "a"}, bson.M{"$function": bson.M{}}}},
+		"bson.D": {"score": bson.D{{Key: "$accumulator", Value: "x"}}},
+	} {
+		methods := map[string]func() error{
+			"GetFirst":               func() error { _, err := repo.GetFirst(ctx, filters); return err },
+			"GetAll":                 func() error { _, err := repo.GetAll(ctx, filters); return err },
+			"GetAllSkipTake":         func() error { _, _, err := repo.GetAllSkipTake(ctx, filters, Pagination{}); return err },
+			"GetAfter":               func() error { _, _, err := repo.GetAfter(ctx, filters, "", 10); return err },
+			"Count":                  func() error { _, err := repo.Count(ctx, filters); return err },
+			"CountAll":               func() error { _, err := repo.CountAll(ctx, filters); return err },
+			"CountBy":                func() error { _, err := repo.CountBy(ctx, "status", filters); return err },
+			"GetAllIncludingDeleted": func() error { _, err := repo.GetAllIncludingDeleted(ctx, filters); return err },
+			"GetDeleted":             func() error { _, err := repo.GetDeleted(ctx, filters); return err },
+			"DeleteMany":             func() error { _, err := repo.DeleteMany(ctx, filters); return err },
+			"UpdateMany":             func() error { _, err := repo.UpdateMany(ctx, filters, map[string]interface{}{"name": "x"}); return err },
+			"Upsert":                 func() error { _, err := repo.Upsert(ctx, filters, &testEntity{}); return err },
+			"ExistsBy":               func() error { _, err := repo.ExistsBy(ctx, filters); return err },
+			"Stream": func() error {
+				items, errs := repo.Stream(ctx, filters)
+				for range items {
+				}
+				return <-errs
+			},
+		}
+		for method, call := range methods {
+			t.Run(name+"/"+method, func(t *testing.T) {
+				assertBadRequest(t, call())
+			})
+		}
+	}
+
+	// Operadores comuns continuam liberados
+	assert.Empty(t, jsFilterOperator(map[string]interface{}{
+		"status": map[string]interface{}{"$in": []interface{}{"a", "b"}},
+		"$or":    []interface{}{map[string]interface{}{"age": bson.M{"$gt": 18}}},
+	}))
+}
+
+func TestRepository_TenantFilterPolicy(t *testing.T) {
+	repo := &Repository[*testEntity]{config: RepositoryConfig{audit: true}}
+	id := uuid.New()
+	filters := map[string]interface{}{"name": "Ana"}
+
+	methods := map[string]func(ctx context.Context) error{
+		"Create":                 func(ctx context.Context) error { _, err := repo.Create(ctx, &testEntity{}); return err },
+		"BulkCreate":             func(ctx context.Context) error { _, err := repo.BulkCreate(ctx, []*testEntity{{}}); return err },
+		"Upsert":                 func(ctx context.Context) error { _, err := repo.Upsert(ctx, filters, &testEntity{}); return err },
+		"GetByID":                func(ctx context.Context) error { _, err := repo.GetByID(ctx, id); return err },
+		"GetByIDs":               func(ctx context.Context) error { _, err := repo.GetByIDs(ctx, []uuid.UUID{id}); return err },
+		"GetFirst":               func(ctx context.Context) error { _, err := repo.GetFirst(ctx, filters); return err },
+		"GetAll":                 func(ctx context.Context) error { _, err := repo.GetAll(ctx, filters); return err },
+		"GetAllSkipTake":         func(ctx context.Context) error { _, _, err := repo.GetAllSkipTake(ctx, filters, Pagination{}); return err },
+		"GetAfter":               func(ctx context.Context) error { _, _, err := repo.GetAfter(ctx, filters, "", 10); return err },
+		"GetAllIncludingDeleted": func(ctx context.Context) error { _, err := repo.GetAllIncludingDeleted(ctx, filters); return err },
+		"GetDeleted":             func(ctx context.Context) error { _, err := repo.GetDeleted(ctx, filters); return err },
+		"Count":                  func(ctx context.Context) error { _, err := repo.Count(ctx, filters); return err },
+		"CountAll":               func(ctx context.Context) error { _, err := repo.CountAll(ctx, filters); return err },
+		"CountBy":                func(ctx context.Context) error { _, err := repo.CountBy(ctx, "status", filters); return err },
+		"ExistsBy":               func(ctx context.Context) error { _, err := repo.ExistsBy(ctx, filters); return err },
+		"Aggregate":              func(ctx context.Context) error { _, err := repo.Aggregate(ctx, nil); return err },
+		"AggregateRaw":           func(ctx context.Context) error { _, err := repo.AggregateRaw(ctx, nil); return err },
+		"Update":                 func(ctx context.Context) error { _, err := repo.Update(ctx, id, &testEntity{}); return err },
+		"Patch":                  func(ctx context.Context) error { _, err := repo.Patch(ctx, id, map[string]interface{}{"name": "x"}); return err },
+		"UpdateMany":             func(ctx context.Context) error { _, err := repo.UpdateMany(ctx, filters, map[string]interface{}{"name": "x"}); return err },
+		"Delete":                 func(ctx context.Context) error { return repo.Delete(ctx, id) },
+		"DeleteWithReason":       func(ctx context.Context) error { return repo.DeleteWithReason(ctx, id, "LGPD") },
+		"DeleteMany":             func(ctx context.Context) error { _, err := repo.DeleteMany(ctx, filters); return err },
+		"HardDelete":             func(ctx context.Context) error { return repo.HardDelete(ctx, id) },
+		"Restore":                func(ctx context.Context) error { return repo.Restore(ctx, id) },
+		"RestoreMany":            func(ctx context.Context) error { _, err := repo.RestoreMany(ctx, []uuid.UUID{id}); return err },
+		"Stream": func(ctx context.Context) error {
+			items, errs := repo.Stream(ctx, filters)
+			for range items {
+			}
+			return <-errs
+		},
+	}
+
+	// Mesma política em todos os métodos: sem tenant 401, tenant inválido 400, sem tocar no banco
+	for name, call := range methods {
+		t.Run(name, func(t *testing.T) {
+			apiErr, ok := call(context.Background()).(*APIError)
+			if assert.True(t, ok, "missing tenant") {
+				assert.Equal(t, http.StatusUnauthorized, apiErr.Code)
+			}
+			assertBadRequest(t, call(contextWithTenant("not-a-uuid", "")))
+		})
+	}
+}
+
+func TestRepository_TenantFilterNotOverridable(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock).
+		ClientOptions(options.Client().SetRegistry(NewMongoRegistry())))
+	defer mt.Close()
+
+	tenantID := uuid.New()
+	ctx := contextWithTenant(tenantID.String(), "")
+	foreign := map[string]interface{}{"tenant_id": UUIDBinaryEncoder(uuid.New()), "name": "Ana"}
+
+	mt.Run("list and count", func(mt *mtest.T) {
+		repo := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{audit: true}}
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "db.coll", mtest.FirstBatch),
+			mtest.CreateCursorResponse(1, "db.coll", mtest.FirstBatch, bson.D{{Key: "n", Value: 0}}),
+			mtest.CreateCursorResponse(0, "db.coll", mtest.FirstBatch),
+		)
+
+		_, err := repo.GetAll(ctx, foreign)
+		assert.NoError(t, err)
+		_, err = repo.Count(ctx, foreign)
+		assert.NoError(t, err)
+		_, err = repo.GetDeleted(ctx, foreign)
+		assert.NoError(t, err)
+
+		for _, event := range mt.GetAllStartedEvents() {
+			filter := event.Command.Lookup("filter")
+			if event.CommandName == "aggregate" {
+				filter = event.Command.Lookup("pipeline", "0", "$match")
+			}
+			assertUUIDBinary(t, filter.Document().Lookup("tenant_id"), tenantID, event.CommandName)
+			assert.Equal(t, "Ana", filter.Document().Lookup("name").StringValue())
+		}
+	})
+}
+
+func TestContext_StreamPaged(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock).
+		ClientOptions(options.Client().SetRegistry(NewMongoRegistry())))
+	defer mt.Close()
+
+	mt.Run("streams documents across batches", func(mt *mtest.T) {
+		ids := make([]uuid.UUID, 150)
+		docs := make([]bson.D, len(ids))
+		for i := range ids {
+			ids[i] = uuid.New()
+			docs[i] = bson.D{{Key: "_id", Value: UUIDBinaryEncoder(ids[i])}, {Key: "name", Value: "user"}, {Key: "active", Value: true}}
+		}
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(1, "db.coll", mtest.FirstBatch, docs[:100]...),
+			mtest.CreateCursorResponse(0, "db.coll", mtest.NextBatch, docs[100:]...),
+		)
+
+		app := New()
+		app.GET("/users", Handle(func(c *Context[*testEntity]) error {
+			cursor, err := mt.Coll.Find(c.Request.Context(), bson.M{}, options.Find().SetSkip(20).SetLimit(150))
+			if err != nil {
+				return err
+			}
+			return c.StreamPaged(cursor, 400, 20, 150)
+		}))
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/users", nil)
+		app.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var body struct {
+			Success bool         `json:"success"`
+			Message string       `json:"message"`
+			Total   int64        `json:"total"`
+			Skip    int          `json:"skip"`
+			Take    int          `json:"take"`
+			HasMore bool         `json:"has_more"`
+			Data    []testEntity `json:"data"`
+		}
+		if assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), w.Body.String()) {
+			assert.True(t, body.Success)
+			assert.Equal(t, MsgRetrievedSuccess, body.Message)
+			assert.Equal(t, int64(400), body.Total)
+			assert.Equal(t, 20, body.Skip)
+			assert.Equal(t, 150, body.Take)
+			assert.True(t, body.HasMore)
+			if assert.Len(t, body.Data, 150) {
+				assert.Equal(t, ids[0], body.Data[0].ID)
+				assert.Equal(t, ids[149], body.Data[149].ID)
+			}
+		}
+	})
+
+	mt.Run("last page with generic documents", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.coll", mtest.FirstBatch,
+			bson.D{{Key: "name", Value: "Ana"}}))
+
+		app := New()
+		app.GET("/users", Handle(func(c *Context[any]) error {
+			cursor, err := mt.Coll.Find(c.Request.Context(), bson.M{})
+			if err != nil {
+				return err
+			}
+			return c.StreamPaged(cursor, 1, 0, 10)
+		}))
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/users", nil)
+		app.ServeHTTP(w, req)
+
+		var body map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), w.Body.String())
+		assert.Equal(t, false, body["has_more"])
+		assert.Equal(t, []interface{}{map[string]interface{}{"name": "Ana"}}, body["data"])
+	})
+}
+
+func mustCursor(t *testing.T, doc bson.D, field string) string {
+	t.Helper()
+	raw, err := bson.Marshal(doc)
+	assert.NoError(t, err)
+	cursor, err := encodeKeysetCursor(raw, field)
+	assert.NoError(t, err)
+	return cursor
+}
+
+func TestRepository_IDGenerator(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock).
+		ClientOptions(options.Client().SetRegistry(NewMongoRegistry())))
+	defer mt.Close()
+
+	mt.Run("injected generator", func(mt *mtest.T) {
+		var generated []uuid.UUID
+		repo := NewRepository[*testEntity](mt.Coll, WithIDGenerator(func() uuid.UUID {
+			id := uuid.New()
+			generated = append(generated, id)
+			return id
+		}))
+		mt.ClearEvents()
+		mt.AddMockResponses(mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse())
+
+		created, err := repo.Create(context.Background(), &testEntity{Name: "Ana"})
+		assert.NoError(t, err)
+		bulk, err := repo.BulkCreate(context.Background(), []*testEntity{{Name: "Bia"}, {ID: uuid.New(), Name: "Caio"}})
+		assert.NoError(t, err)
+
+		// Entidade que já tem ID mantém o seu
+		if assert.Len(t, generated, 2) {
+			assert.Equal(t, generated[0], created.ID)
+			assert.Equal(t, generated[1], bulk[0].ID)
+		}
+		docs := mt.GetStartedEvent().Command.Lookup("documents").Array()
+		assertUUIDBinary(t, docs.Index(0).Value().Document().Lookup("_id"), generated[0], "_id")
+	})
+
+	mt.Run("default uuid v4", func(mt *mtest.T) {
+		repo := &Repository[*testEntity]{collection: mt.Coll}
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		created, err := repo.Create(context.Background(), &testEntity{})
+		assert.NoError(t, err)
+		assert.Equal(t, uuid.Version(4), created.ID.Version())
+	})
+}
+
+func TestAddHistoryEndpoint(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock).
+		ClientOptions(options.Client().SetRegistry(NewMongoRegistry())))
+	defer mt.Close()
+
+	mt.Run("tenant scoped history", func(mt *mtest.T) {
+		repo := &Repository[*testEntity]{collection: mt.Coll, history: NewHistoryManager(mt.Coll)}
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		tenantID := uuid.New()
+		entityID := uuid.New()
+
+		app := New()
+		app.Use(TenantMiddleware(nil))
+		AddHistoryEndpoint(app.Group("/users"), repo)
+
+		request := func(path, tenant string) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", path, nil)
+			if tenant != "" {
+				req.Header.Set("X-Tenant-ID", tenant)
+			}
+			app.ServeHTTP(w, req)
+			return w
+		}
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: UUIDBinaryEncoder(uuid.New())},
+			{Key: "entity_id", Value: UUIDBinaryEncoder(entityID)},
+			{Key: "entity_type", Value: "User"},
+		}))
+		w := request("/users/"+entityID.String()+"/history", tenantID.String())
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var body map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, float64(1), body["total"])
+		filter := mt.GetStartedEvent().Command.Lookup("filter")
+		assertUUIDBinary(t, filter.Document().Lookup("tenant_id"), tenantID, "history tenant_id")
+
+		assert.Equal(t, http.StatusUnauthorized, request("/users/"+entityID.String()+"/history", "").Code)
+		assert.Equal(t, http.StatusBadRequest, request("/users/not-a-uuid/history", tenantID.String()).Code)
+	})
+}
+
+func TestFilterGuard_Validate(t *testing.T) {
+	guard := FilterGuard{MaxKeys: 5, MaxValueLength: 10, MaxArrayLength: 3, MaxDepth: 2}
+
+	assert.NoError(t, guard.Validate(map[string]interface{}{
+		"_id":    uuid.New(),
+		"status": map[string]interface{}{"$in": []string{"a", "b"}},
+	}))
+
+	// Array no nível raiz também é limitado
+	assertBadRequest(t, guard.Validate(map[string]interface{}{"ids": []interface{}{1, 2, 3, 4}}))
+	assertBadRequest(t, guard.Validate(map[string]interface{}{"name": "way too long value"}))
+	assertBadRequest(t, guard.Validate(map[string]interface{}{
+		"a": map[string]interface{}{"b": map[string]interface{}{"c": map[string]interface{}{"d": 1}}},
+	}))
+	// Chaves aninhadas contam no total
+	assertBadRequest(t, guard.Validate(map[string]interface{}{
+		"a": 1, "b": 2, "c": map[string]interface{}{"d": 1, "e": 2, "f": 3},
+	}))
+
+	// bson.M e outros maps nomeados também contam chaves e profundidade
+	assert.NoError(t, guard.Validate(map[string]interface{}{"status": bson.M{"$in": bson.A{"a", "b"}}}))
+	assertBadRequest(t, guard.Validate(map[string]interface{}{
+		"a": bson.M{"b": bson.M{"c": bson.M{"d": 1}}},
+	}))
+	assertBadRequest(t, guard.Validate(map[string]interface{}{
+		"a": 1, "b": 2, "c": bson.M{"d": 1, "e": 2, "f": 3},
+	}))
+	assertBadRequest(t, guard.Validate(map[string]interface{}{
+		"a": map[string]string{"b": "way too long value"},
+	}))
+
+	// O InputSanitizer aplica o mesmo guard
+	_, err := NewInputSanitizer().WithGuard(guard).Sanitize(map[string]interface{}{"name": "way too long value"})
+	assertBadRequest(t, err)
+}
+
+func TestFilterGuardMiddleware(t *testing.T) {
+	app := New()
+	app.Use(FilterGuardMiddleware(FilterGuard{MaxKeys: 2, MaxArrayLength: 2}))
+	app.GET("/items", Handle(func(c *Context[any]) error {
+		c.Success("ok", nil)
+		return nil
+	}))
+
+	request := func(query string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/items?"+query, nil)
+		app.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request("name=a&status=b"))
+	assert.Equal(t, http.StatusBadRequest, request("a=1&b=2&c=3"))
+	assert.Equal(t, http.StatusBadRequest, request("id=1&id=2&id=3"))
+}
+
+// slowMongoListener servidor TCP que aceita conexões e nunca responde, como um MongoDB travado
+func slowMongoListener(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		var conns []net.Conn
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				for _, c := range conns {
+					c.Close()
+				}
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestRepository_OperationTimeout(t *testing.T) {
+	addr := slowMongoListener(t)
+	client, err := mongo.Connect(context.Background(), options.Client().
+		ApplyURI("mongodb://"+addr).
+		SetDirect(true).
+		SetRegistry(NewMongoRegistry()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect(context.Background())
+
+	repo := NewRepository[*testEntity](client.Database("test").Collection("entities"),
+		WithOperationTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	_, err = repo.GetByID(context.Background(), uuid.New())
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	apiErr, ok := err.(*APIError)
+	if assert.True(t, ok, "expected *APIError, got %v", err) {
+		assert.Equal(t, GatewayTimeoutErrorType, apiErr.Type)
+		assert.Equal(t, http.StatusGatewayTimeout, apiErr.Code)
+		assert.Equal(t, ErrorCodeGatewayTimeout, apiErr.ErrorCode)
+	}
+
+	app := New()
+	app.GET("/entities/:id", Handle(func(c *Context[any]) error {
+		_, err := repo.GetByID(c.Request.Context(), uuid.New())
+		return err
+	}))
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/entities/1", nil))
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Contains(t, w.Body.String(), ErrorCodeGatewayTimeout)
+}
+
+func TestRepository_DeleteHooks(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock).
+		ClientOptions(options.Client().SetRegistry(NewMongoRegistry())))
+	defer mt.Close()
+
+	// newHook guarda a transição, a entidade e o usuário do ctx de cada chamada
+	type call struct {
+		transition string
+		entity     *testEntity
+		userID     string
+	}
+	newHook := func(calls *[]call, err error) DeleteHookFuncs[*testEntity] {
+		record := func(transition string) func(context.Context, *testEntity) error {
+			return func(ctx context.Context, entity *testEntity) error {
+				*calls = append(*calls, call{transition, entity, GetTenantInfo(ctx).UserID})
+				return err
+			}
+		}
+		return DeleteHookFuncs[*testEntity]{
+			SoftDelete: record("soft"),
+			Restore:    record("restore"),
+			HardDelete: record("hard"),
+		}
+	}
+
+	tenantID, userID := uuid.New(), uuid.New()
+	doc := func(id uuid.UUID, active bool) bson.D {
+		return bson.D{
+			{Key: "_id", Value: UUIDBinaryEncoder(id)},
+			{Key: "name", Value: "Ana"},
+			{Key: "tenant_id", Value: UUIDBinaryEncoder(tenantID)},
+			{Key: "active", Value: active},
+		}
+	}
+
+	mt.Run("soft delete fires OnSoftDelete with the deleted entity", func(mt *mtest.T) {
+		var calls []call
+		repo := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{
+			audit: true, deleteHook: DeleteHook[*testEntity](newHook(&calls, nil)),
+		}}
+		id := uuid.New()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "db.coll", mtest.FirstBatch, doc(id, true)),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: doc(id, false)}),
+		)
+
+		err := repo.DeleteWithReason(contextWithTenant(tenantID.String(), userID.String()), id, "LGPD")
+		assert.NoError(t, err)
+		if assert.Len(t, calls, 1) {
+			assert.Equal(t, "soft", calls[0].transition)
+			assert.Equal(t, id, calls[0].entity.ID)
+			assert.False(t, calls[0].entity.Active)
+			assert.Equal(t, userID.String(), calls[0].userID)
+		}
+	})
+
+	mt.Run("restore fires OnRestore with the restored entity", func(mt *mtest.T) {
+		var calls []call
+		repo := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{
+			audit: true, deleteHook: DeleteHook[*testEntity](newHook(&calls, nil)),
+		}}
+		id := uuid.New()
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: doc(id, true)}))
+
+		assert.NoError(t, repo.Restore(contextWithTenant(tenantID.String(), userID.String()), id))
+		if assert.Len(t, calls, 1) {
+			assert.Equal(t, "restore", calls[0].transition)
+			assert.True(t, calls[0].entity.Active)
+			assert.Equal(t, userID.String(), calls[0].userID)
+		}
+		filter := mt.GetStartedEvent().Command.Lookup("query").Document()
+		assertUUIDBinary(t, filter.Lookup("tenant_id"), tenantID, "tenant filter")
+	})
+
+	mt.Run("hard delete fires OnHardDelete with the removed document", func(mt *mtest.T) {
+		var calls []call
+		repo := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{
+			audit: true, deleteHook: DeleteHook[*testEntity](newHook(&calls, nil)),
+		}}
+		id := uuid.New()
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: doc(id, false)}))
+
+		assert.NoError(t, repo.HardDelete(contextWithTenant(tenantID.String(), userID.String()), id))
+		if assert.Len(t, calls, 1) {
+			assert.Equal(t, "hard", calls[0].transition)
+			assert.Equal(t, id, calls[0].entity.ID)
+			assert.Equal(t, "Ana", calls[0].entity.Name)
+		}
+	})
+
+	mt.Run("hook error is logged without rolling back", func(mt *mtest.T) {
+		var calls []call
+		repo := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{
+			deleteHook: DeleteHook[*testEntity](newHook(&calls, assert.AnError)),
+		}}
+		id := uuid.New()
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: doc(id, false)}))
+
+		assert.NoError(t, repo.Delete(context.Background(), id))
+		assert.Len(t, calls, 1)
+		assert.Equal(t, "findAndModify", mt.GetStartedEvent().CommandName)
+		assert.Nil(t, mt.GetStartedEvent(), "no revert write expected")
+	})
+
+	mt.Run("rollback reverts soft delete and returns the hook error", func(mt *mtest.T) {
+		var calls []call
+		repo := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{
+			deleteHook: DeleteHook[*testEntity](newHook(&calls, assert.AnError)), deleteHookRollback: true,
+		}}
+		id := uuid.New()
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: doc(id, false)}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+		)
+
+		assert.ErrorIs(t, repo.Delete(context.Background(), id), assert.AnError)
+		mt.GetStartedEvent() // findAndModify
+
+		revert := mt.GetStartedEvent()
+		if assert.NotNil(t, revert) && assert.Equal(t, "update", revert.CommandName) {
+			update := revert.Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("u").Document()
+			assert.True(t, update.Lookup("$set", "active").Boolean())
+			_, err := update.LookupErr("$unset", "deleted")
+			assert.NoError(t, err, "deleted must be unset")
+		}
+	})
+
+	mt.Run("rollback reverts restore", func(mt *mtest.T) {
+		var calls []call
+		repo := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{
+			deleteHook: DeleteHook[*testEntity](newHook(&calls, assert.AnError)), deleteHookRollback: true,
+		}}
+		id := uuid.New()
+		before := append(doc(id, false), bson.E{Key: "deleted", Value: bson.D{{Key: "reason", Value: "LGPD"}}})
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: before}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+		)
+
+		assert.ErrorIs(t, repo.Restore(context.Background(), id), assert.AnError)
+		if assert.Len(t, calls, 1) {
+			assert.True(t, calls[0].entity.Active)
+			assert.Empty(t, calls[0].entity.Deleted.Reason)
+		}
+		mt.GetStartedEvent() // findAndModify
+
+		revert := mt.GetStartedEvent()
+		if assert.NotNil(t, revert) && assert.Equal(t, "update", revert.CommandName) {
+			update := revert.Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("u").Document()
+			assert.False(t, update.Lookup("$set", "active").Boolean())
+			assert.Equal(t, "LGPD", update.Lookup("$set", "deleted", "reason").StringValue(), "previous deleted info restored")
+		}
+	})
+
+	mt.Run("rollback runs after the operation timeout expired", func(mt *mtest.T) {
+		slow := DeleteHookFuncs[*testEntity]{SoftDelete: func(ctx context.Context, _ *testEntity) error {
+			<-ctx.Done() // hook lento: estoura o WithOperationTimeout
+			return assert.AnError
+		}}
+		repo := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{
+			deleteHook: DeleteHook[*testEntity](slow), deleteHookRollback: true, opTimeout: 20 * time.Millisecond,
+		}}
+		id := uuid.New()
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: doc(id, false)}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+		)
+
+		err := repo.Delete(context.Background(), id)
+		assert.ErrorIs(t, err, assert.AnError, "revert must succeed and return the hook error, not a timeout")
+		mt.GetStartedEvent() // findAndModify
+		revert := mt.GetStartedEvent()
+		if assert.NotNil(t, revert) {
+			assert.Equal(t, "update", revert.CommandName)
+		}
+	})
+
+	mt.Run("DeleteMany fires OnSoftDelete per entity", func(mt *mtest.T) {
+		var calls []call
+		repo := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{
+			deleteHook: DeleteHook[*testEntity](newHook(&calls, nil)),
+		}}
+		first, second := uuid.New(), uuid.New()
+		idDoc := func(id uuid.UUID) bson.D { return bson.D{{Key: "_id", Value: UUIDBinaryEncoder(id)}} }
+		// Um lote por vez: o hook do primeiro roda antes do getMore do segundo
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(42, "db.coll", mtest.FirstBatch, idDoc(first)),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: doc(first, false)}),
+			mtest.CreateCursorResponse(0, "db.coll", mtest.NextBatch, idDoc(second)),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: doc(second, false)}),
+		)
+
+		count, err := repo.DeleteMany(context.Background(), map[string]interface{}{"name": "Ana"})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+		if assert.Len(t, calls, 2) {
+			assert.Equal(t, first, calls[0].entity.ID)
+			assert.Equal(t, "Ana", calls[0].entity.Name)
+			assert.Equal(t, second, calls[1].entity.ID)
+		}
+
+		find := mt.GetStartedEvent()
+		assert.Equal(t, "find", find.CommandName)
+		assert.Equal(t, int32(1), find.Command.Lookup("projection", "_id").Int32())
+		var commands []string
+		for e := mt.GetStartedEvent(); e != nil; e = mt.GetStartedEvent() {
+			commands = append(commands, e.CommandName)
+		}
+		assert.Equal(t, []string{"findAndModify", "getMore", "findAndModify"}, commands)
+	})
+
+	mt.Run("RestoreMany fires OnRestore per entity", func(mt *mtest.T) {
+		var calls []call
+		repo := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{
+			audit: true, deleteHook: DeleteHook[*testEntity](newHook(&calls, nil)),
+		}}
+		restored, missing := uuid.New(), uuid.New()
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: doc(restored, false)}),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}),
+		)
+
+		count, err := repo.RestoreMany(contextWithTenant(tenantID.String(), userID.String()), []uuid.UUID{restored, missing})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+		if assert.Len(t, calls, 1) {
+			assert.Equal(t, "restore", calls[0].transition)
+			assert.Equal(t, restored, calls[0].entity.ID)
+		}
+	})
+
+	mt.Run("rollback reinserts the hard deleted document", func(mt *mtest.T) {
+		var calls []call
+		repo := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{
+			deleteHook: DeleteHook[*testEntity](newHook(&calls, assert.AnError)), deleteHookRollback: true,
+		}}
+		id := uuid.New()
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: doc(id, false)}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+		)
+
+		assert.ErrorIs(t, repo.HardDelete(context.Background(), id), assert.AnError)
+		mt.GetStartedEvent() // findAndModify
+
+		revert := mt.GetStartedEvent()
+		if assert.NotNil(t, revert) && assert.Equal(t, "insert", revert.CommandName) {
+			inserted := revert.Command.Lookup("documents").Array().Index(0).Value().Document()
+			assertUUIDBinary(t, inserted.Lookup("_id"), id, "reinserted _id")
+			assert.Equal(t, "Ana", inserted.Lookup("name").StringValue())
+		}
+	})
+
+	mt.Run("mismatched hook type panics on creation", func(mt *mtest.T) {
+		assert.Panics(t, func() {
+			NewRepository[*testEntity](mt.Coll, WithDeleteHook[*orgTestEntity](DeleteHookFuncs[*orgTestEntity]{}))
+		})
+
+		// Depois de criado, o caminho de delete nunca entra em panic por causa do tipo
+		repo := &Repository[*testEntity]{collection: mt.Coll, config: RepositoryConfig{
+			deleteHook: DeleteHook[*orgTestEntity](DeleteHookFuncs[*orgTestEntity]{}),
+		}}
+		assert.NotPanics(t, func() { assert.Nil(t, repo.deleteHook()) })
+	})
+}
+
+// countingCache conta as leituras por chave do CacheProvider
+type countingCache struct {
+	CacheProvider
+	gets map[string]int
+}
+
+func (c *countingCache) Get(ctx context.Context, key string) ([]byte, bool) {
+	c.gets[key]++
+	return c.CacheProvider.Get(ctx, key)
+}
+
+func TestContext_NotModifiedWithCachedListETag(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock).
+		ClientOptions(options.Client().SetRegistry(NewMongoRegistry())))
+	defer mt.Close()
+
+	mt.Run("repeat request with prior etag", func(mt *mtest.T) {
+		base := &Repository[*testEntity]{collection: mt.Coll}
+		cache := &countingCache{NewMemoryCache(MemoryCacheConfig{CacheConfig: CacheConfig{TTL: time.Minute}}), map[string]int{}}
+		repo := NewCachedRepository(base, cache, CacheConfig{}, "Test")
+		ctx := contextWithTenant(uuid.New().String(), "")
+		listKey, _ := repo.listKey(ctx)
+
+		app := New()
+		app.GET("/entities", Handle(func(c *Context[any]) error {
+			if c.NotModified(repo.ListETag(ctx)) {
+				return nil
+			}
+			items, err := repo.GetAll(ctx, nil)
+			if err != nil {
+				return err
+			}
+			c.SetETag(repo.ListETag(ctx))
+			c.Success(MsgRetrievedSuccess, items)
+			return nil
+		}))
+		get := func(etag string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, "/entities", nil)
+			if etag != "" {
+				req.Header.Set("If-None-Match", etag)
+			}
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, req)
+			return w
+		}
+
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: UUIDBinaryEncoder(uuid.New())}, {Key: "active", Value: true}}))
+
+		first := get("")
+		assert.Equal(t, http.StatusOK, first.Code)
+		etag := first.Header().Get("ETag")
+		assert.NotEmpty(t, etag)
+
+		// Mesmo ETag: 304 sem body, sem banco e sem ler a lista do cache
+		cache.gets = map[string]int{}
+		mt.ClearEvents()
+		repeat := get(etag)
+		assert.Equal(t, http.StatusNotModified, repeat.Code)
+		assert.Empty(t, repeat.Body.String())
+		assert.Equal(t, etag, repeat.Header().Get("ETag"))
+		assert.Zero(t, cache.gets[listKey])
+		assert.Nil(t, mt.GetStartedEvent())
+
+		// Escrita troca a versão: o ETag antigo não casa mais
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch),
+		)
+		assert.NoError(t, repo.Delete(ctx, uuid.New()))
+		stale := get(etag)
+		assert.Equal(t, http.StatusOK, stale.Code)
+		assert.NotEmpty(t, stale.Header().Get("ETag"))
+		assert.NotEqual(t, etag, stale.Header().Get("ETag"))
+	})
+
+	assert.True(t, etagMatches(`"a", W/"b"`, `W/"b"`))
+	assert.True(t, etagMatches(`*`, `W/"b"`))
+	assert.False(t, etagMatches(`"a"`, `W/"b"`))
+}
@@ -0,0 +1,140 @@
+package zendia
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// responseCachePrefix prefixo das chaves de resposta no CacheProvider
+const responseCachePrefix = "response:"
+
+// ResponseCache cache de respostas GET completas (status 200 + body) num CacheProvider
+// Complementa o CachedRepository para respostas caras de montar (agregações, relatórios)
+type ResponseCache struct {
+	cache CacheProvider
+	ttl   time.Duration
+	keyFn func(*gin.Context) string
+}
+
+// cachedResponse formato serializado de uma resposta no cache
+type cachedResponse struct {
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// NewResponseCache cria o cache de respostas
+// keyFn nil usa DefaultResponseCacheKey (tenant + path + query); keyFn retornando "" não cacheia
+func NewResponseCache(cache CacheProvider, ttl time.Duration, keyFn func(*gin.Context) string) *ResponseCache {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &ResponseCache{cache: cache, ttl: ttl, keyFn: keyFn}
+}
+
+// CacheResponse middleware de cache de resposta para uso direto em rotas e grupos
+//
+// Uso:
+//
+//	reports.GET("/summary", zendia.CacheResponse(cache, time.Minute, nil), handler)
+func CacheResponse(cache CacheProvider, ttl time.Duration, keyFn func(*gin.Context) string) gin.HandlerFunc {
+	return NewResponseCache(cache, ttl, keyFn).Middleware()
+}
+
+// ResponseCacheKey chave padrão de uma resposta, para invalidar com Invalidate
+func ResponseCacheKey(tenantID, path, rawQuery string) string {
+	key := responseCachePrefix + tenantID + ":" + path
+	if rawQuery != "" {
+		key += "?" + rawQuery
+	}
+	return key
+}
+
+// DefaultResponseCacheKey chave por tenant + path + query da requisição
+// Requisições com usuário (Authorization ou X-User-ID) não são cacheadas com a chave
+// padrão, pois o conteúdo pode variar por usuário; inclua o usuário num keyFn próprio
+func DefaultResponseCacheKey(c *gin.Context) string {
+	if c.GetHeader("Authorization") != "" || GetUserIDFromGin(c) != "" {
+		return ""
+	}
+	return ResponseCacheKey(GetTenantIDFromGin(c), c.Request.URL.Path, c.Request.URL.Query().Encode())
+}
+
+// Middleware serve respostas do cache e guarda respostas 200 de GETs
+// Deve rodar depois do middleware de tenant, para a chave padrão incluir o tenant
+func (rc *ResponseCache) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		key := rc.key(c)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		if data, found := rc.cache.Get(ctx, key); found {
+			var cached cachedResponse
+			if err := json.Unmarshal(data, &cached); err == nil {
+				c.Header(HeaderCacheStatus, "HIT")
+				c.Data(http.StatusOK, cached.ContentType, cached.Body)
+				c.Abort()
+				return
+			}
+		}
+
+		writer := &responseCaptureWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Header(HeaderCacheStatus, "MISS")
+
+		c.Next()
+
+		if writer.Status() != http.StatusOK || len(c.Errors) > 0 {
+			return
+		}
+
+		data, err := json.Marshal(cachedResponse{
+			ContentType: writer.Header().Get("Content-Type"),
+			Body:        writer.body.Bytes(),
+		})
+		if err == nil {
+			rc.cache.Set(ctx, key, data, rc.ttl)
+		}
+	}
+}
+
+// Invalidate remove uma resposta do cache (ex: após uma escrita que muda o relatório)
+//
+//	rc.Invalidate(ctx, zendia.ResponseCacheKey(tenantID, "/api/v1/reports/summary", ""))
+func (rc *ResponseCache) Invalidate(ctx context.Context, key string) error {
+	return rc.cache.Delete(ctx, key)
+}
+
+func (rc *ResponseCache) key(c *gin.Context) string {
+	if rc.keyFn != nil {
+		return rc.keyFn(c)
+	}
+	return DefaultResponseCacheKey(c)
+}
+
+// responseCaptureWriter repassa a resposta ao cliente guardando uma cópia do body
+type responseCaptureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseCaptureWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseCaptureWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
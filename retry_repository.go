@@ -0,0 +1,167 @@
+package zendia
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// EntityRepository operações comuns a Repository, CachedRepository, SQLRepository e decorators
+type EntityRepository[T any] interface {
+	Create(ctx context.Context, entity T) (T, error)
+	GetByID(ctx context.Context, id uuid.UUID) (T, error)
+	GetFirst(ctx context.Context, filters map[string]interface{}) (T, error)
+	Update(ctx context.Context, id uuid.UUID, entity T) (T, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetAll(ctx context.Context, filters map[string]interface{}, opts ...*QueryOptions) ([]T, error)
+	GetAllSkipTake(ctx context.Context, filters map[string]interface{}, pagination Pagination, opts ...*QueryOptions) ([]T, int64, error)
+	List(ctx context.Context, filters map[string]interface{}, opts ...*QueryOptions) ([]T, error)
+	Aggregate(ctx context.Context, pipeline []interface{}) ([]T, error)
+}
+
+// RetryConfig configuração de retry com backoff exponencial
+type RetryConfig struct {
+	MaxAttempts    int              // Total de tentativas, incluindo a primeira
+	InitialBackoff time.Duration    // Espera antes da segunda tentativa
+	MaxBackoff     time.Duration    // Teto da espera entre tentativas
+	RetryWrites    bool             // Também repete Create/Update/Delete (só se as escritas forem idempotentes)
+	IsRetryable    func(error) bool // Classificador de erros transitórios (padrão: IsRetryableError)
+}
+
+// DefaultRetryConfig configuração padrão: 3 tentativas, 50ms → 100ms, só leituras
+var DefaultRetryConfig = RetryConfig{
+	MaxAttempts:    3,
+	InitialBackoff: 50 * time.Millisecond,
+	MaxBackoff:     time.Second,
+	IsRetryable:    IsRetryableError,
+}
+
+// RetryRepository decorator que repete operações em erros transitórios do banco
+// (troca de primário, timeout, falha de rede). Escritas só são repetidas com RetryWrites.
+type RetryRepository[T any] struct {
+	base   EntityRepository[T]
+	config RetryConfig
+}
+
+// NewRetryRepository cria o decorator de retry; campos zerados usam DefaultRetryConfig
+//
+// Uso:
+//
+//	repo := zendia.NewRetryRepository[*User](zendia.NewRepository[*User](col), zendia.DefaultRetryConfig)
+func NewRetryRepository[T any](base EntityRepository[T], config RetryConfig) *RetryRepository[T] {
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultRetryConfig.MaxAttempts
+	}
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = DefaultRetryConfig.InitialBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = DefaultRetryConfig.MaxBackoff
+	}
+	if config.IsRetryable == nil {
+		config.IsRetryable = DefaultRetryConfig.IsRetryable
+	}
+
+	return &RetryRepository[T]{base: base, config: config}
+}
+
+func (rr *RetryRepository[T]) Create(ctx context.Context, entity T) (T, error) {
+	return retryWrite(rr, ctx, func() (T, error) { return rr.base.Create(ctx, entity) })
+}
+
+func (rr *RetryRepository[T]) GetByID(ctx context.Context, id uuid.UUID) (T, error) {
+	return retry(ctx, rr.config, func() (T, error) { return rr.base.GetByID(ctx, id) })
+}
+
+func (rr *RetryRepository[T]) GetFirst(ctx context.Context, filters map[string]interface{}) (T, error) {
+	return retry(ctx, rr.config, func() (T, error) { return rr.base.GetFirst(ctx, filters) })
+}
+
+func (rr *RetryRepository[T]) Update(ctx context.Context, id uuid.UUID, entity T) (T, error) {
+	return retryWrite(rr, ctx, func() (T, error) { return rr.base.Update(ctx, id, entity) })
+}
+
+func (rr *RetryRepository[T]) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := retryWrite(rr, ctx, func() (struct{}, error) { return struct{}{}, rr.base.Delete(ctx, id) })
+	return err
+}
+
+func (rr *RetryRepository[T]) GetAll(ctx context.Context, filters map[string]interface{}, opts ...*QueryOptions) ([]T, error) {
+	return retry(ctx, rr.config, func() ([]T, error) { return rr.base.GetAll(ctx, filters, opts...) })
+}
+
+func (rr *RetryRepository[T]) GetAllSkipTake(ctx context.Context, filters map[string]interface{}, pagination Pagination, opts ...*QueryOptions) ([]T, int64, error) {
+	var total int64
+	entities, err := retry(ctx, rr.config, func() ([]T, error) {
+		var entities []T
+		var err error
+		entities, total, err = rr.base.GetAllSkipTake(ctx, filters, pagination, opts...)
+		return entities, err
+	})
+	return entities, total, err
+}
+
+func (rr *RetryRepository[T]) List(ctx context.Context, filters map[string]interface{}, opts ...*QueryOptions) ([]T, error) {
+	return retry(ctx, rr.config, func() ([]T, error) { return rr.base.List(ctx, filters, opts...) })
+}
+
+func (rr *RetryRepository[T]) Aggregate(ctx context.Context, pipeline []interface{}) ([]T, error) {
+	return retry(ctx, rr.config, func() ([]T, error) { return rr.base.Aggregate(ctx, pipeline) })
+}
+
+// IsRetryableError identifica erros transitórios do MongoDB
+// Erros de repository (APIError) são desembrulhados até o erro original do driver
+func IsRetryableError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if mongo.IsTimeout(err) || mongo.IsNetworkError(err) {
+		return true
+	}
+
+	var labeled mongo.LabeledError
+	if errors.As(err, &labeled) {
+		return labeled.HasErrorLabel("RetryableWriteError") || labeled.HasErrorLabel("TransientTransactionError")
+	}
+
+	return false
+}
+
+// retryWrite repete escritas apenas quando RetryWrites está habilitado
+func retryWrite[T any, R any](rr *RetryRepository[T], ctx context.Context, fn func() (R, error)) (R, error) {
+	if !rr.config.RetryWrites {
+		return fn()
+	}
+	return retry(ctx, rr.config, fn)
+}
+
+// retry executa fn até MaxAttempts vezes com backoff exponencial
+// Cancelamento do ctx interrompe a espera entre tentativas
+func retry[R any](ctx context.Context, config RetryConfig, fn func() (R, error)) (R, error) {
+	backoff := config.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		result, err := fn()
+		if err == nil || attempt >= config.MaxAttempts || !config.IsRetryable(err) {
+			return result, err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			var zero R
+			return zero, ctx.Err()
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if backoff > config.MaxBackoff {
+			backoff = config.MaxBackoff
+		}
+	}
+}
@@ -1,6 +1,14 @@
 package zendia
 
-import "github.com/gin-gonic/gin"
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
 
 // RouteGroup representa um grupo de rotas
 type RouteGroup struct {
@@ -47,6 +55,82 @@ func (rg *RouteGroup) Use(middleware ...gin.HandlerFunc) {
 	rg.group.Use(middleware...)
 }
 
+// groupErrorHandlerKey chave do gin.Context com o ErrorHandler do grupo da rota
+const groupErrorHandlerKey = "zendia_group_error_handler"
+
+// SetErrorHandler define como os erros retornados pelos handlers do grupo são renderizados
+// Vale para o grupo e seus subgrupos (um subgrupo pode sobrescrever); rotas fora do grupo
+// continuam com o mapeamento padrão do Handle. Deve ser chamado antes de registrar as rotas.
+//
+//	public := app.Group("/public")
+//	public.SetErrorHandler(zendia.ErrorHandlerFunc(func(c *gin.Context, err error) { ... }))
+func (rg *RouteGroup) SetErrorHandler(handler ErrorHandler) {
+	rg.group.Use(func(c *gin.Context) {
+		c.Set(groupErrorHandlerKey, handler)
+		c.Next()
+	})
+}
+
+// CORS aplica uma configuração de CORS própria do grupo, com precedência sobre a global
+// Deve ser chamado antes de registrar as rotas do grupo. Registra OPTIONS /*path no
+// grupo para que o preflight seja respondido com a configuração do grupo.
+//
+//	admin := app.Group("/admin")
+//	admin.CORS(zendia.CORSConfig{AllowOrigins: []string{"https://admin.example.com"}})
+func (rg *RouteGroup) CORS(config CORSConfig) {
+	rg.zendia.corsGroups = append(rg.zendia.corsGroups, strings.TrimSuffix(rg.group.BasePath(), "/"))
+
+	handler := corsHandler(config)
+	rg.group.Use(handler)
+	rg.group.OPTIONS("/*path", func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusNoContent)
+	})
+}
+
+// Router interface comum entre Zendia e RouteGroup para registro de rotas
+type Router interface {
+	GET(relativePath string, handlers ...gin.HandlerFunc)
+	POST(relativePath string, handlers ...gin.HandlerFunc)
+	PUT(relativePath string, handlers ...gin.HandlerFunc)
+	DELETE(relativePath string, handlers ...gin.HandlerFunc)
+	PATCH(relativePath string, handlers ...gin.HandlerFunc)
+}
+
+// withMiddleware monta a cadeia middleware → handler tipado
+func withMiddleware[T any](handler Handler[T], middleware []gin.HandlerFunc) []gin.HandlerFunc {
+	handlers := make([]gin.HandlerFunc, 0, len(middleware)+1)
+	handlers = append(handlers, middleware...)
+	return append(handlers, Handle(handler))
+}
+
+// GetJSON registra uma rota GET tipada com middlewares próprios da rota
+// Os middlewares rodam antes do handler e podem abortar a requisição:
+//
+//	zendia.GetJSON(api, "/admin", handler, zendia.RequireRole("admin"))
+func GetJSON[T any](r Router, relativePath string, handler Handler[T], middleware ...gin.HandlerFunc) {
+	r.GET(relativePath, withMiddleware(handler, middleware)...)
+}
+
+// PostJSON registra uma rota POST tipada com middlewares próprios da rota
+func PostJSON[T any](r Router, relativePath string, handler Handler[T], middleware ...gin.HandlerFunc) {
+	r.POST(relativePath, withMiddleware(handler, middleware)...)
+}
+
+// PutJSON registra uma rota PUT tipada com middlewares próprios da rota
+func PutJSON[T any](r Router, relativePath string, handler Handler[T], middleware ...gin.HandlerFunc) {
+	r.PUT(relativePath, withMiddleware(handler, middleware)...)
+}
+
+// PatchJSON registra uma rota PATCH tipada com middlewares próprios da rota
+func PatchJSON[T any](r Router, relativePath string, handler Handler[T], middleware ...gin.HandlerFunc) {
+	r.PATCH(relativePath, withMiddleware(handler, middleware)...)
+}
+
+// DeleteJSON registra uma rota DELETE tipada com middlewares próprios da rota
+func DeleteJSON[T any](r Router, relativePath string, handler Handler[T], middleware ...gin.HandlerFunc) {
+	r.DELETE(relativePath, withMiddleware(handler, middleware)...)
+}
+
 // Handler é uma função genérica para manipular requisições
 type Handler[T any] func(*Context[T]) error
 
@@ -65,24 +149,93 @@ func Handle[T any](handler Handler[T]) gin.HandlerFunc {
 				return
 			}
 
+			if val, exists := c.Get(groupErrorHandlerKey); exists {
+				if groupHandler, ok := val.(ErrorHandler); ok && groupHandler != nil {
+					groupHandler.Handle(c, err)
+					return
+				}
+			}
+
 			if apiErr, ok := err.(*APIError); ok {
+				ctx.errorCode = apiErr.ErrorCode
 				switch apiErr.Type {
 				case BadRequestErrorType, ValidationErrorType:
 					ctx.BadRequestWithError(apiErr.Message, apiErr.Details)
 				case NotFoundErrorType:
 					ctx.NotFoundWithError(apiErr.Message, apiErr.Details)
 				case InternalErrorType:
-					ctx.InternalErrorWithError(apiErr.Message, apiErr.Details)
+					logInternalError(apiErr.Message, apiErr.Details)
+					ctx.internalFailure(apiErr.Message, apiErr.Details)
 				case ConflictErrorType:
 					ctx.ConflictWithError(apiErr.Message, apiErr.Details)
 				case UnauthorizedErrorType:
-					ctx.Unauthorized(apiErr.Message)
+					if apiErr.Code == http.StatusForbidden {
+						ctx.Forbidden(apiErr.Message)
+					} else {
+						ctx.Unauthorized(apiErr.Message)
+					}
+				case UnsupportedMediaTypeErrorType:
+					ctx.UnsupportedMediaType(apiErr.Message)
+				case ClientClosedRequestErrorType:
+					ctx.ClientClosedRequest(apiErr.Message)
+				case UpgradeRequiredErrorType:
+					ctx.UpgradeRequired(apiErr.Message)
+				case GatewayTimeoutErrorType:
+					logInternalError(apiErr.Message, apiErr.Details)
+					ctx.GatewayTimeout(apiErr.Message)
+				case TooManyRequestsErrorType:
+					ctx.TooManyRequests(apiErr.Message)
 				default:
-					ctx.InternalErrorWithError(apiErr.Message, apiErr.Details)
+					logInternalError(apiErr.Message, apiErr.Details)
+					ctx.internalFailure(apiErr.Message, apiErr.Details)
 				}
+			} else if errors.Is(err, context.Canceled) {
+				ctx.ClientClosedRequest(MsgRequestCanceled)
 			} else {
-				ctx.InternalErrorWithError("Internal server error", err)
+				logInternalError("Internal server error", err)
+				ctx.internalFailure("Internal server error", err)
 			}
 		}
 	}
 }
+
+// HandlerR handler que retorna o valor da resposta em vez de escrevê-la
+type HandlerR[TReq, TResp any] func(*Context[TReq]) (TResp, error)
+
+// HandleR converte um HandlerR para gin.HandlerFunc
+// O valor retornado é enviado com c.Success(message, resp) e o erro segue o mapeamento do Handle
+// message é opcional (padrão: MsgSuccess). Se o handler já escreveu a resposta, o valor é ignorado
+//
+// Uso:
+//
+//	api.GET("/users/:id", zendia.HandleR(func(c *zendia.Context[any]) (*User, error) {
+//	    id, err := c.ParamUUID("id")
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    return repo.GetByID(c.Request.Context(), id)
+//	}, "Usuário encontrado"))
+func HandleR[TReq, TResp any](handler HandlerR[TReq, TResp], message ...string) gin.HandlerFunc {
+	msg := MsgSuccess
+	if len(message) > 0 && message[0] != "" {
+		msg = message[0]
+	}
+
+	return Handle(func(c *Context[TReq]) error {
+		resp, err := handler(c)
+		if err != nil {
+			return err
+		}
+		if !c.Writer.Written() {
+			c.Success(msg, resp)
+		}
+		return nil
+	})
+}
+
+// logInternalError registra a causa de erros internos, que não é enviada ao cliente
+func logInternalError(message string, err error) {
+	if err != nil {
+		log.Printf("[ZENDIA] %s: %v", message, err)
+	}
+}
@@ -0,0 +1,260 @@
+package zendia
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// oneofValue separa os valores de oneof como o validator: entre aspas simples ou sem espaços
+var oneofValue = regexp.MustCompile(`'[^']*'|\S+`)
+
+// SchemaFor gera um JSON Schema a partir das tags json e validate de T
+//
+// As regras de validate suportadas são traduzidas para o equivalente do schema:
+// required → required, min/max → minLength/maxLength (strings), minItems/maxItems
+// (listas) ou minimum/maximum (números), email/uuid/url → format e oneof → enum.
+// Regras depois de dive valem para os itens da lista (ou valores do map).
+func SchemaFor[T any]() map[string]interface{} {
+	var zero T
+	schema := schemaForType(reflect.TypeOf(&zero).Elem(), map[reflect.Type]bool{})
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	return schema
+}
+
+// AddSchemaEndpoint adiciona endpoint GET /schemas/:type com os schemas informados
+//
+//	app.AddSchemaEndpoint(map[string]map[string]interface{}{
+//	    "user": zendia.SchemaFor[User](),
+//	})
+func (z *Zendia) AddSchemaEndpoint(schemas map[string]map[string]interface{}) {
+	z.GET(RouteSchemas+"/:type", Handle(func(c *Context[any]) error {
+		schema, ok := schemas[c.Param("type")]
+		if !ok {
+			return NewNotFoundError("Schema not found")
+		}
+		c.JSON(http.StatusOK, schema)
+		return nil
+	}))
+}
+
+// schemaForType converte um tipo Go em JSON Schema
+func schemaForType(t reflect.Type, visiting map[reflect.Type]bool) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case uuidType:
+		return map[string]interface{}{"type": "string", "format": "uuid"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": schemaForType(t.Elem(), visiting)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaForType(t.Elem(), visiting)}
+	case reflect.Struct:
+		// Tipos recursivos viram object simples para não entrar em loop
+		if visiting[t] {
+			return map[string]interface{}{"type": "object"}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+		return schemaForStruct(t, visiting)
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// schemaForStruct monta properties e required de uma struct
+func schemaForStruct(t reflect.Type, visiting map[reflect.Type]bool) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []string
+
+	var collect func(t reflect.Type)
+	collect = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				continue
+			}
+
+			// Campos embutidos sem nome JSON são achatados, como no encoding/json
+			if field.Anonymous && name == "" {
+				ft := field.Type
+				if ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if ft.Kind() == reflect.Struct {
+					collect(ft)
+					continue
+				}
+			}
+
+			if !field.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+
+			prop := schemaForType(field.Type, visiting)
+			if applyValidateRules(prop, field.Tag.Get("validate")) {
+				required = append(required, name)
+			}
+			properties[name] = prop
+		}
+	}
+	collect(t)
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// applyValidateRules traduz as regras de validate para o schema e indica se o campo é obrigatório
+func applyValidateRules(prop map[string]interface{}, tag string) bool {
+	if tag == "" || tag == "-" {
+		return false
+	}
+
+	required := false
+	kind, _ := prop["type"].(string)
+
+	rules := strings.Split(tag, ",")
+	for i, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "dive":
+			applyDiveRules(prop, rules[i+1:])
+			return required
+		case "required":
+			required = true
+		case "email":
+			prop["format"] = "email"
+		case "uuid", "uuid4":
+			prop["format"] = "uuid"
+		case "url", "uri":
+			prop["format"] = "uri"
+		case "oneof":
+			prop["enum"] = enumValues(kind, oneofValues(param))
+		case "min", "max", "len":
+			applyLengthRule(prop, kind, name, param)
+		case "gt", "gte", "lt", "lte":
+			if value, err := strconv.ParseFloat(param, 64); err == nil {
+				keys := map[string]string{
+					"gt":  "exclusiveMinimum",
+					"gte": "minimum",
+					"lt":  "exclusiveMaximum",
+					"lte": "maximum",
+				}
+				prop[keys[name]] = value
+			}
+		}
+	}
+
+	return required
+}
+
+// applyDiveRules aplica as regras seguintes ao dive no schema dos itens
+// O bloco keys...endkeys de maps valida as chaves, que o schema não descreve
+func applyDiveRules(prop map[string]interface{}, rules []string) {
+	if len(rules) > 0 && rules[0] == "keys" {
+		for i, rule := range rules {
+			if rule == "endkeys" {
+				rules = rules[i+1:]
+				break
+			}
+		}
+	}
+
+	items, ok := prop["items"].(map[string]interface{})
+	if !ok {
+		items, ok = prop["additionalProperties"].(map[string]interface{})
+	}
+	if !ok || len(rules) == 0 {
+		return
+	}
+	applyValidateRules(items, strings.Join(rules, ","))
+}
+
+// applyLengthRule aplica min/max/len conforme o tipo do campo
+func applyLengthRule(prop map[string]interface{}, kind, rule, param string) {
+	value, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	var minKey, maxKey string
+	switch kind {
+	case "string":
+		minKey, maxKey = "minLength", "maxLength"
+	case "array":
+		minKey, maxKey = "minItems", "maxItems"
+	case "object":
+		minKey, maxKey = "minProperties", "maxProperties"
+	case "integer", "number":
+		minKey, maxKey = "minimum", "maximum"
+	default:
+		return
+	}
+
+	switch rule {
+	case "min":
+		prop[minKey] = value
+	case "max":
+		prop[maxKey] = value
+	case "len":
+		prop[minKey] = value
+		prop[maxKey] = value
+	}
+}
+
+// enumValues converte os valores de oneof para o tipo do campo
+func enumValues(kind string, values []string) []interface{} {
+	enum := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		if kind == "integer" || kind == "number" {
+			if n, err := strconv.ParseFloat(v, 64); err == nil {
+				enum = append(enum, n)
+				continue
+			}
+		}
+		enum = append(enum, v)
+	}
+	return enum
+}
+
+// oneofValues separa os valores de oneof, removendo as aspas de valores com espaço
+func oneofValues(param string) []string {
+	values := oneofValue.FindAllString(param, -1)
+	for i, v := range values {
+		values[i] = strings.Replace(v, "'", "", -1)
+	}
+	return values
+}
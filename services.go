@@ -0,0 +1,104 @@
+package zendia
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// servicesKey chave do cache de serviços já resolvidos na requisição
+const servicesKey = "zendia_services"
+
+// ServiceFactory cria um serviço para a requisição (ex: repository com o tenant do contexto)
+type ServiceFactory func(c *gin.Context) interface{}
+
+// serviceRegistry factories registradas por chave (string em Provide, reflect.Type em ProvideService)
+type serviceRegistry struct {
+	mu        sync.RWMutex
+	factories map[interface{}]ServiceFactory
+}
+
+// Provide registra uma factory resolvida por chave com Context.Resolve
+// A factory roda no máximo uma vez por requisição; o resultado é reaproveitado até o fim dela
+//
+// Uso:
+//
+//	app.Provide("users", func(c *gin.Context) interface{} { return userRepo })
+//	repo := c.Resolve("users").(*zendia.Repository[*User])
+func (z *Zendia) Provide(key string, factory ServiceFactory) {
+	z.services.provide(key, factory)
+}
+
+// ProvideService registra uma factory tipada, resolvida pelo tipo com GetService
+//
+//	zendia.ProvideService(app, func(c *gin.Context) *UserService { return NewUserService(userRepo) })
+//	svc, ok := zendia.GetService[*UserService](c)
+func ProvideService[S any](z *Zendia, factory func(c *gin.Context) S) {
+	z.services.provide(serviceType[S](), func(c *gin.Context) interface{} {
+		return factory(c)
+	})
+}
+
+// Resolve retorna o serviço registrado com Provide (nil se não houver)
+func (c *Context[T]) Resolve(key string) interface{} {
+	return resolveService(c.Context, key)
+}
+
+// GetService retorna o serviço registrado com ProvideService para o tipo S
+// ok é false se nenhum serviço do tipo foi registrado
+func GetService[S any, T any](c *Context[T]) (S, bool) {
+	service, ok := resolveService(c.Context, serviceType[S]()).(S)
+	return service, ok
+}
+
+func (r *serviceRegistry) provide(key interface{}, factory ServiceFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.factories == nil {
+		r.factories = make(map[interface{}]ServiceFactory)
+	}
+	r.factories[key] = factory
+}
+
+func (r *serviceRegistry) factory(key interface{}) ServiceFactory {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.factories[key]
+}
+
+// resolveService busca o serviço no cache da requisição ou cria com a factory da instância Zendia
+func resolveService(c *gin.Context, key interface{}) interface{} {
+	resolved, _ := c.Get(servicesKey)
+	cache, _ := resolved.(map[interface{}]interface{})
+	if service, found := cache[key]; found {
+		return service
+	}
+
+	val, exists := c.Get("zendia_instance")
+	if !exists {
+		return nil
+	}
+	z, ok := val.(*Zendia)
+	if !ok {
+		return nil
+	}
+
+	factory := z.services.factory(key)
+	if factory == nil {
+		return nil
+	}
+
+	service := factory(c)
+	if cache == nil {
+		cache = make(map[interface{}]interface{})
+		c.Set(servicesKey, cache)
+	}
+	cache[key] = service
+	return service
+}
+
+// serviceType chave de registro de um serviço tipado
+func serviceType[S any]() reflect.Type {
+	return reflect.TypeOf((*S)(nil)).Elem()
+}
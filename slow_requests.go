@@ -0,0 +1,142 @@
+package zendia
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultSlowRequestsSize quantidade de requisições lentas mantidas em memória
+const DefaultSlowRequestsSize = 100
+
+// SlowRequest dados de uma requisição que passou do limite de latência
+type SlowRequest struct {
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Route      string    `json:"route"`
+	Status     int       `json:"status"`
+	DurationMs float64   `json:"duration_ms"`
+	TenantID   string    `json:"tenant_id,omitempty"`
+	UserID     string    `json:"user_id,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+// SlowRequestLog ring buffer com as últimas requisições lentas
+type SlowRequestLog struct {
+	mu      sync.RWMutex
+	entries []SlowRequest
+	next    int
+	full    bool
+}
+
+// NewSlowRequestLog cria o buffer mantendo as últimas size requisições
+func NewSlowRequestLog(size int) *SlowRequestLog {
+	if size <= 0 {
+		size = DefaultSlowRequestsSize
+	}
+	return &SlowRequestLog{entries: make([]SlowRequest, size)}
+}
+
+// Record guarda uma requisição sobrescrevendo a mais antiga quando o buffer enche
+func (l *SlowRequestLog) Record(req SlowRequest) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries[l.next] = req
+	l.next = (l.next + 1) % len(l.entries)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Recent retorna as requisições lentas da mais recente para a mais antiga
+func (l *SlowRequestLog) Recent() []SlowRequest {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	count := l.next
+	if l.full {
+		count = len(l.entries)
+	}
+
+	recent := make([]SlowRequest, 0, count)
+	for i := 1; i <= count; i++ {
+		idx := (l.next - i + len(l.entries)) % len(l.entries)
+		recent = append(recent, l.entries[idx])
+	}
+	return recent
+}
+
+// SlowRequestLogger middleware que loga requisições acima de threshold
+// Com um SlowRequestLog as requisições também são guardadas para consulta
+func SlowRequestLogger(threshold time.Duration, store ...*SlowRequestLog) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		duration := time.Since(start)
+		if duration < threshold {
+			return
+		}
+
+		req := SlowRequest{
+			Method:     c.Request.Method,
+			Path:       c.Request.URL.Path,
+			Route:      c.FullPath(),
+			Status:     c.Writer.Status(),
+			DurationMs: float64(duration) / float64(time.Millisecond),
+			TenantID:   GetTenantIDFromGin(c),
+			UserID:     GetUserIDFromGin(c),
+			At:         start,
+		}
+
+		log.Printf("🐢 Slow request: %s %s (%s) %d in %s tenant=%s user=%s",
+			req.Method, sanitizeLogValue(req.Path), req.Route, req.Status, duration,
+			sanitizeLogValue(req.TenantID), sanitizeLogValue(req.UserID))
+
+		for _, s := range store {
+			s.Record(req)
+		}
+	}
+}
+
+// RecentForTenant retorna apenas as requisições lentas do tenant, da mais recente para a mais antiga
+func (l *SlowRequestLog) RecentForTenant(tenantID string) []SlowRequest {
+	recent := l.Recent()
+	filtered := recent[:0]
+	for _, req := range recent {
+		if req.TenantID == tenantID {
+			filtered = append(filtered, req)
+		}
+	}
+	return filtered
+}
+
+// AddSlowRequestLogger adiciona o SlowRequestLogger e expõe GET /admin/slow-requests
+// O endpoint fica fora das rotas públicas: exige a role RoleAdmin e um tenant no
+// contexto, e só lista as requisições desse tenant
+func (z *Zendia) AddSlowRequestLogger(threshold time.Duration, size int) *SlowRequestLog {
+	store := NewSlowRequestLog(size)
+	z.Use(SlowRequestLogger(threshold, store))
+	z.features[FeatureSlowRequests] = true
+
+	z.GET(RouteSlowRequests, RequireRole(RoleAdmin), Handle(func(c *Context[any]) error {
+		tenantID := c.GetTenantID()
+		if tenantID == "" {
+			return NewUnauthorizedError(MsgLoginRequired)
+		}
+
+		recent := store.RecentForTenant(tenantID)
+		c.Success("Requisições lentas", map[string]interface{}{
+			"threshold_ms": float64(threshold) / float64(time.Millisecond),
+			"count":        len(recent),
+			"requests":     recent,
+		})
+		return nil
+	}))
+
+	return store
+}
@@ -0,0 +1,332 @@
+package zendia
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Colunas gerenciadas pelo SQLAuditRepository
+const (
+	SQLColumnTenantID      = "tenant_id"
+	SQLColumnActive        = "active"
+	SQLColumnCreatedAt     = "created_at"
+	SQLColumnCreatedBy     = "created_by"
+	SQLColumnCreatedByName = "created_by_name"
+	SQLColumnUpdatedAt     = "updated_at"
+	SQLColumnUpdatedBy     = "updated_by"
+	SQLColumnUpdatedByName = "updated_by_name"
+	SQLColumnDeletedAt     = "deleted_at"
+	SQLColumnDeletedBy     = "deleted_by"
+	SQLColumnDeletedByName = "deleted_by_name"
+)
+
+// sqlDeletedScope quais registros entram na query em relação ao soft delete
+type sqlDeletedScope int
+
+const (
+	sqlScopeActive sqlDeletedScope = iota
+	sqlScopeDeleted
+	sqlScopeAll
+)
+
+// SQLAuditRepository repository SQL com tenant, auditoria e soft delete
+//
+// Aplica a mesma semântica do Repository com WithAudit: toda query é filtrada pelo
+// tenant do contexto, as colunas created_*, updated_* e deleted_* são preenchidas a
+// partir do TenantInfo e o Delete apenas marca deleted_at. A coluna active é mantida
+// em sincronia quando mapeada na entidade.
+//
+// Tabela esperada (PostgreSQL):
+//
+//	tenant_id uuid, created_at timestamptz, created_by uuid, created_by_name text,
+//	updated_at timestamptz, updated_by uuid, updated_by_name text,
+//	deleted_at timestamptz NULL, deleted_by uuid NULL, deleted_by_name text NULL
+type SQLAuditRepository[T any, ID any] struct {
+	base      *SQLRepository[T, ID]
+	hasActive bool
+}
+
+// NewSQLAuditRepository cria um novo repository SQL com auditoria
+//
+// Uso:
+//
+//	repo := zendia.NewSQLAuditRepository[*User, uuid.UUID](db, "users")
+func NewSQLAuditRepository[T any, ID any](db SQLExecutor, table string, opts ...SQLRepositoryOption) *SQLAuditRepository[T, ID] {
+	base := NewSQLRepository[T, ID](db, table, opts...)
+	base.defaultOrder = SQLColumnCreatedAt
+	base.managed = map[string]bool{
+		SQLColumnTenantID:      true,
+		SQLColumnCreatedAt:     true,
+		SQLColumnCreatedBy:     true,
+		SQLColumnCreatedByName: true,
+		SQLColumnUpdatedAt:     true,
+		SQLColumnUpdatedBy:     true,
+		SQLColumnUpdatedByName: true,
+		SQLColumnDeletedAt:     true,
+		SQLColumnDeletedBy:     true,
+		SQLColumnDeletedByName: true,
+	}
+
+	_, hasActive := base.byName[SQLColumnActive]
+	if hasActive {
+		base.managed[SQLColumnActive] = true
+	}
+
+	return &SQLAuditRepository[T, ID]{base: base, hasActive: hasActive}
+}
+
+func (r *SQLAuditRepository[T, ID]) Create(ctx context.Context, entity T) (T, error) {
+	tenantInfo := GetTenantInfo(ctx)
+	tenantUUID, err := requireTenant(ctx)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	info := newAuditInfo(tenantInfo)
+	if te, ok := any(entity).(interface{ SetTenantID(string) }); ok {
+		te.SetTenantID(tenantInfo.TenantID)
+	}
+	if ae, ok := any(entity).(AuditableEntity); ok {
+		ae.SetCreated(info)
+		ae.SetUpdated(info)
+		ae.SetActive(true)
+	}
+
+	extra := []sqlAssignment{{SQLColumnTenantID, tenantUUID}}
+	extra = append(extra, auditAssignments("created", info)...)
+	extra = append(extra, auditAssignments("updated", info)...)
+	if r.hasActive {
+		extra = append(extra, sqlAssignment{SQLColumnActive, true})
+	}
+
+	return r.base.insert(ctx, entity, extra)
+}
+
+func (r *SQLAuditRepository[T, ID]) GetByID(ctx context.Context, id ID) (T, error) {
+	q := r.base.newQuery()
+	if err := r.scope(ctx, q, sqlScopeActive); err != nil {
+		var zero T
+		return zero, err
+	}
+	q.where = append(q.where, r.base.pk.name+" = "+q.arg(id))
+	return r.base.first(ctx, q)
+}
+
+func (r *SQLAuditRepository[T, ID]) GetFirst(ctx context.Context, filters map[string]interface{}) (T, error) {
+	q, err := r.query(ctx, sqlScopeActive, filters)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return r.base.first(ctx, q)
+}
+
+func (r *SQLAuditRepository[T, ID]) Update(ctx context.Context, id ID, entity T) (T, error) {
+	tenantInfo := GetTenantInfo(ctx)
+	if _, err := requireTenant(ctx); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	info := newAuditInfo(tenantInfo)
+	if ae, ok := any(entity).(AuditableEntity); ok {
+		ae.SetUpdated(info)
+	}
+
+	return r.base.update(ctx, id, entity, auditAssignments("updated", info), func(q *sqlQuery) error {
+		return r.scope(ctx, q, sqlScopeActive)
+	})
+}
+
+// Patch atualiza apenas as colunas informadas do registro do tenant, regravando updated_*
+func (r *SQLAuditRepository[T, ID]) Patch(ctx context.Context, id ID, fields map[string]interface{}) (T, error) {
+	tenantInfo := GetTenantInfo(ctx)
+	if _, err := requireTenant(ctx); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return r.base.patch(ctx, id, fields, auditAssignments("updated", newAuditInfo(tenantInfo)), func(q *sqlQuery) error {
+		return r.scope(ctx, q, sqlScopeActive)
+	})
+}
+
+// Delete soft delete: marca deleted_at/deleted_by sem remover a linha
+func (r *SQLAuditRepository[T, ID]) Delete(ctx context.Context, id ID) error {
+	extra := auditAssignments("deleted", newAuditInfo(GetTenantInfo(ctx)))
+	if r.hasActive {
+		extra = append(extra, sqlAssignment{SQLColumnActive, false})
+	}
+
+	return r.setColumns(ctx, id, extra, sqlScopeActive, "Failed to delete entity")
+}
+
+// HardDelete remove permanentemente do banco
+func (r *SQLAuditRepository[T, ID]) HardDelete(ctx context.Context, id ID) error {
+	q := r.base.newQuery()
+	q.where = append(q.where, r.base.pk.name+" = "+q.arg(id))
+	if err := r.scope(ctx, q, sqlScopeAll); err != nil {
+		return err
+	}
+
+	return r.base.execOne(ctx, "DELETE FROM "+r.base.table+q.whereClause(), q.args, "Failed to hard delete entity")
+}
+
+// Restore restaura um registro soft deleted
+func (r *SQLAuditRepository[T, ID]) Restore(ctx context.Context, id ID) error {
+	extra := []sqlAssignment{
+		{SQLColumnDeletedAt, nil},
+		{SQLColumnDeletedBy, nil},
+		{SQLColumnDeletedByName, nil},
+	}
+	if r.hasActive {
+		extra = append(extra, sqlAssignment{SQLColumnActive, true})
+	}
+
+	err := r.setColumns(ctx, id, extra, sqlScopeDeleted, "Failed to restore entity")
+	if apiErr, ok := err.(*APIError); ok && apiErr.Type == NotFoundErrorType {
+		return NewNotFoundError("Entity not found or not deleted")
+	}
+	return err
+}
+
+func (r *SQLAuditRepository[T, ID]) GetAll(ctx context.Context, filters map[string]interface{}, opts ...*QueryOptions) ([]T, error) {
+	q, err := r.query(ctx, sqlScopeActive, filters)
+	if err != nil {
+		return nil, err
+	}
+	return r.base.selectAll(ctx, q, opts...)
+}
+
+func (r *SQLAuditRepository[T, ID]) GetAllSkipTake(ctx context.Context, filters map[string]interface{}, pagination Pagination, opts ...*QueryOptions) ([]T, int64, error) {
+	q, err := r.query(ctx, sqlScopeActive, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+	return r.base.skipTake(ctx, q, pagination, opts...)
+}
+
+func (r *SQLAuditRepository[T, ID]) List(ctx context.Context, filters map[string]interface{}, opts ...*QueryOptions) ([]T, error) {
+	return r.GetAll(ctx, filters, opts...)
+}
+
+// GetAllIncludingDeleted busca todos os registros incluindo os deletados
+func (r *SQLAuditRepository[T, ID]) GetAllIncludingDeleted(ctx context.Context, filters map[string]interface{}) ([]T, error) {
+	q, err := r.query(ctx, sqlScopeAll, filters)
+	if err != nil {
+		return nil, err
+	}
+	return r.base.selectAll(ctx, q)
+}
+
+// GetDeleted busca apenas registros deletados (deleted_at preenchido)
+func (r *SQLAuditRepository[T, ID]) GetDeleted(ctx context.Context, filters map[string]interface{}) ([]T, error) {
+	q, err := r.query(ctx, sqlScopeDeleted, filters)
+	if err != nil {
+		return nil, err
+	}
+	return r.base.selectAll(ctx, q)
+}
+
+func (r *SQLAuditRepository[T, ID]) Count(ctx context.Context, filters map[string]interface{}) (int64, error) {
+	q, err := r.query(ctx, sqlScopeActive, filters)
+	if err != nil {
+		return 0, err
+	}
+	return r.base.count(ctx, q)
+}
+
+// CountAll conta todos os registros do tenant incluindo deletados
+func (r *SQLAuditRepository[T, ID]) CountAll(ctx context.Context, filters map[string]interface{}) (int64, error) {
+	q, err := r.query(ctx, sqlScopeAll, filters)
+	if err != nil {
+		return 0, err
+	}
+	return r.base.count(ctx, q)
+}
+
+func (r *SQLAuditRepository[T, ID]) ExistsBy(ctx context.Context, filters map[string]interface{}) (bool, error) {
+	count, err := r.Count(ctx, filters)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Aggregate não é suportado em SQL; use o *sql.DB diretamente para queries analíticas
+func (r *SQLAuditRepository[T, ID]) Aggregate(ctx context.Context, pipeline []interface{}) ([]T, error) {
+	return r.base.Aggregate(ctx, pipeline)
+}
+
+// --- helpers ---
+
+// query monta a query com escopo de tenant/soft delete e os filtros do usuário
+func (r *SQLAuditRepository[T, ID]) query(ctx context.Context, deleted sqlDeletedScope, filters map[string]interface{}) (*sqlQuery, error) {
+	q := r.base.newQuery()
+	if err := r.scope(ctx, q, deleted); err != nil {
+		return nil, err
+	}
+	if err := r.base.applyFilters(q, filters); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// scope adiciona o tenant do contexto e o filtro de soft delete
+// Tenant ausente ou inválido falha fechado: nunca executa a query sem o filtro
+func (r *SQLAuditRepository[T, ID]) scope(ctx context.Context, q *sqlQuery, deleted sqlDeletedScope) error {
+	tenantUUID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+	q.where = append(q.where, SQLColumnTenantID+" = "+q.arg(tenantUUID))
+
+	switch deleted {
+	case sqlScopeActive:
+		q.where = append(q.where, SQLColumnDeletedAt+" IS NULL")
+	case sqlScopeDeleted:
+		q.where = append(q.where, SQLColumnDeletedAt+" IS NOT NULL")
+	}
+
+	return nil
+}
+
+// setColumns atualiza apenas colunas gerenciadas de um registro do tenant
+func (r *SQLAuditRepository[T, ID]) setColumns(ctx context.Context, id ID, extra []sqlAssignment, deleted sqlDeletedScope, failMsg string) error {
+	q := r.base.newQuery()
+
+	sets := ""
+	for i, a := range extra {
+		if i > 0 {
+			sets += ", "
+		}
+		sets += a.column + " = " + q.arg(a.value)
+	}
+
+	q.where = append(q.where, r.base.pk.name+" = "+q.arg(id))
+	if err := r.scope(ctx, q, deleted); err != nil {
+		return err
+	}
+
+	query := "UPDATE " + r.base.table + " SET " + sets + q.whereClause()
+	return r.base.execOne(ctx, query, q.args, failMsg)
+}
+
+// auditAssignments converte o AuditInfo nas colunas <prefix>_at, <prefix>_by e <prefix>_by_name
+func auditAssignments(prefix string, info AuditInfo) []sqlAssignment {
+	return []sqlAssignment{
+		{prefix + "_at", info.SetAt},
+		{prefix + "_by", nullUUID(info.ByID)},
+		{prefix + "_by_name", info.ByName},
+	}
+}
+
+// nullUUID grava NULL em vez de uuid.Nil
+func nullUUID(id uuid.UUID) interface{} {
+	if id == uuid.Nil {
+		return nil
+	}
+	return id
+}
@@ -0,0 +1,702 @@
+package zendia
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SQLExecutor abstrai *sql.DB, *sql.Conn e *sql.Tx
+type SQLExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// SQLPlaceholder estilo de placeholder dos parâmetros da query
+type SQLPlaceholder int
+
+const (
+	SQLPlaceholderDollar   SQLPlaceholder = iota // $1, $2... (PostgreSQL)
+	SQLPlaceholderQuestion                       // ?, ?... (MySQL, SQLite)
+)
+
+// SQLRepositoryConfig configuração do repository SQL
+type SQLRepositoryConfig struct {
+	placeholder SQLPlaceholder
+	primaryKey  string
+}
+
+// SQLRepositoryOption função para configurar o repository SQL
+type SQLRepositoryOption func(*SQLRepositoryConfig)
+
+// WithPlaceholder define o estilo de placeholder (padrão: $1 do PostgreSQL)
+func WithPlaceholder(placeholder SQLPlaceholder) SQLRepositoryOption {
+	return func(c *SQLRepositoryConfig) {
+		c.placeholder = placeholder
+	}
+}
+
+// WithPrimaryKey define a coluna da chave primária (padrão: id)
+func WithPrimaryKey(column string) SQLRepositoryOption {
+	return func(c *SQLRepositoryConfig) {
+		c.primaryKey = column
+	}
+}
+
+var auditInfoType = reflect.TypeOf(AuditInfo{})
+
+// sqlColumn coluna mapeada para um campo da struct
+type sqlColumn struct {
+	name     string
+	index    []int
+	nullable bool
+}
+
+// sqlAssignment valor de uma coluna gerenciada pelo repository (ex: auditoria)
+type sqlAssignment struct {
+	column string
+	value  interface{}
+}
+
+// SQLRepository implementação do repository sobre database/sql
+//
+// As colunas vêm da tag db dos campos (ou do nome do campo em minúsculo) e
+// formam a allowlist dos filtros: chaves fora dela retornam BadRequest.
+// Campos AuditInfo com db:"created" viram as colunas created_at, created_by e created_by_name.
+type SQLRepository[T any, ID any] struct {
+	db           SQLExecutor
+	table        string
+	config       SQLRepositoryConfig
+	columns      []sqlColumn
+	byName       map[string]sqlColumn
+	pk           sqlColumn
+	elemType     reflect.Type
+	isPtr        bool
+	managed      map[string]bool // colunas escritas pelo repository, nunca a partir da entidade
+	defaultOrder string
+}
+
+// NewSQLRepository cria um novo repository SQL
+//
+// Uso:
+//
+//	repo := zendia.NewSQLRepository[*User, uuid.UUID](db, "users")
+//	repo := zendia.NewSQLRepository[*User, int64](db, "users", zendia.WithPlaceholder(zendia.SQLPlaceholderQuestion))
+func NewSQLRepository[T any, ID any](db SQLExecutor, table string, opts ...SQLRepositoryOption) *SQLRepository[T, ID] {
+	cfg := SQLRepositoryConfig{primaryKey: "id"}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	isPtr := t.Kind() == reflect.Ptr
+	if isPtr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		panic("zendia: SQLRepository requires a struct entity, got " + t.String())
+	}
+
+	repo := &SQLRepository[T, ID]{
+		db:       db,
+		table:    table,
+		config:   cfg,
+		byName:   map[string]sqlColumn{},
+		elemType: t,
+		isPtr:    isPtr,
+	}
+	repo.collectColumns(t, nil)
+
+	pk, ok := repo.byName[cfg.primaryKey]
+	if !ok {
+		panic("zendia: primary key column " + cfg.primaryKey + " not found in " + t.String())
+	}
+	repo.pk = pk
+	repo.defaultOrder = pk.name
+
+	return repo
+}
+
+func (r *SQLRepository[T, ID]) Create(ctx context.Context, entity T) (T, error) {
+	return r.insert(ctx, entity, nil)
+}
+
+// insert grava as colunas da entidade mais as colunas gerenciadas informadas
+func (r *SQLRepository[T, ID]) insert(ctx context.Context, entity T, extra []sqlAssignment) (T, error) {
+	v := r.structValue(entity)
+	pkField := v.FieldByIndex(r.pk.index)
+
+	if pkField.Type() == uuidType && pkField.IsZero() {
+		pkField.Set(reflect.ValueOf(uuid.New()))
+	}
+
+	// Chave zerada fica a cargo do banco (serial/autoincrement)
+	generated := pkField.IsZero()
+
+	q := r.newQuery()
+	var columns, values []string
+	for _, col := range r.columns {
+		if (generated && col.name == r.pk.name) || r.managed[col.name] {
+			continue
+		}
+		columns = append(columns, col.name)
+		values = append(values, q.arg(v.FieldByIndex(col.index).Interface()))
+	}
+	for _, a := range extra {
+		columns = append(columns, a.column)
+		values = append(values, q.arg(a.value))
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", r.table, strings.Join(columns, ", "), strings.Join(values, ", "))
+
+	if generated && r.config.placeholder == SQLPlaceholderDollar {
+		query += " RETURNING " + r.pk.name
+		if err := r.db.QueryRowContext(ctx, query, q.args...).Scan(pkField.Addr().Interface()); err != nil {
+			var zero T
+			return zero, internalError("Failed to create entity", err)
+		}
+		return r.entityFrom(v), nil
+	}
+
+	result, err := r.db.ExecContext(ctx, query, q.args...)
+	if err != nil {
+		var zero T
+		return zero, internalError("Failed to create entity", err)
+	}
+
+	if generated {
+		if id, err := result.LastInsertId(); err == nil {
+			setIntField(pkField, id)
+		}
+	}
+
+	return r.entityFrom(v), nil
+}
+
+func (r *SQLRepository[T, ID]) GetByID(ctx context.Context, id ID) (T, error) {
+	q := r.newQuery()
+	q.where = append(q.where, r.pk.name+" = "+q.arg(id))
+	return r.first(ctx, q)
+}
+
+func (r *SQLRepository[T, ID]) GetFirst(ctx context.Context, filters map[string]interface{}) (T, error) {
+	q := r.newQuery()
+	if err := r.applyFilters(q, filters); err != nil {
+		var zero T
+		return zero, err
+	}
+	return r.first(ctx, q)
+}
+
+func (r *SQLRepository[T, ID]) Update(ctx context.Context, id ID, entity T) (T, error) {
+	return r.update(ctx, id, entity, nil, nil)
+}
+
+// update grava as colunas da entidade mais as gerenciadas; scope adiciona condições ao WHERE
+func (r *SQLRepository[T, ID]) update(ctx context.Context, id ID, entity T, extra []sqlAssignment, scope func(q *sqlQuery) error) (T, error) {
+	v := r.structValue(entity)
+
+	q := r.newQuery()
+	var sets []string
+	for _, col := range r.columns {
+		if col.name == r.pk.name || r.managed[col.name] {
+			continue
+		}
+		sets = append(sets, col.name+" = "+q.arg(v.FieldByIndex(col.index).Interface()))
+	}
+	for _, a := range extra {
+		sets = append(sets, a.column+" = "+q.arg(a.value))
+	}
+	q.where = append(q.where, r.pk.name+" = "+q.arg(id))
+
+	if scope != nil {
+		if err := scope(q); err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s%s", r.table, strings.Join(sets, ", "), q.whereClause())
+	if err := r.execOne(ctx, query, q.args, "Failed to update entity"); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	if idValue := reflect.ValueOf(id); idValue.Type().AssignableTo(v.FieldByIndex(r.pk.index).Type()) {
+		v.FieldByIndex(r.pk.index).Set(idValue)
+	}
+
+	return r.entityFrom(v), nil
+}
+
+// Patch atualiza apenas as colunas informadas, preservando as demais
+// Colunas desconhecidas, a chave primária e colunas gerenciadas são rejeitadas
+func (r *SQLRepository[T, ID]) Patch(ctx context.Context, id ID, fields map[string]interface{}) (T, error) {
+	return r.patch(ctx, id, fields, nil, nil)
+}
+
+// patch grava só as colunas de fields mais as gerenciadas e relê o registro
+// scope adiciona as mesmas condições ao UPDATE e à releitura
+func (r *SQLRepository[T, ID]) patch(ctx context.Context, id ID, fields map[string]interface{}, extra []sqlAssignment, scope func(q *sqlQuery) error) (T, error) {
+	var zero T
+	if len(fields) == 0 {
+		return zero, NewBadRequestError(MsgEmptyPatch)
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	q := r.newQuery()
+	sets := make([]string, 0, len(keys)+len(extra))
+	for _, key := range keys {
+		if _, ok := r.byName[key]; !ok || key == r.pk.name || r.managed[key] {
+			return zero, NewBadRequestError(MsgInvalidPatchField + ": " + key)
+		}
+		sets = append(sets, key+" = "+q.arg(fields[key]))
+	}
+	for _, a := range extra {
+		sets = append(sets, a.column+" = "+q.arg(a.value))
+	}
+	q.where = append(q.where, r.pk.name+" = "+q.arg(id))
+
+	if scope != nil {
+		if err := scope(q); err != nil {
+			return zero, err
+		}
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s%s", r.table, strings.Join(sets, ", "), q.whereClause())
+	if err := r.execOne(ctx, query, q.args, "Failed to patch entity"); err != nil {
+		return zero, err
+	}
+
+	read := r.newQuery()
+	if scope != nil {
+		if err := scope(read); err != nil {
+			return zero, err
+		}
+	}
+	read.where = append(read.where, r.pk.name+" = "+read.arg(id))
+	return r.first(ctx, read)
+}
+
+func (r *SQLRepository[T, ID]) Delete(ctx context.Context, id ID) error {
+	q := r.newQuery()
+	q.where = append(q.where, r.pk.name+" = "+q.arg(id))
+
+	query := "DELETE FROM " + r.table + q.whereClause()
+	return r.execOne(ctx, query, q.args, "Failed to delete entity")
+}
+
+func (r *SQLRepository[T, ID]) GetAll(ctx context.Context, filters map[string]interface{}, opts ...*QueryOptions) ([]T, error) {
+	q := r.newQuery()
+	if err := r.applyFilters(q, filters); err != nil {
+		return nil, err
+	}
+	return r.selectAll(ctx, q, opts...)
+}
+
+func (r *SQLRepository[T, ID]) GetAllSkipTake(ctx context.Context, filters map[string]interface{}, pagination Pagination, opts ...*QueryOptions) ([]T, int64, error) {
+	q := r.newQuery()
+	if err := r.applyFilters(q, filters); err != nil {
+		return nil, 0, err
+	}
+	return r.skipTake(ctx, q, pagination, opts...)
+}
+
+// skipTake conta o total e busca a página com os mesmos filtros
+func (r *SQLRepository[T, ID]) skipTake(ctx context.Context, q *sqlQuery, pagination Pagination, opts ...*QueryOptions) ([]T, int64, error) {
+	pagination = ResolvePaginationContext(ctx, pagination)
+
+	count, err := r.count(ctx, q)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	qo := &QueryOptions{}
+	if len(opts) > 0 && opts[0] != nil {
+		*qo = *opts[0]
+	}
+	qo.Skip = int64(pagination.Skip)
+	qo.Limit = int64(pagination.Take)
+
+	entities, err := r.selectAll(ctx, q, qo)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entities, count, nil
+}
+
+func (r *SQLRepository[T, ID]) List(ctx context.Context, filters map[string]interface{}, opts ...*QueryOptions) ([]T, error) {
+	return r.GetAll(ctx, filters, opts...)
+}
+
+func (r *SQLRepository[T, ID]) Count(ctx context.Context, filters map[string]interface{}) (int64, error) {
+	q := r.newQuery()
+	if err := r.applyFilters(q, filters); err != nil {
+		return 0, err
+	}
+	return r.count(ctx, q)
+}
+
+func (r *SQLRepository[T, ID]) ExistsBy(ctx context.Context, filters map[string]interface{}) (bool, error) {
+	count, err := r.Count(ctx, filters)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Aggregate não é suportado em SQL; use o *sql.DB diretamente para queries analíticas
+func (r *SQLRepository[T, ID]) Aggregate(ctx context.Context, pipeline []interface{}) ([]T, error) {
+	return nil, NewBadRequestError(MsgUnsupportedOperation + ": Aggregate")
+}
+
+// first executa a query com LIMIT 1
+func (r *SQLRepository[T, ID]) first(ctx context.Context, q *sqlQuery) (T, error) {
+	var zero T
+
+	query := r.selectClause() + q.whereClause() + " LIMIT 1"
+	row := r.db.QueryRowContext(ctx, query, q.args...)
+
+	entity, dest := r.newEntity()
+	if err := row.Scan(dest...); err != nil {
+		if err == sql.ErrNoRows {
+			return zero, NewNotFoundError("Entity not found")
+		}
+		return zero, internalError("Failed to get entity", err)
+	}
+
+	return entity, nil
+}
+
+// selectAll executa a query aplicando ordenação, limit e offset
+func (r *SQLRepository[T, ID]) selectAll(ctx context.Context, q *sqlQuery, opts ...*QueryOptions) ([]T, error) {
+	tail, err := r.queryOptionsClause(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, r.selectClause()+q.whereClause()+tail, q.args...)
+	if err != nil {
+		return nil, internalError("Failed to get entities", err)
+	}
+	defer rows.Close()
+
+	var entities []T
+	for rows.Next() {
+		entity, dest := r.newEntity()
+		if err := rows.Scan(dest...); err != nil {
+			return nil, internalError("Failed to decode entities", err)
+		}
+		entities = append(entities, entity)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, internalError("Failed to decode entities", err)
+	}
+
+	return entities, nil
+}
+
+// count executa SELECT COUNT(*) com os mesmos filtros
+func (r *SQLRepository[T, ID]) count(ctx context.Context, q *sqlQuery) (int64, error) {
+	var count int64
+	query := "SELECT COUNT(*) FROM " + r.table + q.whereClause()
+	if err := r.db.QueryRowContext(ctx, query, q.args...).Scan(&count); err != nil {
+		return 0, internalError("Failed to count entities", err)
+	}
+	return count, nil
+}
+
+// execOne executa a query e retorna NotFound quando nenhuma linha foi afetada
+func (r *SQLRepository[T, ID]) execOne(ctx context.Context, query string, args []interface{}, failMsg string) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return internalError(failMsg, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return internalError(failMsg, err)
+	}
+	if affected == 0 {
+		return NewNotFoundError("Entity not found")
+	}
+
+	return nil
+}
+
+// applyFilters traduz o map de filtros para condições parametrizadas
+// Só aceita colunas mapeadas na entidade; valores nunca são interpolados na query
+func (r *SQLRepository[T, ID]) applyFilters(q *sqlQuery, filters map[string]interface{}) error {
+	keys := make([]string, 0, len(filters))
+	for k := range filters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !r.isColumn(key) {
+			return NewBadRequestError(MsgInvalidFilterField + ": " + key)
+		}
+		q.where = append(q.where, q.condition(key, filters[key]))
+	}
+
+	return nil
+}
+
+// queryOptionsClause monta ORDER BY, LIMIT e OFFSET a partir das QueryOptions
+// Projection é ignorada: o scan sempre lê todas as colunas da entidade
+func (r *SQLRepository[T, ID]) queryOptionsClause(opts ...*QueryOptions) (string, error) {
+	order := Order{By: r.defaultOrder}
+	var limit, skip int64
+
+	if len(opts) > 0 && opts[0] != nil {
+		qo := opts[0]
+		if qo.Order.By != "" {
+			order = qo.Order
+		}
+		limit, skip = qo.Limit, qo.Skip
+	}
+	order = ResolveOrder(order)
+
+	if !r.isColumn(order.By) {
+		return "", NewBadRequestError(MsgInvalidFilterField + ": " + order.By)
+	}
+
+	direction := "DESC"
+	if order.At == 1 {
+		direction = "ASC"
+	}
+
+	clause := " ORDER BY " + order.By + " " + direction
+	if limit > 0 {
+		clause += " LIMIT " + strconv.FormatInt(limit, 10)
+	}
+	if skip > 0 {
+		clause += " OFFSET " + strconv.FormatInt(skip, 10)
+	}
+
+	return clause, nil
+}
+
+// isColumn verifica se o nome está na allowlist (colunas da entidade ou gerenciadas)
+func (r *SQLRepository[T, ID]) isColumn(name string) bool {
+	_, ok := r.byName[name]
+	return ok || r.managed[name]
+}
+
+func (r *SQLRepository[T, ID]) selectClause() string {
+	names := make([]string, len(r.columns))
+	for i, col := range r.columns {
+		names[i] = col.name
+	}
+	return "SELECT " + strings.Join(names, ", ") + " FROM " + r.table
+}
+
+func (r *SQLRepository[T, ID]) newQuery() *sqlQuery {
+	return &sqlQuery{placeholder: r.config.placeholder}
+}
+
+// newEntity cria uma entidade vazia e os ponteiros de scan na ordem das colunas
+func (r *SQLRepository[T, ID]) newEntity() (T, []interface{}) {
+	ptr := reflect.New(r.elemType)
+
+	dest := make([]interface{}, len(r.columns))
+	for i, col := range r.columns {
+		dest[i] = ptr.Elem().FieldByIndex(col.index).Addr().Interface()
+		if col.nullable {
+			dest[i] = nullableDest(dest[i])
+		}
+	}
+
+	if r.isPtr {
+		return ptr.Interface().(T), dest
+	}
+	return ptr.Elem().Interface().(T), dest
+}
+
+// structValue retorna o valor endereçável da struct da entidade
+func (r *SQLRepository[T, ID]) structValue(entity T) reflect.Value {
+	if r.isPtr {
+		return reflect.ValueOf(entity).Elem()
+	}
+	v := reflect.New(r.elemType).Elem()
+	v.Set(reflect.ValueOf(entity))
+	return v
+}
+
+// entityFrom converte o valor da struct de volta para T
+func (r *SQLRepository[T, ID]) entityFrom(v reflect.Value) T {
+	if r.isPtr {
+		return v.Addr().Interface().(T)
+	}
+	return v.Interface().(T)
+}
+
+// collectColumns mapeia os campos exportados, achatando structs embutidas
+func (r *SQLRepository[T, ID]) collectColumns(t reflect.Type, parent []int) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		index := append(append([]int{}, parent...), i)
+
+		name := strings.SplitN(field.Tag.Get("db"), ",", 2)[0]
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			r.collectColumns(field.Type, index)
+			continue
+		}
+
+		if field.Type == auditInfoType && name != "" {
+			r.collectAuditColumns(name, index)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		col := sqlColumn{name: name, index: index}
+		r.columns = append(r.columns, col)
+		r.byName[name] = col
+	}
+}
+
+// collectAuditColumns mapeia um AuditInfo para <prefix>_at, <prefix>_by e <prefix>_by_name
+// As colunas aceitam NULL (ex: deleted_* de registros ativos)
+func (r *SQLRepository[T, ID]) collectAuditColumns(prefix string, parent []int) {
+	fields := [][2]string{
+		{"_at", "SetAt"},
+		{"_by", "ByID"},
+		{"_by_name", "ByName"},
+	}
+	for _, f := range fields {
+		sub, _ := auditInfoType.FieldByName(f[1])
+		col := sqlColumn{
+			name:     prefix + f[0],
+			index:    append(append([]int{}, parent...), sub.Index...),
+			nullable: true,
+		}
+		r.columns = append(r.columns, col)
+		r.byName[col.name] = col
+	}
+}
+
+// sqlQuery acumula condições e argumentos com placeholders numerados
+type sqlQuery struct {
+	placeholder SQLPlaceholder
+	where       []string
+	args        []interface{}
+}
+
+// arg registra o argumento e retorna o placeholder correspondente
+func (q *sqlQuery) arg(value interface{}) string {
+	q.args = append(q.args, value)
+	if q.placeholder == SQLPlaceholderQuestion {
+		return "?"
+	}
+	return "$" + strconv.Itoa(len(q.args))
+}
+
+// condition monta a condição da coluna: nil vira IS NULL e listas viram IN
+func (q *sqlQuery) condition(column string, value interface{}) string {
+	if value == nil {
+		return column + " IS NULL"
+	}
+
+	v := reflect.ValueOf(value)
+	if (v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8) || (v.Kind() == reflect.Array && v.Type() != uuidType) {
+		if v.Len() == 0 {
+			return "1 = 0"
+		}
+		placeholders := make([]string, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			placeholders[i] = q.arg(v.Index(i).Interface())
+		}
+		return column + " IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	return column + " = " + q.arg(value)
+}
+
+func (q *sqlQuery) whereClause() string {
+	if len(q.where) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(q.where, " AND ")
+}
+
+// setIntField atribui o ID gerado pelo banco quando o campo é inteiro
+func setIntField(field reflect.Value, id int64) {
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		field.SetInt(id)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		field.SetUint(uint64(id))
+	}
+}
+
+// nullableDest envolve o destino do scan para aceitar NULL como valor zero
+func nullableDest(dest interface{}) interface{} {
+	switch d := dest.(type) {
+	case *time.Time:
+		return &nullTimeDest{d}
+	case *uuid.UUID:
+		return &nullUUIDDest{d}
+	case *string:
+		return &nullStringDest{d}
+	}
+	return dest
+}
+
+type nullTimeDest struct{ dest *time.Time }
+
+func (n *nullTimeDest) Scan(src interface{}) error {
+	var v sql.NullTime
+	if err := v.Scan(src); err != nil {
+		return err
+	}
+	*n.dest = v.Time
+	return nil
+}
+
+type nullUUIDDest struct{ dest *uuid.UUID }
+
+func (n *nullUUIDDest) Scan(src interface{}) error {
+	var v uuid.NullUUID
+	if err := v.Scan(src); err != nil {
+		return err
+	}
+	*n.dest = v.UUID
+	return nil
+}
+
+type nullStringDest struct{ dest *string }
+
+func (n *nullStringDest) Scan(src interface{}) error {
+	var v sql.NullString
+	if err := v.Scan(src); err != nil {
+		return err
+	}
+	*n.dest = v.String
+	return nil
+}
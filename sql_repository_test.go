@@ -0,0 +1,393 @@
+package zendia
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type sqlTestEntity struct {
+	ID    uuid.UUID `db:"id"`
+	Name  string    `db:"name"`
+	Email string    `db:"email"`
+	Notes string    `db:"-"`
+}
+
+func newSQLTestRepo(t *testing.T) (*SQLRepository[*sqlTestEntity, uuid.UUID], sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return NewSQLRepository[*sqlTestEntity, uuid.UUID](db, "users"), mock
+}
+
+func TestSQLRepository_CRUD(t *testing.T) {
+	repo, mock := newSQLTestRepo(t)
+	ctx := context.Background()
+
+	mock.ExpectExec("INSERT INTO users (id, name, email) VALUES ($1, $2, $3)").
+		WithArgs(sqlmock.AnyArg(), "Ana", "ana@test.com").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	created, err := repo.Create(ctx, &sqlTestEntity{Name: "Ana", Email: "ana@test.com"})
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, created.ID)
+
+	mock.ExpectQuery("SELECT id, name, email FROM users WHERE id = $1 LIMIT 1").
+		WithArgs(created.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(created.ID, "Ana", "ana@test.com"))
+
+	found, err := repo.GetByID(ctx, created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Ana", found.Name)
+
+	mock.ExpectExec("UPDATE users SET name = $1, email = $2 WHERE id = $3").
+		WithArgs("Bia", "ana@test.com", created.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err = repo.Update(ctx, created.ID, &sqlTestEntity{Name: "Bia", Email: "ana@test.com"})
+	assert.Equal(t, NotFoundErrorType, err.(*APIError).Type)
+
+	mock.ExpectExec("DELETE FROM users WHERE id = $1").
+		WithArgs(created.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.Delete(ctx, created.ID))
+
+	mock.ExpectQuery("SELECT id, name, email FROM users WHERE id = $1 LIMIT 1").
+		WithArgs(created.ID).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetByID(ctx, created.ID)
+	assert.Equal(t, NotFoundErrorType, err.(*APIError).Type)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_GetAllSkipTake(t *testing.T) {
+	repo, mock := newSQLTestRepo(t)
+	ctx := context.Background()
+
+	filters := map[string]interface{}{
+		"name":  []string{"Ana", "Bia"},
+		"email": nil,
+	}
+
+	mock.ExpectQuery("SELECT COUNT(*) FROM users WHERE email IS NULL AND name IN ($1, $2)").
+		WithArgs("Ana", "Bia").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+
+	mock.ExpectQuery("SELECT id, name, email FROM users WHERE email IS NULL AND name IN ($1, $2) ORDER BY name ASC LIMIT 5 OFFSET 10").
+		WithArgs("Ana", "Bia").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).
+			AddRow(uuid.New(), "Ana", "").
+			AddRow(uuid.New(), "Bia", ""))
+
+	entities, total, err := repo.GetAllSkipTake(ctx, filters, Pagination{Skip: 10, Take: 5}, &QueryOptions{
+		Order: Order{By: "name", At: 1},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, entities, 2)
+	assert.Equal(t, int64(12), total)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_RejectsUnknownColumns(t *testing.T) {
+	repo, mock := newSQLTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.GetAll(ctx, map[string]interface{}{"name; DROP TABLE users": "x"})
+	assert.Equal(t, BadRequestErrorType, err.(*APIError).Type)
+
+	_, err = repo.GetAll(ctx, map[string]interface{}{"notes": "x"})
+	assert.Equal(t, BadRequestErrorType, err.(*APIError).Type)
+
+	_, err = repo.GetAll(ctx, nil, &QueryOptions{Order: Order{By: "1; DROP TABLE users"}})
+	assert.Equal(t, BadRequestErrorType, err.(*APIError).Type)
+
+	_, err = repo.Aggregate(ctx, nil)
+	assert.Equal(t, BadRequestErrorType, err.(*APIError).Type)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_QuestionPlaceholderAndGeneratedID(t *testing.T) {
+	type item struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSQLRepository[item, int64](db, "items", WithPlaceholder(SQLPlaceholderQuestion))
+
+	mock.ExpectExec("INSERT INTO items (name) VALUES (?)").
+		WithArgs("caneta").
+		WillReturnResult(sqlmock.NewResult(42, 1))
+
+	created, err := repo.Create(context.Background(), item{Name: "caneta"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), created.ID)
+
+	mock.ExpectQuery("SELECT COUNT(*) FROM items WHERE name = ?").
+		WithArgs("caneta").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	exists, err := repo.ExistsBy(context.Background(), map[string]interface{}{"name": "caneta"})
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+type sqlAuditTestEntity struct {
+	ID       uuid.UUID `db:"id"`
+	Name     string    `db:"name"`
+	TenantID uuid.UUID `db:"tenant_id"`
+	Active   bool      `db:"active"`
+	Created  AuditInfo `db:"created"`
+	Deleted  AuditInfo `db:"deleted"`
+}
+
+func (e *sqlAuditTestEntity) SetTenantID(s string)      { e.TenantID, _ = uuid.Parse(s) }
+func (e *sqlAuditTestEntity) SetCreated(info AuditInfo) { e.Created = info }
+func (e *sqlAuditTestEntity) SetUpdated(info AuditInfo) {}
+func (e *sqlAuditTestEntity) SetDeleted(info AuditInfo) { e.Deleted = info }
+func (e *sqlAuditTestEntity) SetActive(active bool)     { e.Active = active }
+
+func TestSQLAuditRepository_TenantScopeAndSoftDelete(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSQLAuditRepository[*sqlAuditTestEntity, uuid.UUID](db, "users")
+
+	tenantID := uuid.New()
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), TenantIDKey, tenantID.String())
+	ctx = context.WithValue(ctx, UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, UserNameKey, "Ana")
+
+	mock.ExpectExec("INSERT INTO users (id, name, tenant_id, created_at, created_by, created_by_name, updated_at, updated_by, updated_by_name, active) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)").
+		WithArgs(sqlmock.AnyArg(), "Ana", tenantID, sqlmock.AnyArg(), userID, "Ana", sqlmock.AnyArg(), userID, "Ana", true).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	created, err := repo.Create(ctx, &sqlAuditTestEntity{Name: "Ana"})
+	assert.NoError(t, err)
+	assert.Equal(t, tenantID, created.TenantID)
+	assert.Equal(t, userID, created.Created.ByID)
+	assert.True(t, created.Active)
+
+	columns := []string{"id", "name", "tenant_id", "active", "created_at", "created_by", "created_by_name", "deleted_at", "deleted_by", "deleted_by_name"}
+
+	mock.ExpectQuery("SELECT "+"id, name, tenant_id, active, created_at, created_by, created_by_name, deleted_at, deleted_by, deleted_by_name"+
+		" FROM users WHERE tenant_id = $1 AND deleted_at IS NULL AND id = $2 LIMIT 1").
+		WithArgs(tenantID, created.ID).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(created.ID, "Ana", tenantID, true, created.Created.SetAt, userID, "Ana", nil, nil, nil))
+
+	found, err := repo.GetByID(ctx, created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Ana", found.Created.ByName)
+	assert.True(t, found.Deleted.SetAt.IsZero())
+
+	mock.ExpectExec("UPDATE users SET deleted_at = $1, deleted_by = $2, deleted_by_name = $3, active = $4 WHERE id = $5 AND tenant_id = $6 AND deleted_at IS NULL").
+		WithArgs(sqlmock.AnyArg(), userID, "Ana", false, created.ID, tenantID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.Delete(ctx, created.ID))
+
+	mock.ExpectExec("UPDATE users SET deleted_at = $1, deleted_by = $2, deleted_by_name = $3, active = $4 WHERE id = $5 AND tenant_id = $6 AND deleted_at IS NOT NULL").
+		WithArgs(nil, nil, nil, true, created.ID, tenantID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Restore(ctx, created.ID)
+	assert.Equal(t, NotFoundErrorType, err.(*APIError).Type)
+
+	mock.ExpectQuery("SELECT id, name, tenant_id, active, created_at, created_by, created_by_name, deleted_at, deleted_by, deleted_by_name"+
+		" FROM users WHERE tenant_id = $1 AND deleted_at IS NOT NULL AND name = $2 ORDER BY created_at DESC").
+		WithArgs(tenantID, "Ana").
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	deleted, err := repo.GetDeleted(ctx, map[string]interface{}{"name": "Ana"})
+	assert.NoError(t, err)
+	assert.Empty(t, deleted)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLAuditRepository_InvalidTenantFailsClosed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSQLAuditRepository[*sqlAuditTestEntity, uuid.UUID](db, "users")
+	ctx := context.WithValue(context.Background(), TenantIDKey, "not-a-uuid")
+
+	_, err = repo.Create(ctx, &sqlAuditTestEntity{Name: "Ana"})
+	assert.Equal(t, BadRequestErrorType, err.(*APIError).Type)
+
+	_, err = repo.GetAllIncludingDeleted(ctx, nil)
+	assert.Equal(t, BadRequestErrorType, err.(*APIError).Type)
+
+	err = repo.HardDelete(ctx, uuid.New())
+	assert.Equal(t, BadRequestErrorType, err.(*APIError).Type)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLAuditRepository_MissingTenantFailsClosed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSQLAuditRepository[*sqlAuditTestEntity, uuid.UUID](db, "users")
+	ctx := context.Background()
+	id := uuid.New()
+
+	assertUnauthorized := func(err error) {
+		t.Helper()
+		apiErr, ok := err.(*APIError)
+		if assert.True(t, ok, "expected *APIError, got %v", err) {
+			assert.Equal(t, http.StatusUnauthorized, apiErr.Code)
+		}
+	}
+
+	_, err = repo.Create(ctx, &sqlAuditTestEntity{Name: "Ana"})
+	assertUnauthorized(err)
+	_, err = repo.GetByID(ctx, id)
+	assertUnauthorized(err)
+	_, err = repo.GetAll(ctx, nil)
+	assertUnauthorized(err)
+	_, err = repo.Update(ctx, id, &sqlAuditTestEntity{Name: "Ana"})
+	assertUnauthorized(err)
+	_, err = repo.Patch(ctx, id, map[string]interface{}{"name": "Ana"})
+	assertUnauthorized(err)
+	assertUnauthorized(repo.Delete(ctx, id))
+	assertUnauthorized(repo.HardDelete(ctx, id))
+	assertUnauthorized(repo.Restore(ctx, id))
+	_, err = repo.GetDeleted(ctx, nil)
+	assertUnauthorized(err)
+	_, err = repo.Count(ctx, nil)
+	assertUnauthorized(err)
+
+	// Nenhuma query chega ao banco
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// captureArg aceita qualquer valor e guarda o último recebido
+type captureArg struct{ value driver.Value }
+
+func (a *captureArg) Match(v driver.Value) bool {
+	a.value = v
+	return true
+}
+
+func TestSQLAuditRepository_CreateUsesRequestActionAt(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSQLAuditRepository[*sqlAuditTestEntity, uuid.UUID](db, "users")
+	tenantID := uuid.New()
+
+	createdAt, updatedAt := &captureArg{}, &captureArg{}
+	mock.ExpectExec("INSERT INTO users (id, name, tenant_id, created_at, created_by, created_by_name, updated_at, updated_by, updated_by_name, active) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)").
+		WithArgs(sqlmock.AnyArg(), "Ana", tenantID, createdAt, sqlmock.AnyArg(), sqlmock.AnyArg(), updatedAt, sqlmock.AnyArg(), sqlmock.AnyArg(), true).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Extractor customizado sem ActionAt: o middleware fixa um valor para a requisição
+	app := New()
+	app.Use(TenantMiddleware(func(c *gin.Context) TenantInfo {
+		return TenantInfo{TenantID: c.GetHeader("X-Tenant-ID")}
+	}))
+
+	var actionAt time.Time
+	app.POST("/users", Handle(func(c *Context[any]) error {
+		actionAt = c.GetActionAt()
+		assert.Equal(t, actionAt, GetTenantInfo(c.Request.Context()).ActionAt)
+
+		_, err := repo.Create(c.Request.Context(), &sqlAuditTestEntity{Name: "Ana"})
+		return err
+	}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/users", nil)
+	req.Header.Set("X-Tenant-ID", tenantID.String())
+	app.ServeHTTP(w, req)
+
+	assert.False(t, actionAt.IsZero())
+	assert.Equal(t, actionAt, createdAt.value)
+	assert.Equal(t, createdAt.value, updatedAt.value)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestActionAt_FallbackAndReuse(t *testing.T) {
+	// Fora de uma requisição os getters continuam retornando o horário atual
+	before := time.Now()
+	assert.False(t, GetActionAt(context.Background()).Before(before))
+	assert.False(t, GetTenantInfo(context.Background()).ActionAt.IsZero())
+
+	fixed := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, fixed, GetActionAt(WithActionAt(context.Background(), fixed)))
+
+	// O extractor padrão não sobrescreve o ActionAt fixado antes do TenantMiddleware
+	app := New()
+	app.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(WithActionAt(c.Request.Context(), fixed))
+		c.Next()
+	})
+	app.Use(TenantMiddleware(DefaultTenantExtractor))
+
+	var ginAt, ctxAt time.Time
+	app.GET("/now", Handle(func(c *Context[any]) error {
+		ginAt = c.GetActionAt()
+		ctxAt = GetActionAt(c.Request.Context())
+		c.NoContent()
+		return nil
+	}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/now", nil)
+	app.ServeHTTP(w, req)
+
+	assert.Equal(t, fixed, ginAt)
+	assert.Equal(t, fixed, ctxAt)
+}
+
+func TestSQLRepository_Patch(t *testing.T) {
+	repo, mock := newSQLTestRepo(t)
+	ctx := context.Background()
+	id := uuid.New()
+
+	mock.ExpectExec("UPDATE users SET name = $1 WHERE id = $2").
+		WithArgs("Bia", id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT id, name, email FROM users WHERE id = $1 LIMIT 1").
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(id, "Bia", "ana@test.com"))
+
+	patched, err := repo.Patch(ctx, id, map[string]interface{}{"name": "Bia"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Bia", patched.Name)
+	assert.Equal(t, "ana@test.com", patched.Email)
+
+	for _, fields := range []map[string]interface{}{{}, {"id": uuid.New()}, {"unknown": 1}} {
+		_, err = repo.Patch(ctx, id, fields)
+		assert.Equal(t, BadRequestErrorType, err.(*APIError).Type)
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
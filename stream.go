@@ -157,7 +157,7 @@ func (sc *StreamClient) processMessage(ctx context.Context, config StreamConsume
 	if userName, ok := msg.Values["user_name"].(string); ok && userName != "" {
 		msgCtx = context.WithValue(msgCtx, UserNameKey, userName)
 	}
-	msgCtx = context.WithValue(msgCtx, ActionAtKey, time.Now())
+	msgCtx = WithActionAt(msgCtx, time.Now())
 
 	// Extrai payload
 	payload, ok := msg.Values["payload"].(string)
@@ -2,17 +2,23 @@ package zendia
 
 import (
 	"context"
+	"net/http"
 	"time"
+	"unicode"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 // TenantContext chaves para contexto de tenant
 const (
-	TenantIDKey  = "tenant_id"
-	UserIDKey    = "user_id"
-	UserNameKey  = "user_name"
-	ActionAtKey  = "action_at"
+	TenantIDKey    = "tenant_id"
+	UserIDKey      = "user_id"
+	UserNameKey    = "user_name"
+	ActionAtKey    = "action_at"
+	TenantExtraKey = "tenant_extra"
+
+	ImpersonationKey = "impersonation" // *Impersonation gravado pelo Context.ImpersonateTenant
 )
 
 // TenantInfo informações do tenant no contexto
@@ -21,49 +27,237 @@ type TenantInfo struct {
 	UserID   string    `json:"userId"`
 	UserName string    `json:"userName"`
 	ActionAt time.Time `json:"actionAt"`
+
+	// Extra chaves de isolamento além do tenant (ex: org_id), usadas com WithIsolationKeys
+	Extra map[string]string `json:"extra,omitempty"`
+}
+
+// TenantSource origem do tenant/usuário da requisição, em ordem crescente de precedência
+type TenantSource int
+
+const (
+	TenantSourceNone          TenantSource = iota // Nada extraído
+	TenantSourceHeader                            // TenantExtractor (headers X-Tenant-ID, X-User-ID... no padrão)
+	TenantSourceExplicit                          // c.SetTenant / c.SetUserID / c.SetUserName
+	TenantSourceClaim                             // Claim do token verificado pelo Firebase Auth
+	TenantSourceImpersonation                     // c.ImpersonateTenant de um admin
+)
+
+// String nome da origem ("none", "header", "explicit", "claim", "impersonation")
+func (s TenantSource) String() string {
+	switch s {
+	case TenantSourceHeader:
+		return "header"
+	case TenantSourceExplicit:
+		return "explicit"
+	case TenantSourceClaim:
+		return "claim"
+	case TenantSourceImpersonation:
+		return "impersonation"
+	}
+	return "none"
+}
+
+// identitySourcesKey origem de cada valor de identidade (TenantIDKey, UserIDKey, UserNameKey) no gin.Context
+const identitySourcesKey = "identity_sources"
+
+// setIdentity grava tenant, user ID ou nome vindo de source no gin.Context e no context.Context
+// A precedência é claim > SetTenant > header, independente da ordem dos middlewares:
+// uma origem mais fraca nunca sobrescreve uma mais forte. Valores vazios são ignorados.
+// Retorna false quando o valor não foi aplicado.
+func setIdentity(c *gin.Context, key, value string, source TenantSource) bool {
+	if value == "" || identitySource(c, key) > source {
+		return false
+	}
+
+	sources, _ := c.Get(identitySourcesKey)
+	m, ok := sources.(map[string]TenantSource)
+	if !ok {
+		m = make(map[string]TenantSource, 3)
+		c.Set(identitySourcesKey, m)
+	}
+	m[key] = source
+
+	c.Set(key, value)
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), key, value))
+	return true
+}
+
+// identitySource origem do valor gravado em key
+func identitySource(c *gin.Context, key string) TenantSource {
+	if sources, exists := c.Get(identitySourcesKey); exists {
+		if m, ok := sources.(map[string]TenantSource); ok {
+			return m[key]
+		}
+	}
+	return TenantSourceNone
+}
+
+// GetTenantSourceFromGin origem do tenant da requisição (TenantSourceNone sem tenant)
+func GetTenantSourceFromGin(c *gin.Context) TenantSource {
+	return identitySource(c, TenantIDKey)
 }
 
 // TenantExtractor função para extrair informações do tenant
 type TenantExtractor func(*gin.Context) TenantInfo
 
 // DefaultTenantExtractor extrator padrão que busca nos headers
+// ActionAt fica zerado para o TenantMiddleware reaproveitar o já fixado na requisição
 func DefaultTenantExtractor(c *gin.Context) TenantInfo {
 	return TenantInfo{
 		TenantID: c.GetHeader("X-Tenant-ID"),
 		UserID:   c.GetHeader("X-User-ID"),
 		UserName: c.GetHeader("X-User-Name"),
-		ActionAt: time.Now(),
 	}
 }
 
+// HeaderTenantExtractor extrator dos headers padrão mais chaves de isolamento extras
+// mapeadas para headers (ex: {"org_id": "X-Org-ID"}); headers ausentes ficam fora de Extra
+func HeaderTenantExtractor(extraHeaders map[string]string) TenantExtractor {
+	return func(c *gin.Context) TenantInfo {
+		info := DefaultTenantExtractor(c)
+		for key, header := range extraHeaders {
+			if value := c.GetHeader(header); value != "" {
+				if info.Extra == nil {
+					info.Extra = make(map[string]string, len(extraHeaders))
+				}
+				info.Extra[key] = value
+			}
+		}
+		return info
+	}
+}
+
+// TenantIDFormat formato esperado do header X-Tenant-ID
+type TenantIDFormat int
+
+const (
+	TenantIDFormatUUID   TenantIDFormat = iota // UUID (padrão dos repositories)
+	TenantIDFormatOpaque                       // String livre, sem espaços nem caracteres de controle
+)
+
+// maxOpaqueTenantIDLength tamanho máximo de um tenant ID opaco
+const maxOpaqueTenantIDLength = 128
+
+// ValidateTenantHeader rejeita X-Tenant-ID mal formatado com BadRequest antes de qualquer handler
+// Sem o header a requisição segue (rotas públicas); a exigência de tenant fica com quem precisa dele.
+// Use antes do TenantMiddleware / SetTenantExtractor:
+//
+//	app.Use(zendia.ValidateTenantHeader(zendia.TenantIDFormatUUID))
+func ValidateTenantHeader(format TenantIDFormat) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID := c.GetHeader(HeaderTenantID)
+		if tenantID != "" && !validTenantID(tenantID, format) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, errorEnvelope(c, NewBadRequestError(MsgInvalidTenantID)))
+			return
+		}
+		c.Next()
+	}
+}
+
+// validTenantID verifica o tenant ID contra o formato configurado
+func validTenantID(tenantID string, format TenantIDFormat) bool {
+	if format == TenantIDFormatOpaque {
+		if len(tenantID) > maxOpaqueTenantIDLength {
+			return false
+		}
+		for _, r := range tenantID {
+			if unicode.IsSpace(r) || unicode.IsControl(r) {
+				return false
+			}
+		}
+		return true
+	}
+
+	_, err := uuid.Parse(tenantID)
+	return err == nil
+}
+
 // TenantMiddleware middleware para carregar contexto do tenant
+// Fixa um único ActionAt para toda a requisição: o do extractor ou, se zerado,
+// o já fixado por outro middleware (ex: Firebase Auth) ou o horário de entrada
 func TenantMiddleware(extractor TenantExtractor) gin.HandlerFunc {
 	if extractor == nil {
 		extractor = DefaultTenantExtractor
 	}
 	
 	return func(c *gin.Context) {
-		tenantInfo := extractor(c)
-		
-		// Adiciona ao contexto do Gin
-		c.Set(TenantIDKey, tenantInfo.TenantID)
-		c.Set(UserIDKey, tenantInfo.UserID)
-		c.Set(UserNameKey, tenantInfo.UserName)
-		c.Set(ActionAtKey, tenantInfo.ActionAt)
-		
-		// Cria contexto com informações do tenant
-		ctx := context.WithValue(c.Request.Context(), TenantIDKey, tenantInfo.TenantID)
-		ctx = context.WithValue(ctx, UserIDKey, tenantInfo.UserID)
-		ctx = context.WithValue(ctx, UserNameKey, tenantInfo.UserName)
-		ctx = context.WithValue(ctx, ActionAtKey, tenantInfo.ActionAt)
-		
-		// Atualiza o request com o novo contexto
-		c.Request = c.Request.WithContext(ctx)
-		
+		applyTenant(c, extractor(c))
 		c.Next()
 	}
 }
 
+// applyTenant grava o TenantInfo no contexto do gin e no context.Context da requisição
+// Tenant e usuário do extractor têm a menor precedência: não sobrescrevem os vindos
+// do token (Firebase Auth) nem os definidos com SetTenant / SetUserID
+func applyTenant(c *gin.Context, tenantInfo TenantInfo) {
+	if tenantInfo.ActionAt.IsZero() {
+		tenantInfo.ActionAt = fixActionAt(c)
+	}
+	
+	setIdentity(c, TenantIDKey, tenantInfo.TenantID, TenantSourceHeader)
+	setIdentity(c, UserIDKey, tenantInfo.UserID, TenantSourceHeader)
+	setIdentity(c, UserNameKey, tenantInfo.UserName, TenantSourceHeader)
+
+	// Adiciona ao contexto do Gin
+	c.Set(ActionAtKey, tenantInfo.ActionAt)
+	if len(tenantInfo.Extra) > 0 {
+		c.Set(TenantExtraKey, tenantInfo.Extra)
+	}
+	
+	// Cria contexto com informações do tenant
+	ctx := context.WithValue(c.Request.Context(), ActionAtKey, tenantInfo.ActionAt)
+	if len(tenantInfo.Extra) > 0 {
+		ctx = context.WithValue(ctx, TenantExtraKey, tenantInfo.Extra)
+	}
+	
+	// Atualiza o request com o novo contexto
+	c.Request = c.Request.WithContext(ctx)
+}
+
+// fixActionAt retorna o ActionAt já fixado na requisição ou fixa o horário atual
+// no gin.Context e no context da request, para que toda leitura veja o mesmo valor
+func fixActionAt(c *gin.Context) time.Time {
+	if actionAt, ok := c.Request.Context().Value(ActionAtKey).(time.Time); ok && !actionAt.IsZero() {
+		c.Set(ActionAtKey, actionAt)
+		return actionAt
+	}
+
+	actionAt := time.Now()
+	c.Set(ActionAtKey, actionAt)
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), ActionAtKey, actionAt))
+	return actionAt
+}
+
+// WithActionAt fixa o timestamp da ação no context, para jobs e workers fora de uma requisição HTTP
+func WithActionAt(ctx context.Context, actionAt time.Time) context.Context {
+	return context.WithValue(ctx, ActionAtKey, actionAt)
+}
+
+// ParseTenantID converte o tenant ID para UUID
+// Retorna uuid.Nil sem erro quando não há tenant; tenant mal formatado retorna BadRequest
+func ParseTenantID(tenantID string) (uuid.UUID, error) {
+	if tenantID == "" {
+		return uuid.Nil, nil
+	}
+	tenantUUID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return uuid.Nil, NewBadRequestError(MsgInvalidTenantID)
+	}
+	return tenantUUID, nil
+}
+
+// ParseUserID converte o user ID para UUID de forma tolerante
+// IDs que não são UUID (ex: Firebase UID) resultam em uuid.Nil em vez de panic;
+// o nome do usuário continua registrado na auditoria
+func ParseUserID(userID string) uuid.UUID {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil
+	}
+	return id
+}
+
 // GetTenantID obtém o tenant ID do contexto
 func GetTenantID(ctx context.Context) string {
 	if tenantID, ok := ctx.Value(TenantIDKey).(string); ok {
@@ -81,8 +275,10 @@ func GetUserID(ctx context.Context) string {
 }
 
 // GetActionAt obtém o timestamp da ação do contexto
+// Retorna o valor fixado pelo middleware ou por WithActionAt (estável em toda a requisição);
+// fora deles (jobs, testes) retorna o horário atual
 func GetActionAt(ctx context.Context) time.Time {
-	if actionAt, ok := ctx.Value(ActionAtKey).(time.Time); ok {
+	if actionAt, ok := ctx.Value(ActionAtKey).(time.Time); ok && !actionAt.IsZero() {
 		return actionAt
 	}
 	return time.Now()
@@ -96,6 +292,19 @@ func GetUserName(ctx context.Context) string {
 	return ""
 }
 
+// GetTenantExtra obtém as chaves de isolamento extras do contexto
+func GetTenantExtra(ctx context.Context) map[string]string {
+	if extra, ok := ctx.Value(TenantExtraKey).(map[string]string); ok {
+		return extra
+	}
+	return nil
+}
+
+// WithTenantExtra adiciona as chaves de isolamento extras ao context (jobs e workers)
+func WithTenantExtra(ctx context.Context, extra map[string]string) context.Context {
+	return context.WithValue(ctx, TenantExtraKey, extra)
+}
+
 // GetTenantInfo obtém todas as informações do tenant do contexto
 func GetTenantInfo(ctx context.Context) TenantInfo {
 	return TenantInfo{
@@ -103,6 +312,7 @@ func GetTenantInfo(ctx context.Context) TenantInfo {
 		UserID:   GetUserID(ctx),
 		UserName: GetUserName(ctx),
 		ActionAt: GetActionAt(ctx),
+		Extra:    GetTenantExtra(ctx),
 	}
 }
 
@@ -122,12 +332,14 @@ func GetUserIDFromGin(c *gin.Context) string {
 	return ""
 }
 
-// GetActionAtFromGin obtém action timestamp do gin.Context
+// GetActionAtFromGin obtém action timestamp do gin.Context (horário atual quando não fixado)
 func GetActionAtFromGin(c *gin.Context) time.Time {
 	if actionAt, exists := c.Get(ActionAtKey); exists {
-		return actionAt.(time.Time)
+		if t, ok := actionAt.(time.Time); ok && !t.IsZero() {
+			return t
+		}
 	}
-	return time.Now()
+	return GetActionAt(c.Request.Context())
 }
 
 // GetUserNameFromGin obtém user name do gin.Context
@@ -138,6 +350,16 @@ func GetUserNameFromGin(c *gin.Context) string {
 	return ""
 }
 
+// GetTenantExtraFromGin obtém as chaves de isolamento extras do gin.Context
+func GetTenantExtraFromGin(c *gin.Context) map[string]string {
+	if extra, exists := c.Get(TenantExtraKey); exists {
+		if m, ok := extra.(map[string]string); ok {
+			return m
+		}
+	}
+	return nil
+}
+
 // GetTenantInfoFromGin obtém informações do tenant do gin.Context
 func GetTenantInfoFromGin(c *gin.Context) TenantInfo {
 	return TenantInfo{
@@ -145,5 +367,6 @@ func GetTenantInfoFromGin(c *gin.Context) TenantInfo {
 		UserID:   GetUserIDFromGin(c),
 		UserName: GetUserNameFromGin(c),
 		ActionAt: GetActionAtFromGin(c),
+		Extra:    GetTenantExtraFromGin(c),
 	}
 }
\ No newline at end of file
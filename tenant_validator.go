@@ -0,0 +1,109 @@
+package zendia
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tenantValidationPrefix prefixo das chaves de validação de tenant no CacheProvider
+const tenantValidationPrefix = "tenant_valid:"
+
+// TenantValidator confere se um tenant existe e está ativo (ex: consulta à tabela de tenants)
+type TenantValidator interface {
+	IsValidTenant(ctx context.Context, tenantID string) (bool, error)
+}
+
+// TenantValidatorFunc adapta uma função para TenantValidator
+type TenantValidatorFunc func(ctx context.Context, tenantID string) (bool, error)
+
+// IsValidTenant chama a função
+func (f TenantValidatorFunc) IsValidTenant(ctx context.Context, tenantID string) (bool, error) {
+	return f(ctx, tenantID)
+}
+
+// CachedTenantValidator guarda o resultado do TenantValidator por ttl, evitando uma consulta por requisição
+// Tenants válidos e inválidos são cacheados; erros do validator não
+type CachedTenantValidator struct {
+	validator TenantValidator
+	cache     CacheProvider
+	ttl       time.Duration
+}
+
+// NewCachedTenantValidator cria o validator com cache
+// cache nil usa um MemoryCache; ttl <= 0 usa DefaultTenantValidationTTL
+func NewCachedTenantValidator(validator TenantValidator, cache CacheProvider, ttl time.Duration) *CachedTenantValidator {
+	if ttl <= 0 {
+		ttl = DefaultTenantValidationTTL
+	}
+	if cache == nil {
+		cache = NewMemoryCache(MemoryCacheConfig{CacheConfig: CacheConfig{TTL: ttl}})
+	}
+	return &CachedTenantValidator{validator: validator, cache: cache, ttl: ttl}
+}
+
+// IsValidTenant consulta o cache e, na falta, o validator
+func (v *CachedTenantValidator) IsValidTenant(ctx context.Context, tenantID string) (bool, error) {
+	key := tenantValidationPrefix + tenantID
+	if data, found := v.cache.Get(ctx, key); found && len(data) == 1 {
+		return data[0] == '1', nil
+	}
+
+	valid, err := v.validator.IsValidTenant(ctx, tenantID)
+	if err != nil {
+		return false, err
+	}
+
+	value := []byte{'0'}
+	if valid {
+		value[0] = '1'
+	}
+	v.cache.Set(ctx, key, value, v.ttl)
+	return valid, nil
+}
+
+// Invalidate descarta o resultado cacheado de um tenant (ex: logo após desativá-lo)
+func (v *CachedTenantValidator) Invalidate(ctx context.Context, tenantID string) error {
+	return v.cache.Delete(ctx, tenantValidationPrefix+tenantID)
+}
+
+// RequireValidTenant middleware que recusa com 403 requisições de tenant desconhecido ou desativado
+// Requisições sem tenant seguem (rotas públicas). Falha do validator responde 500 (fail closed).
+// Deve rodar depois da extração do tenant (SetTenantExtractor, TenantMiddleware ou Firebase Auth)
+func RequireValidTenant(validator TenantValidator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID := GetTenantIDFromGin(c)
+		if tenantID == "" {
+			c.Next()
+			return
+		}
+
+		valid, err := validator.IsValidTenant(c.Request.Context(), tenantID)
+		if err != nil {
+			log.Printf("[ZENDIA] tenant validation failed for %s: %v", tenantID, err)
+			apiErr := NewInternalError(MsgInternalServerError)
+			c.AbortWithStatusJSON(apiErr.Code, errorEnvelope(c, apiErr))
+			return
+		}
+		if !valid {
+			apiErr := NewForbiddenError(MsgTenantNotAllowed)
+			c.AbortWithStatusJSON(apiErr.Code, errorEnvelope(c, apiErr))
+			return
+		}
+		c.Next()
+	}
+}
+
+// SetTenantValidator registra o validator de tenant com cache em memória de cacheTTL
+// Chame depois de SetTenantExtractor / SetupFirebaseAuth, para rodar com o tenant já extraído.
+// O validator com cache retornado permite invalidar um tenant desativado:
+//
+//	tenants := app.SetTenantValidator(zendia.TenantValidatorFunc(tenantStore.IsActive), time.Minute)
+//	tenants.Invalidate(ctx, tenantID)
+func (z *Zendia) SetTenantValidator(validator TenantValidator, cacheTTL time.Duration) *CachedTenantValidator {
+	cached := NewCachedTenantValidator(validator, nil, cacheTTL)
+	z.Use(RequireValidTenant(cached))
+	return cached
+}
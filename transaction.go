@@ -13,7 +13,7 @@ type TxFunc func(ctx context.Context) error
 func RunTransaction(ctx context.Context, client *mongo.Client, fn TxFunc) error {
 	session, err := client.StartSession()
 	if err != nil {
-		return NewInternalError("Failed to start transaction session: " + err.Error())
+		return internalError("Failed to start transaction session", err)
 	}
 	defer session.EndSession(ctx)
 
@@ -28,7 +28,7 @@ func RunTransaction(ctx context.Context, client *mongo.Client, fn TxFunc) error
 
 // propagateTenantContext copia os valores de tenant/user do src para o dst.
 func propagateTenantContext(src context.Context, dst context.Context) context.Context {
-	for _, key := range []string{TenantIDKey, UserIDKey, UserNameKey, ActionAtKey, ContextEmail} {
+	for _, key := range []string{TenantIDKey, UserIDKey, UserNameKey, ActionAtKey, TenantExtraKey, ContextEmail} {
 		if val := src.Value(key); val != nil {
 			dst = context.WithValue(dst, key, val)
 		}
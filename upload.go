@@ -0,0 +1,107 @@
+package zendia
+
+import (
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// UploadConfig configuração para upload de arquivos
+type UploadConfig struct {
+	MaxFileSize         int64    // Tamanho máximo por arquivo em bytes
+	AllowedContentTypes []string // Content-Types permitidos (vazio = qualquer tipo)
+}
+
+// DefaultUploadConfig configuração padrão de upload
+var DefaultUploadConfig = UploadConfig{
+	MaxFileSize: DefaultMaxUploadSize,
+}
+
+// SetUploadConfig configura os limites de upload usados por FormFile e SaveUploadedFile
+func (z *Zendia) SetUploadConfig(config UploadConfig) {
+	if config.MaxFileSize <= 0 {
+		config.MaxFileSize = DefaultMaxUploadSize
+	}
+	z.uploadConfig = config
+}
+
+// GetUploadConfig retorna a configuração de upload
+func (z *Zendia) GetUploadConfig() UploadConfig {
+	return z.uploadConfig
+}
+
+// FormFile retorna o arquivo enviado no campo informado, validando tamanho e Content-Type
+func (c *Context[T]) FormFile(name string) (*multipart.FileHeader, error) {
+	file, err := c.Context.FormFile(name)
+	if err != nil {
+		return nil, NewBadRequestError(fmt.Sprintf("File '%s' is required", name))
+	}
+
+	if err := c.validateUpload(file); err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// SaveUploadedFile salva o arquivo enviado no destino informado
+func (c *Context[T]) SaveUploadedFile(file *multipart.FileHeader, dst string) error {
+	if err := c.validateUpload(file); err != nil {
+		return err
+	}
+
+	if err := c.Context.SaveUploadedFile(file, dst); err != nil {
+		return internalError("Failed to save uploaded file", err)
+	}
+
+	return nil
+}
+
+// BindForm faz o bind e validação dos campos de formulário (urlencoded ou multipart)
+func (c *Context[T]) BindForm(obj *T) error {
+	if err := c.Context.ShouldBindWith(obj, binding.Form); err != nil {
+		return NewValidationError("Invalid form data", err)
+	}
+
+	// Valida usando o validator compartilhado
+	if c.zendia != nil {
+		if err := c.zendia.GetValidator().ValidateLocale(obj, c.Locale()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateUpload aplica os limites de tamanho e tipo da configuração de upload
+func (c *Context[T]) validateUpload(file *multipart.FileHeader) error {
+	config := DefaultUploadConfig
+	if c.zendia != nil {
+		config = c.zendia.GetUploadConfig()
+	}
+
+	if file.Size > config.MaxFileSize {
+		return NewBadRequestError(fmt.Sprintf("File too large: %d bytes (max: %d bytes)", file.Size, config.MaxFileSize))
+	}
+
+	if len(config.AllowedContentTypes) == 0 {
+		return nil
+	}
+
+	// O Content-Type é o declarado pelo cliente na parte do multipart
+	contentType, _, err := mime.ParseMediaType(file.Header.Get("Content-Type"))
+	if err != nil {
+		return NewBadRequestError("Invalid file content type")
+	}
+
+	for _, allowed := range config.AllowedContentTypes {
+		if strings.EqualFold(contentType, allowed) {
+			return nil
+		}
+	}
+
+	return NewBadRequestError(fmt.Sprintf("File content type not allowed: %s", sanitizeLogValue(contentType)))
+}
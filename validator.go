@@ -12,6 +12,7 @@ import (
 // Validator encapsula o validador
 type Validator struct {
 	validate *validator.Validate
+	messages map[string]map[string]string // idioma base → tag → formato
 }
 
 // NewValidator cria uma nova instância do validador
@@ -26,30 +27,82 @@ func NewValidator() *Validator {
 		}
 		return name
 	})
+
+	messages := make(map[string]map[string]string, len(defaultValidationMessages))
+	for lang, formats := range defaultValidationMessages {
+		messages[lang] = make(map[string]string, len(formats))
+		for tag, format := range formats {
+			messages[lang][tag] = format
+		}
+	}
 	
-	return &Validator{validate: v}
+	return &Validator{validate: v, messages: messages}
 }
 
-// Validate valida uma estrutura
+// Validate valida uma estrutura com as mensagens em DefaultLocale
 func (v *Validator) Validate(s interface{}) error {
-	if err := v.validate.Struct(s); err != nil {
-		validationErrors := err.(validator.ValidationErrors)
-		if len(validationErrors) == 1 {
-			// Otimização: se há apenas um erro, não precisa de slice
-			return NewValidationError("Validation failed", fmt.Errorf(v.formatError(validationErrors[0])))
-		}
-		
-		// Para múltiplos erros, usa strings.Builder para melhor performance
-		var builder strings.Builder
-		for i, err := range validationErrors {
-			if i > 0 {
-				builder.WriteString("; ")
-			}
-			builder.WriteString(v.formatError(err))
+	return v.ValidateLocale(s, DefaultLocale)
+}
+
+// ValidateLocale valida uma estrutura com as mensagens no idioma de locale ("en", "en-US", "pt-BR")
+// Idioma sem mensagens cadastradas usa as de DefaultLocale. Os erros por campo vão em
+// Details como FieldErrors
+func (v *Validator) ValidateLocale(s interface{}, locale string) error {
+	err := v.validate.Struct(s)
+	if err == nil {
+		return nil
+	}
+
+	validationErrors := err.(validator.ValidationErrors)
+	formats := v.formatsFor(locale)
+	fields := make(FieldErrors, len(validationErrors))
+	for i, fe := range validationErrors {
+		fields[i] = FieldError{
+			Field:   sanitizeLogValue(fe.Field()),
+			Tag:     sanitizeLogValue(fe.Tag()),
+			Message: v.formatError(fe, formats),
 		}
-		return NewValidationError("Validation failed", fmt.Errorf(builder.String()))
 	}
-	return nil
+	return NewValidationError("Validation failed", fields)
+}
+
+// FieldError falha de validação de um campo (nome json, tag e mensagem no idioma pedido)
+type FieldError struct {
+	Field   string `json:"field"`
+	Tag     string `json:"tag"`
+	Message string `json:"message"`
+}
+
+// FieldErrors erros por campo de uma validação; Error junta as mensagens com "; "
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		messages[i] = fe.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// SetMessage cadastra ou troca a mensagem de uma tag em um idioma
+// format recebe o campo (%[1]s), o parâmetro da tag (%[2]s) e a tag (%[3]s).
+// Configure na inicialização, antes de atender requisições
+//
+//	v.SetMessage("es", "required", "%[1]s es obligatorio")
+func (v *Validator) SetMessage(locale, tag, format string) {
+	lang := localeLanguage(locale)
+	if v.messages[lang] == nil {
+		v.messages[lang] = make(map[string]string)
+	}
+	v.messages[lang][tag] = format
+}
+
+// formatsFor mensagens do idioma de locale, ou as de DefaultLocale
+func (v *Validator) formatsFor(locale string) map[string]string {
+	if formats, ok := v.messages[localeLanguage(locale)]; ok {
+		return formats
+	}
+	return v.messages[localeLanguage(DefaultLocale)]
 }
 
 // RegisterValidation registra uma validação customizada
@@ -84,44 +137,66 @@ func sanitizeLogValue(value string) string {
 	return controlCharsRegex.ReplaceAllString(value, "")
 }
 
-// formatError formats validation errors in Portuguese with log injection protection
-func (v *Validator) formatError(err validator.FieldError) string {
+// formatError formats validation errors in the given language with log injection protection
+func (v *Validator) formatError(err validator.FieldError, formats map[string]string) string {
 	// Sanitize field name and parameters to prevent log injection
 	field := sanitizeLogValue(err.Field())
 	tag := sanitizeLogValue(err.Tag())
 	param := sanitizeLogValue(err.Param())
 	
-	switch tag {
-	case "required":
-		return fmt.Sprintf("%s é obrigatório", field)
-	case "email":
-		return fmt.Sprintf("%s deve ser um email válido", field)
-	case "min":
-		return fmt.Sprintf("%s deve ter pelo menos %s caracteres", field, param)
-	case "max":
-		return fmt.Sprintf("%s deve ter no máximo %s caracteres", field, param)
-	case "len":
-		return fmt.Sprintf("%s deve ter exatamente %s caracteres", field, param)
-	case "gt":
-		return fmt.Sprintf("%s deve ser maior que %s", field, param)
-	case "gte":
-		return fmt.Sprintf("%s deve ser maior ou igual a %s", field, param)
-	case "lt":
-		return fmt.Sprintf("%s deve ser menor que %s", field, param)
-	case "lte":
-		return fmt.Sprintf("%s deve ser menor ou igual a %s", field, param)
-	case "oneof":
-		return fmt.Sprintf("%s deve ser um dos valores: %s", field, param)
-	case "uuid":
-		return fmt.Sprintf("%s deve ser um UUID válido", field)
-	case "numeric":
-		return fmt.Sprintf("%s deve ser numérico", field)
-	case "alpha":
-		return fmt.Sprintf("%s deve conter apenas letras", field)
-	case "alphanum":
-		return fmt.Sprintf("%s deve conter apenas letras e números", field)
-	default:
-		return fmt.Sprintf("%s falhou na validação '%s'", field, tag)
+	// Idiomas cadastrados com SetMessage podem não ter todas as tags
+	fallback := v.messages[localeLanguage(DefaultLocale)]
+	format, ok := formats[tag]
+	if !ok {
+		format, ok = fallback[tag]
+	}
+	if !ok {
+		format, ok = formats[validationFallbackTag]
 	}
+	if !ok {
+		format = fallback[validationFallbackTag]
+	}
+	return fmt.Sprintf(format, field, param, tag)
 }
 
+// validationFallbackTag mensagem usada para tags sem mensagem própria
+const validationFallbackTag = "*"
+
+// defaultValidationMessages mensagens padrão em português e inglês
+// %[1]s campo, %[2]s parâmetro da tag, %[3]s tag
+var defaultValidationMessages = map[string]map[string]string{
+	"pt": {
+		"required":            "%[1]s é obrigatório",
+		"email":               "%[1]s deve ser um email válido",
+		"min":                 "%[1]s deve ter pelo menos %[2]s caracteres",
+		"max":                 "%[1]s deve ter no máximo %[2]s caracteres",
+		"len":                 "%[1]s deve ter exatamente %[2]s caracteres",
+		"gt":                  "%[1]s deve ser maior que %[2]s",
+		"gte":                 "%[1]s deve ser maior ou igual a %[2]s",
+		"lt":                  "%[1]s deve ser menor que %[2]s",
+		"lte":                 "%[1]s deve ser menor ou igual a %[2]s",
+		"oneof":               "%[1]s deve ser um dos valores: %[2]s",
+		"uuid":                "%[1]s deve ser um UUID válido",
+		"numeric":             "%[1]s deve ser numérico",
+		"alpha":               "%[1]s deve conter apenas letras",
+		"alphanum":            "%[1]s deve conter apenas letras e números",
+		validationFallbackTag: "%[1]s falhou na validação '%[3]s'",
+	},
+	"en": {
+		"required":            "%[1]s is required",
+		"email":               "%[1]s must be a valid email",
+		"min":                 "%[1]s must be at least %[2]s characters",
+		"max":                 "%[1]s must be at most %[2]s characters",
+		"len":                 "%[1]s must be exactly %[2]s characters",
+		"gt":                  "%[1]s must be greater than %[2]s",
+		"gte":                 "%[1]s must be greater than or equal to %[2]s",
+		"lt":                  "%[1]s must be less than %[2]s",
+		"lte":                 "%[1]s must be less than or equal to %[2]s",
+		"oneof":               "%[1]s must be one of: %[2]s",
+		"uuid":                "%[1]s must be a valid UUID",
+		"numeric":             "%[1]s must be numeric",
+		"alpha":               "%[1]s must contain only letters",
+		"alphanum":            "%[1]s must contain only letters and numbers",
+		validationFallbackTag: "%[1]s failed on the '%[3]s' validation",
+	},
+}
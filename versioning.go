@@ -0,0 +1,130 @@
+package zendia
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIVersionKey chave da versão da API no gin.Context e no context.Context
+const APIVersionKey = "api_version"
+
+// VersioningConfig de onde a versão da API é lida
+type VersioningConfig struct {
+	Header  string // Header com a versão (padrão: X-API-Version)
+	Vendor  string // Vendor do Accept (ex: "myapi" lê application/vnd.myapi.v2+json); vazio ignora o Accept
+	Default string // Versão quando o cliente não informa nenhuma
+}
+
+// Versioning middleware que lê a versão da API do cliente e grava no contexto
+// O Accept com vendor tem precedência sobre o header; o "v" inicial é removido ("v2" → "2")
+//
+// Uso:
+//
+//	app.Use(zendia.Versioning(zendia.VersioningConfig{Vendor: "myapi", Default: "1"}))
+func Versioning(config VersioningConfig) gin.HandlerFunc {
+	if config.Header == "" {
+		config.Header = HeaderAPIVersion
+	}
+
+	return func(c *gin.Context) {
+		version := ""
+		if config.Vendor != "" {
+			version = vendorVersion(c.GetHeader("Accept"), config.Vendor)
+		}
+		if version == "" {
+			version = c.GetHeader(config.Header)
+		}
+		version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+		if version == "" {
+			version = strings.TrimPrefix(config.Default, "v")
+		}
+
+		c.Set(APIVersionKey, version)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), APIVersionKey, version))
+		c.Next()
+	}
+}
+
+// RequireMinVersion guard que responde 426 Upgrade Required para clientes abaixo de minVersion
+// Requisições sem versão também são recusadas; use VersioningConfig.Default para aceitá-las
+func RequireMinVersion(minVersion string) gin.HandlerFunc {
+	minVersion = strings.TrimPrefix(minVersion, "v")
+
+	return func(c *gin.Context) {
+		version := GetAPIVersionFromGin(c)
+		if version == "" || compareVersions(version, minVersion) < 0 {
+			apiErr := NewUpgradeRequiredError("API version " + minVersion + " or later required")
+			c.AbortWithStatusJSON(apiErr.Code, errorEnvelope(c, apiErr))
+			return
+		}
+		c.Next()
+	}
+}
+
+// APIVersion retorna a versão da API lida pelo middleware Versioning ("" sem o middleware)
+func (c *Context[T]) APIVersion() string {
+	return GetAPIVersionFromGin(c.Context)
+}
+
+// GetAPIVersion retorna a versão da API do context.Context
+func GetAPIVersion(ctx context.Context) string {
+	if version, ok := ctx.Value(APIVersionKey).(string); ok {
+		return version
+	}
+	return ""
+}
+
+// GetAPIVersionFromGin retorna a versão da API do gin.Context
+func GetAPIVersionFromGin(c *gin.Context) string {
+	return c.GetString(APIVersionKey)
+}
+
+// vendorVersion extrai a versão de um Accept como application/vnd.<vendor>.v2+json
+func vendorVersion(accept, vendor string) string {
+	prefix := "application/vnd." + strings.ToLower(vendor) + "."
+	for _, mediaType := range strings.Split(accept, ",") {
+		mediaType = strings.ToLower(strings.TrimSpace(strings.SplitN(mediaType, ";", 2)[0]))
+		if !strings.HasPrefix(mediaType, prefix) {
+			continue
+		}
+		version := strings.TrimPrefix(mediaType, prefix)
+		if i := strings.IndexByte(version, '+'); i >= 0 {
+			version = version[:i]
+		}
+		return version
+	}
+	return ""
+}
+
+// compareVersions compara versões numéricas por segmento ("2.10" > "2.9")
+// Segmentos ausentes valem 0 e segmentos não numéricos são comparados como texto
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		x, y := "0", "0"
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+
+		xn, errX := strconv.Atoi(x)
+		yn, errY := strconv.Atoi(y)
+		switch {
+		case errX == nil && errY == nil:
+			if xn != yn {
+				if xn < yn {
+					return -1
+				}
+				return 1
+			}
+		case x != y:
+			return strings.Compare(x, y)
+		}
+	}
+	return 0
+}
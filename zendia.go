@@ -1,7 +1,13 @@
 package zendia
 
 import (
+	"fmt"
+	"log"
 	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,32 +19,188 @@ type Zendia struct {
 	validator          *Validator
 	errorHandler       ErrorHandler
 	firebaseAuthConfig *FirebaseAuthConfig
+	uploadConfig       UploadConfig
+	startTime          time.Time
+	stripSlash         bool
+	jsonSerializer     JSONSerializer
+	corsGroups         []string // prefixos de grupos com CORS próprio
+	tenantExtractor    TenantExtractor
+	tenantInstalled    bool
+	panicHandler       PanicHandler
+	envelope           ResponseEnvelopeConfig
+	services           serviceRegistry
+	features           map[string]bool // recursos habilitados, expostos em StartupInfo
+	devMode            bool            // inclui causa e stack trace nas respostas 500
+	publicRoutes       []string        // prefixos liberados de autenticação
+	pageLimits         PageLimits      // take padrão e máximo de handlers e repositories
+}
+
+// PanicHandler recebe panics recuperados para reportar a um error tracker (Sentry, Rollbar...)
+// c carrega o context da requisição, então tenant, usuário e trace podem ser anexados
+type PanicHandler func(c *gin.Context, recovered interface{}, stack []byte)
+
+// Options configuração da instância criada por NewWithOptions
+type Options struct {
+	TenantExtractor TenantExtractor // Extrator de tenant (nil: nenhum middleware de tenant)
+	Mode            string          // Modo do gin: gin.DebugMode, gin.ReleaseMode (padrão) ou gin.TestMode
+	TrustedProxies  []string        // Proxies confiáveis para c.ClientIP() (nil: nenhum)
+	DevMode         bool            // Causa e stack trace nas respostas 500 (ver SetDevMode)
+	DefaultPageSize int             // Take sem ?take= (padrão: DefaultPageSize, ver SetPageLimits)
+	MaxPageSize     int             // Maior take aceito (padrão: DefaultMaxPageSize)
+}
+
+// JSONSerializer interface para trocar o encoder JSON das respostas
+// É compatível com sonic.ConfigStd e jsoniter.ConfigCompatibleWithStandardLibrary
+type JSONSerializer interface {
+	Marshal(v interface{}) ([]byte, error)
 }
 
 // New cria uma nova instância do framework
+// Nenhum proxy é confiável por padrão: c.ClientIP() usa o IP da conexão (ver SetTrustedProxies)
 func New() *Zendia {
 	gin.SetMode(gin.ReleaseMode)
+	return NewWithEngine(newEngine())
+}
+
+// newEngine gin.Engine padrão do framework, sem confiar em headers de proxy
+func newEngine() *gin.Engine {
 	engine := gin.New()
-	
+	_ = engine.SetTrustedProxies(nil) // nil nunca retorna erro
+	return engine
+}
+
+// NewWithEngine cria a instância sobre um gin.Engine já configurado
+// (MaxMultipartMemory, trusted proxies, etc.). O modo do gin e os proxies do engine
+// não são alterados; os middlewares padrão do framework são adicionados ao engine
+func NewWithEngine(engine *gin.Engine) *Zendia {
 	z := &Zendia{
 		engine:       engine,
 		middlewares:  []gin.HandlerFunc{},
 		validator:    NewValidator(),
 		errorHandler: NewErrorHandler(),
+		uploadConfig: DefaultUploadConfig,
+		envelope:     DefaultResponseEnvelope,
+		features:     make(map[string]bool),
+		publicRoutes: append([]string(nil), DefaultPublicRoutes...),
+		pageLimits:   DefaultPageLimits,
+		startTime:    time.Now(),
 	}
 	
 	// Middlewares padrão
-	z.engine.Use(gin.Recovery())
+	z.engine.Use(gin.CustomRecovery(z.recoverPanic))
 
 	// Injeta instância do Zendia no context pra o Handle acessar
+	// e os limites de página no context.Context, lidos pelos repositories
 	z.engine.Use(func(c *gin.Context) {
 		c.Set("zendia_instance", z)
+		if z.pageLimits != DefaultPageLimits {
+			c.Request = c.Request.WithContext(WithPageLimits(c.Request.Context(), z.pageLimits))
+		}
 		c.Next()
 	})
 	
 	return z
 }
 
+// NewWithOptions cria a instância já com as opções aplicadas
+//
+// Uso:
+//
+//	app := zendia.NewWithOptions(zendia.Options{TenantExtractor: zendia.DefaultTenantExtractor})
+func NewWithOptions(opts Options) *Zendia {
+	mode := opts.Mode
+	if mode == "" {
+		mode = gin.ReleaseMode
+	}
+	gin.SetMode(mode)
+
+	z := NewWithEngine(newEngine())
+	if opts.TenantExtractor != nil {
+		z.SetTenantExtractor(opts.TenantExtractor)
+	}
+	if opts.TrustedProxies != nil {
+		if err := z.SetTrustedProxies(opts.TrustedProxies); err != nil {
+			log.Printf("[ZENDIA] invalid trusted proxies: %v", err)
+		}
+	}
+	if opts.DevMode {
+		z.SetDevMode(true)
+	}
+	if opts.DefaultPageSize > 0 || opts.MaxPageSize > 0 {
+		z.SetPageLimits(opts.DefaultPageSize, opts.MaxPageSize)
+	}
+	return z
+}
+
+// SetPageLimits define o take padrão e o máximo de toda a instância
+// Vale para PaginationMiddleware, RegisterCRUD e para GetAllSkipTake/GetAfter dos
+// repositories chamados com o ctx da requisição: o handler responde 400 acima do máximo
+// e o repository reduz ao máximo. Valores <= 0 mantêm DefaultPageSize/DefaultMaxPageSize.
+//
+//	app.SetPageLimits(20, 100)   // API pública
+//	admin.SetPageLimits(50, 5000) // ferramenta interna
+func (z *Zendia) SetPageLimits(defaultSize, maxSize int) {
+	if defaultSize <= 0 {
+		defaultSize = DefaultPageSize
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultMaxPageSize
+	}
+	if defaultSize > maxSize {
+		defaultSize = maxSize
+	}
+	z.pageLimits = PageLimits{Default: defaultSize, Max: maxSize}
+}
+
+// SetTrustedProxies define os proxies cujos headers X-Forwarded-For / X-Real-IP são aceitos
+// New não confia em nenhum proxy; atrás de um load balancer informe os IPs/CIDRs dele,
+// senão c.ClientIP() retorna o IP do balancer. Nunca confie em todos ("0.0.0.0/0"):
+// o cliente forjaria o próprio IP com um X-Forwarded-For (rate limit, logs, auditoria)
+func (z *Zendia) SetTrustedProxies(proxies []string) error {
+	return z.engine.SetTrustedProxies(proxies)
+}
+
+// OnPanic registra o hook chamado em panics antes da resposta 500 ser escrita
+func (z *Zendia) OnPanic(handler PanicHandler) {
+	z.panicHandler = handler
+}
+
+// recoverPanic reporta o panic ao hook e responde 500 no envelope padrão
+// Um panic no próprio hook é descartado para não impedir a resposta
+func (z *Zendia) recoverPanic(c *gin.Context, recovered interface{}) {
+	if z.panicHandler != nil {
+		stack := debug.Stack()
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("[ZENDIA] panic handler failed: %v", r)
+				}
+			}()
+			z.panicHandler(c, recovered, stack)
+		}()
+	}
+
+	apiErr := NewInternalError(MsgInternalServerError)
+	body := errorEnvelope(c, apiErr)
+	if z.devMode {
+		addDevDetails(body, z.envelope.Error, fmt.Errorf("panic: %v", recovered))
+	}
+	c.AbortWithStatusJSON(apiErr.Code, body)
+}
+
+// hasGroupCORS indica se a rota pertence a um grupo com CORS próprio
+func (z *Zendia) hasGroupCORS(fullPath string) bool {
+	if fullPath == "" {
+		return false
+	}
+	for _, prefix := range z.corsGroups {
+		if prefix == "" || fullPath == prefix || strings.HasPrefix(fullPath, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
 // Use adiciona middleware global
 func (z *Zendia) Use(middleware ...gin.HandlerFunc) {
 	z.middlewares = append(z.middlewares, middleware...)
@@ -80,15 +242,65 @@ func (z *Zendia) PATCH(relativePath string, handlers ...gin.HandlerFunc) {
 }
 
 // Run inicia o servidor
+// Sem endereço usa a variável PORT ou DefaultPort
 func (z *Zendia) Run(addr ...string) error {
-	return z.engine.Run(addr...)
+	address := DefaultPort
+	if len(addr) > 0 {
+		address = addr[0]
+	} else if port := os.Getenv("PORT"); port != "" {
+		address = ":" + port
+	}
+	return http.ListenAndServe(address, z)
 }
 
 // ServeHTTP implementa http.Handler
 func (z *Zendia) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if z.stripSlash {
+		stripTrailingSlash(req)
+	}
 	z.engine.ServeHTTP(w, req)
 }
 
+// SetRedirectTrailingSlash controla o redirect automático do gin entre /users/ e /users
+// GET é redirecionado com 301 e os demais métodos (POST, PUT...) com 307, que
+// preserva método e body mas exige que o cliente siga o redirect. Padrão: habilitado
+func (z *Zendia) SetRedirectTrailingSlash(enabled bool) {
+	z.engine.RedirectTrailingSlash = enabled
+}
+
+// SetStripTrailingSlash remove a barra final do path antes do roteamento
+// Diferente do redirect, não há ida e volta ao cliente, então funciona igual
+// para POST/PUT/PATCH. Com isso habilitado registre as rotas sem barra final
+// (ex: users.GET("") em vez de users.GET("/")); o redirect do gin é desligado
+// para evitar loop entre as duas formas
+func (z *Zendia) SetStripTrailingSlash(enabled bool) {
+	z.stripSlash = enabled
+	if enabled {
+		z.engine.RedirectTrailingSlash = false
+	}
+}
+
+// stripTrailingSlash remove a barra final do path da requisição (exceto "/")
+func stripTrailingSlash(req *http.Request) {
+	path := req.URL.Path
+	if len(path) <= 1 || !strings.HasSuffix(path, "/") {
+		return
+	}
+	req.URL.Path = strings.TrimRight(path, "/")
+	if req.URL.Path == "" {
+		req.URL.Path = "/"
+	}
+	if req.URL.RawPath != "" {
+		req.URL.RawPath = strings.TrimRight(req.URL.RawPath, "/")
+	}
+}
+
+// SetJSONSerializer troca o encoder usado por Success, Created, Updated e Fail
+// Passar nil volta para o encoding/json padrão
+func (z *Zendia) SetJSONSerializer(serializer JSONSerializer) {
+	z.jsonSerializer = serializer
+}
+
 // GetValidator retorna o validador
 func (z *Zendia) GetValidator() *Validator {
 	return z.validator
@@ -99,8 +311,20 @@ func (z *Zendia) GetErrorHandler() ErrorHandler {
 	return z.errorHandler
 }
 
-// SetTenantExtractor configura um extrator customizado de tenant
+// SetTenantExtractor configura o extrator de tenant da aplicação
+// Chamadas seguintes substituem o extrator anterior em vez de empilhar outro middleware;
+// nil desliga a extração. Configure antes de Run
 func (z *Zendia) SetTenantExtractor(extractor TenantExtractor) {
-	// Remove o middleware padrão e adiciona o customizado
-	z.Use(TenantMiddleware(extractor))
+	z.tenantExtractor = extractor
+	if z.tenantInstalled || extractor == nil {
+		return
+	}
+
+	z.tenantInstalled = true
+	z.Use(func(c *gin.Context) {
+		if extractor := z.tenantExtractor; extractor != nil {
+			applyTenant(c, extractor(c))
+		}
+		c.Next()
+	})
 }
\ No newline at end of file
@@ -2,11 +2,23 @@ package zendia
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"log/slog"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/textproto"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -144,3 +156,2619 @@ func TestMiddleware_CORS(t *testing.T) {
 
 	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
 }
+
+func TestContext_ParamUUID(t *testing.T) {
+	app := New()
+
+	app.GET("/users/:id", Handle(func(c *Context[any]) error {
+		id, err := c.ParamUUID("id")
+		if err != nil {
+			return err
+		}
+		c.Success("Message Teste: ", id.String())
+		return nil
+	}))
+
+	id := "7f1c2b1e-8d4a-4c3e-9a8b-2f6d5e4c3b2a"
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/users/"+id, nil)
+	app.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, id, response["data"])
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/users/not-a-uuid", nil)
+	app.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestContext_ParamInt(t *testing.T) {
+	app := New()
+
+	app.GET("/pages/:n", Handle(func(c *Context[any]) error {
+		n, err := c.ParamInt("n")
+		if err != nil {
+			return err
+		}
+		c.Success("Message Teste: ", n)
+		return nil
+	}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/pages/42", nil)
+	app.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/pages/abc", nil)
+	app.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestContext_BindAll(t *testing.T) {
+	app := New()
+
+	type UpdateRequest struct {
+		ID     string `uri:"id" json:"id" validate:"required"`
+		Notify bool   `form:"notify" json:"notify"`
+		Name   string `json:"name" validate:"required"`
+	}
+
+	app.PUT("/users/:id", Handle(func(c *Context[UpdateRequest]) error {
+		var req UpdateRequest
+		if err := c.BindAll(&req); err != nil {
+			return err
+		}
+		c.Updated("Message Teste: ", req)
+		return nil
+	}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/users/abc?notify=true", bytes.NewBufferString(`{"name":"João"}`))
+	req.Header.Set("Content-Type", "application/json")
+	app.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, "abc", data["id"])
+	assert.Equal(t, true, data["notify"])
+	assert.Equal(t, "João", data["name"])
+
+	// Sem body JSON a validação final falha
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("PUT", "/users/abc", nil)
+	app.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Campo binding:"required" que só chega no JSON não falha nas fontes anteriores
+	type ScopedRequest struct {
+		Tenant string `uri:"tenant" json:"tenant" binding:"required"`
+		Page   int    `form:"page" json:"page"`
+		Owner  string `json:"owner" binding:"required"`
+	}
+
+	app.POST("/tenants/:tenant/items", Handle(func(c *Context[ScopedRequest]) error {
+		var req ScopedRequest
+		if err := c.BindAll(&req); err != nil {
+			return err
+		}
+		c.Created("Message Teste: ", req)
+		return nil
+	}))
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/tenants/acme/items?page=2", bytes.NewBufferString(`{"owner":"ana"}`))
+	req.Header.Set("Content-Type", "application/json")
+	app.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	response = nil
+	json.Unmarshal(w.Body.Bytes(), &response)
+	data = response["data"].(map[string]interface{})
+	assert.Equal(t, "acme", data["tenant"])
+	assert.Equal(t, float64(2), data["page"])
+	assert.Equal(t, "ana", data["owner"])
+
+	// Sem o owner, a validação final ainda rejeita
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/tenants/acme/items", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	app.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestContext_FormFile(t *testing.T) {
+	app := New()
+	app.SetUploadConfig(UploadConfig{
+		MaxFileSize:         16,
+		AllowedContentTypes: []string{"image/png"},
+	})
+
+	app.POST("/upload", Handle(func(c *Context[any]) error {
+		file, err := c.FormFile("avatar")
+		if err != nil {
+			return err
+		}
+		c.Created("Message Teste: ", file.Filename)
+		return nil
+	}))
+
+	newRequest := func(contentType string, content []byte) *http.Request {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", `form-data; name="avatar"; filename="avatar.png"`)
+		header.Set("Content-Type", contentType)
+		part, _ := writer.CreatePart(header)
+		part.Write(content)
+		writer.Close()
+
+		req, _ := http.NewRequest("POST", "/upload", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		return req
+	}
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, newRequest("image/png", []byte("png")))
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// Content-Type fora da allowlist
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, newRequest("application/pdf", []byte("pdf")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Arquivo maior que o limite
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, newRequest("image/png", bytes.Repeat([]byte("x"), 32)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestZendia_AddInfoEndpoint(t *testing.T) {
+	app := New()
+	app.AddInfoEndpoint(BuildInfo{Version: "1.2.3", GitCommit: "abc123"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/info", nil)
+	app.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, "1.2.3", data["version"])
+	assert.Equal(t, "abc123", data["git_commit"])
+	assert.Equal(t, runtime.Version(), data["go_version"])
+	assert.NotEmpty(t, data["uptime"])
+}
+
+func TestZendia_ShowBanner(t *testing.T) {
+	app := New()
+
+	var buf bytes.Buffer
+	app.ShowBanner(BannerConfig{AppName: "Teste", Version: "1.0.0", Port: ":8080", Output: &buf})
+
+	// Saída que não é terminal não recebe cores ANSI
+	assert.Contains(t, buf.String(), "Teste")
+	assert.NotContains(t, buf.String(), "\033[")
+
+	buf.Reset()
+	app.ShowBanner(BannerConfig{AppName: "Teste", Output: &buf, Disable: true})
+	assert.Empty(t, buf.String())
+}
+
+func TestZendia_StartupInfo(t *testing.T) {
+	app := New()
+	app.SetTenantExtractor(HeaderTenantExtractor(nil))
+	app.AddMonitoring()
+	app.AddHealthEndpoint(NewHealthManager())
+	app.GET("/ping", Handle(func(c *Context[any]) error { return nil }))
+
+	config := BannerConfig{AppName: "Teste", Port: ":8080"}
+	info := app.StartupInfo(config)
+
+	assert.Equal(t, "Teste", info.AppName)
+	assert.Equal(t, BuildVersion, info.Version)
+	assert.Equal(t, ":8080", info.Port)
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+	assert.Equal(t, 2, info.RouteCount)
+	assert.Equal(t, []string{FeatureHealth, FeatureMonitoring, FeatureTenant}, info.Features)
+
+	// Banner e resumo saem dos mesmos dados
+	var buf bytes.Buffer
+	config.Output = &buf
+	app.ShowBanner(config)
+	assert.Contains(t, buf.String(), "health, monitoring, tenant")
+
+	body, err := json.Marshal(info)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), `"route_count":2`)
+}
+
+func TestMiddleware_RequireContentType(t *testing.T) {
+	app := New()
+	app.Use(RequireContentType("application/json"))
+
+	app.POST("/test", Handle(func(c *Context[any]) error {
+		c.Created("Message Teste: ", "ok")
+		return nil
+	}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/test", bytes.NewBufferString("name=test"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	app.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.False(t, response["success"].(bool))
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/test", bytes.NewBufferString(`{"name":"test"}`))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	app.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestZendia_TrailingSlash(t *testing.T) {
+	handler := Handle(func(c *Context[any]) error {
+		c.Success("Message Teste: ", "ok")
+		return nil
+	})
+
+	// Redirect padrão do gin
+	app := New()
+	app.GET("/users", handler)
+	app.POST("/users", handler)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/users/", nil)
+	app.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/users/", nil)
+	app.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+
+	// Sem redirect a rota com barra não existe
+	app.SetRedirectTrailingSlash(false)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/users/", nil)
+	app.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Strip reescreve o path antes do roteamento
+	app = New()
+	app.SetStripTrailingSlash(true)
+	app.GET("/users", handler)
+	app.POST("/users", handler)
+
+	for _, method := range []string{"GET", "POST"} {
+		for _, path := range []string{"/users", "/users/"} {
+			w = httptest.NewRecorder()
+			req, _ = http.NewRequest(method, path, nil)
+			app.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code, method+" "+path)
+		}
+	}
+}
+
+func TestGetJSON_RouteMiddleware(t *testing.T) {
+	app := New()
+	api := app.Group("/api")
+
+	setRole := func(c *gin.Context) {
+		c.Set(AuthRoleKey, c.GetHeader("X-Role"))
+		c.Next()
+	}
+
+	GetJSON(api, "/admin", func(c *Context[any]) error {
+		c.Success("Message Teste: ", "admin area")
+		return nil
+	}, setRole, RequireRole("admin"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/admin", nil)
+	req.Header.Set("X-Role", "admin")
+	app.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/admin", nil)
+	req.Header.Set("X-Role", "viewer")
+	app.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Role vazia é negada mesmo com "" entre as roles permitidas
+	GetJSON(api, "/loose", func(c *Context[any]) error {
+		c.Success("Message Teste: ", "loose area")
+		return nil
+	}, setRole, RequireRole("admin", ""))
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/loose", nil)
+	app.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestSchemaFor(t *testing.T) {
+	type Address struct {
+		City string `json:"city" validate:"required"`
+	}
+	type CreateUser struct {
+		Name    string   `json:"name" validate:"required,min=2,max=50"`
+		Email   string   `json:"email" validate:"required,email"`
+		Age     int      `json:"age" validate:"gte=0,lte=150"`
+		Role    string   `json:"role" validate:"oneof=admin user"`
+		Tags    []string `json:"tags" validate:"max=5"`
+		Address Address  `json:"address"`
+		Secret  string   `json:"-"`
+	}
+
+	schema := SchemaFor[CreateUser]()
+	assert.Equal(t, "object", schema["type"])
+	assert.ElementsMatch(t, []string{"name", "email"}, schema["required"])
+
+	props := schema["properties"].(map[string]interface{})
+	assert.NotContains(t, props, "Secret")
+
+	name := props["name"].(map[string]interface{})
+	assert.Equal(t, "string", name["type"])
+	assert.Equal(t, 2.0, name["minLength"])
+	assert.Equal(t, 50.0, name["maxLength"])
+
+	assert.Equal(t, "email", props["email"].(map[string]interface{})["format"])
+
+	age := props["age"].(map[string]interface{})
+	assert.Equal(t, "integer", age["type"])
+	assert.Equal(t, 0.0, age["minimum"])
+	assert.Equal(t, 150.0, age["maximum"])
+
+	assert.Equal(t, []interface{}{"admin", "user"}, props["role"].(map[string]interface{})["enum"])
+	assert.Equal(t, 5.0, props["tags"].(map[string]interface{})["maxItems"])
+
+	address := props["address"].(map[string]interface{})
+	assert.Equal(t, []string{"city"}, address["required"])
+}
+
+func TestSchemaFor_DiveAndQuotedOneof(t *testing.T) {
+	type Payload struct {
+		Tags   []string          `json:"tags" validate:"required,max=5,dive,min=2,max=10"`
+		Labels map[string]string `json:"labels" validate:"dive,keys,min=1,endkeys,oneof=red green"`
+		Status string            `json:"status" validate:"oneof='in progress' done"`
+	}
+
+	props := SchemaFor[Payload]()["properties"].(map[string]interface{})
+
+	// Regras antes do dive valem para a lista; depois, para cada item
+	tags := props["tags"].(map[string]interface{})
+	assert.Equal(t, 5.0, tags["maxItems"])
+	assert.NotContains(t, tags, "minItems")
+	items := tags["items"].(map[string]interface{})
+	assert.Equal(t, 2.0, items["minLength"])
+	assert.Equal(t, 10.0, items["maxLength"])
+
+	labels := props["labels"].(map[string]interface{})
+	assert.NotContains(t, labels, "minProperties")
+	assert.Equal(t, []interface{}{"red", "green"}, labels["additionalProperties"].(map[string]interface{})["enum"])
+
+	assert.Equal(t, []interface{}{"in progress", "done"}, props["status"].(map[string]interface{})["enum"])
+}
+
+func TestContext_Pagination(t *testing.T) {
+	app := New()
+
+	app.GET("/users", Handle(func(c *Context[any]) error {
+		skip, take, err := c.Pagination(10, 100)
+		if err != nil {
+			return err
+		}
+		c.Success("Message Teste: ", map[string]int{"skip": skip, "take": take})
+		return nil
+	}))
+
+	tests := []struct {
+		query string
+		code  int
+		skip  float64
+		take  float64
+	}{
+		{"", http.StatusOK, 0, 10},
+		{"?skip=20&take=50", http.StatusOK, 20, 50},
+		{"?take=100", http.StatusOK, 0, 100},
+		{"?take=101", http.StatusBadRequest, 0, 0},
+		{"?take=0", http.StatusBadRequest, 0, 0},
+		{"?skip=-1", http.StatusBadRequest, 0, 0},
+		{"?skip=abc", http.StatusBadRequest, 0, 0},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/users"+tt.query, nil)
+		app.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, tt.query)
+		if tt.code == http.StatusOK {
+			var response map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &response)
+			data := response["data"].(map[string]interface{})
+			assert.Equal(t, tt.skip, data["skip"], tt.query)
+			assert.Equal(t, tt.take, data["take"], tt.query)
+		}
+	}
+}
+
+// countingSerializer serializer de teste que conta as chamadas
+type countingSerializer struct {
+	calls int
+}
+
+func (s *countingSerializer) Marshal(v interface{}) ([]byte, error) {
+	s.calls++
+	return json.Marshal(v)
+}
+
+func TestZendia_SetJSONSerializer(t *testing.T) {
+	app := New()
+	serializer := &countingSerializer{}
+	app.SetJSONSerializer(serializer)
+
+	app.GET("/test", Handle(func(c *Context[any]) error {
+		c.Success("Message Teste: ", "custom serializer")
+		return nil
+	}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	app.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, serializer.calls)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "custom serializer", response["data"])
+}
+
+// benchmarkSuccessList mede a serialização de uma lista de 1000 itens
+// Para comparar com sonic/jsoniter, passe o serializer correspondente
+func benchmarkSuccessList(b *testing.B, serializer JSONSerializer) {
+	type item struct {
+		ID    int    `json:"id"`
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+	items := make([]item, 1000)
+	for i := range items {
+		items[i] = item{ID: i, Name: "João da Silva", Email: "joao@example.com"}
+	}
+
+	app := New()
+	app.SetJSONSerializer(serializer)
+	app.GET("/items", Handle(func(c *Context[any]) error {
+		c.Success("Message Teste: ", items)
+		return nil
+	}))
+
+	req, _ := http.NewRequest("GET", "/items", nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		app.ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
+
+func BenchmarkSuccess_DefaultSerializer(b *testing.B) {
+	benchmarkSuccessList(b, nil)
+}
+
+func TestContext_StreamJSON(t *testing.T) {
+	app := New()
+
+	app.GET("/export", Handle(func(c *Context[any]) error {
+		items := make(chan interface{})
+		go func() {
+			defer close(items)
+			for i := 0; i < 250; i++ {
+				items <- map[string]int{"id": i}
+			}
+		}()
+		return c.StreamJSON(items)
+	}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/export", nil)
+	app.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var items []map[string]int
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &items))
+	assert.Len(t, items, 250)
+	assert.Equal(t, 249, items[249]["id"])
+}
+
+func TestHandle_ContextCanceledReturns499(t *testing.T) {
+	app := New()
+
+	app.GET("/raw", Handle(func(c *Context[any]) error {
+		return context.Canceled
+	}))
+	app.GET("/repo", Handle(func(c *Context[any]) error {
+		return NewClientClosedRequestError(MsgRequestCanceled)
+	}))
+
+	for _, path := range []string{"/raw", "/repo"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		app.ServeHTTP(w, req)
+
+		assert.Equal(t, StatusClientClosedRequest, w.Code, path)
+		assert.Contains(t, w.Body.String(), MsgRequestCanceled, path)
+	}
+}
+
+type stubHealthCheck struct {
+	name   string
+	status HealthStatus
+	calls  int
+}
+
+func (s *stubHealthCheck) Name() string { return s.name }
+
+func (s *stubHealthCheck) Check(ctx context.Context) HealthCheckResult {
+	s.calls++
+	return HealthCheckResult{Status: s.status}
+}
+
+func TestHealthMetrics_Percentiles(t *testing.T) {
+	hm := NewHealthMetrics(10)
+
+	// 15 execuções: só as 10 últimas (6ms..15ms) ficam na janela
+	for i := 1; i <= 15; i++ {
+		status := HealthStatusUp
+		if i == 15 {
+			status = HealthStatusDown
+		}
+		hm.RecordHealthCheck("mongodb", time.Duration(i)*time.Millisecond, status)
+	}
+
+	stats := hm.Stats()["mongodb"]
+	assert.Equal(t, 10, stats.Samples)
+	assert.Equal(t, 1, stats.Failures)
+	assert.Equal(t, 10.5, stats.AvgMs)
+	assert.Equal(t, 10.0, stats.P50Ms)
+	assert.Equal(t, 15.0, stats.P95Ms)
+	assert.Equal(t, 15.0, stats.MaxMs)
+	assert.Equal(t, HealthStatusDown, stats.LastStatus)
+}
+
+func TestHealthManager_MetricsSinks(t *testing.T) {
+	manager := NewHealthManager()
+	manager.AddCheck(&stubHealthCheck{name: "cache", status: HealthStatusDown})
+
+	assert.Nil(t, manager.Metrics())
+
+	manager.EnableMetrics(0)
+	metrics := NewMetrics()
+	manager.AddMetricsSink(metrics)
+
+	manager.CheckHealth(context.Background())
+	manager.CheckHealth(context.Background())
+
+	stats := manager.Metrics()["cache"]
+	assert.Equal(t, 2, stats.Samples)
+	assert.Equal(t, 2, stats.Failures)
+
+	all := metrics.GetStats()
+	health := all["health"].(map[string]HealthCheckStats)["cache"]
+	assert.Equal(t, 2, health.Samples)
+	assert.Equal(t, 2, health.Failures)
+
+	// Health checks não entram nas estatísticas HTTP
+	assert.Empty(t, all["endpoints"])
+	assert.Equal(t, int64(0), all["total_requests"])
+	assert.Equal(t, 0.0, all["error_rate"])
+}
+
+func TestHealthManager_DependsOn(t *testing.T) {
+	db := &stubHealthCheck{name: "mongodb", status: HealthStatusDown}
+	repo := &stubHealthCheck{name: "users_repo", status: HealthStatusUp}
+	cache := &stubHealthCheck{name: "cache", status: HealthStatusUp}
+
+	manager := NewHealthManager()
+	manager.AddCheck(repo, "mongodb")
+	manager.AddCheck(db)
+	manager.AddCheck(cache, "unknown")
+
+	report := manager.Report(context.Background())
+	results := report.Checks
+
+	assert.Equal(t, HealthStatusDown, report.Status)
+	assert.Equal(t, 1, db.calls)
+	assert.Equal(t, 0, repo.calls)
+	assert.True(t, results["users_repo"].Skipped)
+	assert.Equal(t, HealthStatusDown, results["users_repo"].Status)
+	assert.Equal(t, map[string]HealthStatus{"mongodb": HealthStatusDown}, results["users_repo"].Dependencies)
+
+	// Dependência não registrada é ignorada
+	assert.Equal(t, 1, cache.calls)
+	assert.Equal(t, HealthStatusUp, results["cache"].Status)
+
+	// Com a dependência UP o check volta a ser executado
+	db.status = HealthStatusUp
+	report = manager.Report(context.Background())
+	results = report.Checks
+
+	assert.Equal(t, HealthStatusUp, report.Status)
+	assert.Equal(t, 1, repo.calls)
+	assert.False(t, results["users_repo"].Skipped)
+	assert.Equal(t, map[string]HealthStatus{"mongodb": HealthStatusUp}, results["users_repo"].Dependencies)
+}
+
+func TestHealthManager_ReportEndpoint(t *testing.T) {
+	db := &stubHealthCheck{name: "mongodb", status: HealthStatusUp}
+	manager := NewHealthManager()
+	manager.AddCheck(db)
+
+	app := New()
+	app.AddHealthEndpoint(manager)
+
+	get := func() (int, []byte) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/health", nil)
+		app.ServeHTTP(w, req)
+		return w.Code, w.Body.Bytes()
+	}
+
+	status, raw := get()
+	var ok struct {
+		Data HealthReport `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(raw, &ok))
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, HealthStatusUp, ok.Data.Status)
+	assert.Equal(t, HealthStatusUp, ok.Data.Checks["mongodb"].Status)
+	assert.False(t, ok.Data.Timestamp.IsZero())
+
+	db.status = HealthStatusDown
+	status, raw = get()
+	var down HealthReport
+	assert.NoError(t, json.Unmarshal(raw, &down))
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, HealthStatusDown, down.Status)
+
+	// CheckHealth continua retornando o map para compatibilidade
+	legacy := manager.CheckHealth(context.Background())
+	assert.Equal(t, HealthStatusDown, legacy["status"])
+	assert.Contains(t, legacy["checks"], "mongodb")
+}
+
+func TestHealthManager_NamedEndpointsAndProbes(t *testing.T) {
+	db := &stubHealthCheck{name: "mongodb", status: HealthStatusDown}
+	cache := &stubHealthCheck{name: "cache", status: HealthStatusUp}
+	manager := NewHealthManager()
+	manager.AddCheck(db)
+	manager.AddCheck(cache)
+
+	app := New()
+	app.AddNamedHealthEndpoint(manager, "/health/db", "mongodb")
+	app.AddNamedHealthEndpoint(manager, "/health/cache", "cache")
+	app.AddNamedHealthEndpoint(manager, "/health/typo", "mongo")
+	app.AddProbeEndpoints(manager, "cache")
+
+	get := func(path string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		app.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, get("/health/cache"))
+	assert.Equal(t, 0, db.calls)
+	assert.Equal(t, http.StatusServiceUnavailable, get("/health/db"))
+	assert.Equal(t, 1, db.calls)
+	assert.Equal(t, http.StatusServiceUnavailable, get("/health/typo"))
+
+	// live não executa checks; ready só os informados
+	cache.calls, db.calls = 0, 0
+	assert.Equal(t, http.StatusOK, get(RouteHealthLive))
+	assert.Equal(t, 0, cache.calls)
+	assert.Equal(t, http.StatusOK, get(RouteHealthReady))
+	assert.Equal(t, 1, cache.calls)
+	assert.Equal(t, 0, db.calls)
+
+	report := manager.ReportFor(context.Background(), "cache")
+	assert.Len(t, report.Checks, 1)
+	assert.Equal(t, HealthStatusUp, report.Status)
+	assert.Equal(t, HealthStatusDown, manager.Report(context.Background()).Status)
+}
+
+func TestHTTPHealthCheck_Retries(t *testing.T) {
+	var requests int32
+	failures := int32(1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= atomic.LoadInt32(&failures) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+
+	// Sem retries uma falha pontual já é DOWN
+	single := NewHTTPHealthCheck("billing", server.URL, time.Second)
+	assert.Equal(t, HealthStatusDown, single.Check(ctx).Status)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	// Com retries a falha pontual seguida de sucesso vira WARN
+	atomic.StoreInt32(&requests, 0)
+	check := NewHTTPHealthCheck("billing", server.URL, time.Second, WithHTTPRetries(2, time.Millisecond))
+	result := check.Check(ctx)
+	assert.Equal(t, HealthStatusWarn, result.Status)
+	assert.Contains(t, result.Message, "2 attempts")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+	// Sem falhas continua UP
+	atomic.StoreInt32(&requests, 0)
+	atomic.StoreInt32(&failures, 0)
+	assert.Equal(t, HealthStatusUp, check.Check(ctx).Status)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	// DOWN só depois de todas as tentativas falharem
+	atomic.StoreInt32(&requests, 0)
+	atomic.StoreInt32(&failures, 10)
+	result = check.Check(ctx)
+	assert.Equal(t, HealthStatusDown, result.Status)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+	assert.Equal(t, 3, result.Details.(map[string]interface{})["max_attempts"])
+}
+
+func TestHTTPHealthCheck_CircuitBreaker(t *testing.T) {
+	var requests int32
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	breaker := NewCircuitBreaker(2, time.Minute)
+	breaker.now = func() time.Time { return now }
+	check := NewHTTPHealthCheck("billing", server.URL, time.Second, WithHTTPCircuitBreaker(breaker))
+	ctx := context.Background()
+
+	// Duas falhas seguidas abrem o circuito
+	check.Check(ctx)
+	result := check.Check(ctx)
+	assert.Equal(t, HealthStatusDown, result.Status)
+	assert.Equal(t, CircuitOpen, result.Details.(map[string]interface{})["circuit_state"])
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+	// Aberto: DOWN sem chamar o serviço
+	result = check.Check(ctx)
+	assert.Equal(t, HealthStatusDown, result.Status)
+	assert.Contains(t, result.Message, "Circuit breaker open")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+	// Depois do cooldown uma requisição de teste; falha reabre
+	now = now.Add(time.Minute)
+	check.Check(ctx)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+	assert.Equal(t, CircuitOpen, breaker.State())
+	check.Check(ctx)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+
+	// Teste bem-sucedido fecha o circuito
+	healthy.Store(true)
+	now = now.Add(time.Minute)
+	result = check.Check(ctx)
+	assert.Equal(t, HealthStatusUp, result.Status)
+	assert.Equal(t, CircuitClosed, result.Details.(map[string]interface{})["circuit_state"])
+	assert.Equal(t, 0, breaker.Failures())
+
+	// Half-open libera só uma chamada de teste por vez
+	breaker.Failure()
+	breaker.Failure()
+	now = now.Add(time.Minute)
+	assert.True(t, breaker.Allow())
+	assert.False(t, breaker.Allow())
+	assert.Equal(t, CircuitHalfOpen, breaker.State())
+	assert.ErrorIs(t, breaker.Execute(func() error { return nil }), ErrCircuitOpen)
+	breaker.Success()
+	assert.NoError(t, breaker.Execute(func() error { return nil }))
+}
+
+func TestMiddleware_CORSGroupOverride(t *testing.T) {
+	app := New()
+	app.Use(CORSWithConfig(CORSConfig{
+		AllowOrigins: []string{"https://site.example.com", "https://admin.example.com"},
+		MaxAge:       time.Hour,
+	}))
+
+	public := app.Group("/public")
+	public.GET("/items", Handle(func(c *Context[any]) error {
+		c.Success("ok", nil)
+		return nil
+	}))
+
+	admin := app.Group("/admin")
+	admin.CORS(CORSConfig{
+		AllowOrigins: []string{"https://admin.example.com"},
+		MaxAge:       10 * time.Minute,
+	})
+	admin.GET("/users", Handle(func(c *Context[any]) error {
+		c.Success("ok", nil)
+		return nil
+	}))
+
+	preflight := func(path, origin string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("OPTIONS", path, nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", "GET")
+		app.ServeHTTP(w, req)
+		return w
+	}
+
+	// Grupo público usa a configuração global
+	w := preflight("/public/items", "https://site.example.com")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://site.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "3600", w.Header().Get("Access-Control-Max-Age"))
+
+	// Grupo admin rejeita a origem que o público aceita
+	w = preflight("/admin/users", "https://site.example.com")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+
+	w = preflight("/admin/users", "https://admin.example.com")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://admin.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+
+	// Requisição simples: sem header de CORS para origem não permitida
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/admin/users", nil)
+	req.Header.Set("Origin", "https://site.example.com")
+	app.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestMiddleware_CORSCredentials(t *testing.T) {
+	newApp := func(origins ...string) *Zendia {
+		app := New()
+		app.Use(CORSWithConfig(CORSConfig{AllowOrigins: origins, AllowCredentials: true}))
+		app.GET("/me", Handle(func(c *Context[any]) error {
+			c.Success("ok", nil)
+			return nil
+		}))
+		return app
+	}
+	request := func(app *Zendia, method, origin string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, "/me", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		app.ServeHTTP(w, req)
+		return w
+	}
+
+	// Origem da allowlist: refletida com credenciais e Vary, nunca "*"
+	app := newApp("https://app.example.com")
+	for _, method := range []string{"GET", "OPTIONS"} {
+		w := request(app, method, "https://app.example.com")
+		assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+		assert.Contains(t, w.Header().Values("Vary"), "Origin")
+	}
+
+	// Origem fora da allowlist: sem headers de CORS, mas com Vary para não envenenar caches
+	w := request(app, "GET", "https://evil.example.com")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Contains(t, w.Header().Values("Vary"), "Origin")
+	w = request(app, "OPTIONS", "https://evil.example.com")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Header().Values("Vary"), "Origin")
+	assert.Contains(t, request(app, "GET", "").Header().Values("Vary"), "Origin")
+
+	// Wildcard sem credenciais não depende da origem: sem Vary
+	wildcard := New()
+	wildcard.Use(CORS("*"))
+	wildcard.GET("/me", Handle(func(c *Context[any]) error {
+		c.Success("ok", nil)
+		return nil
+	}))
+	w = request(wildcard, "GET", "https://any.example.com")
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.NotContains(t, w.Header().Values("Vary"), "Origin")
+
+	// "*" com credenciais não libera qualquer origem nem é emitido
+	app = newApp("*", "https://app.example.com")
+	w = request(app, "GET", "https://evil.example.com")
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	w = request(app, "GET", "*")
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	w = request(app, "GET", "")
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	w = request(app, "GET", "https://app.example.com")
+	assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestHandle_InternalErrorHidesDetails(t *testing.T) {
+	app := New()
+
+	cause := errors.New("connection(10.0.0.5:27017) incomplete read of message header")
+	app.GET("/repo", Handle(func(c *Context[any]) error {
+		return internalError("Failed to get entity", cause)
+	}))
+	app.GET("/raw", Handle(func(c *Context[any]) error {
+		return cause
+	}))
+
+	for _, path := range []string{"/repo", "/raw"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		app.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code, path)
+		assert.NotContains(t, w.Body.String(), "10.0.0.5", path)
+	}
+
+	err := internalError("Failed to get entity", cause)
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, "Failed to get entity", err.Error())
+
+	body, _ := json.Marshal(err)
+	assert.NotContains(t, string(body), "10.0.0.5")
+}
+
+func TestHandleR(t *testing.T) {
+	type userDTO struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	app := New()
+	app.GET("/users/:id", HandleR(func(c *Context[any]) (userDTO, error) {
+		if c.Param("id") != "42" {
+			return userDTO{}, NewNotFoundError("User not found")
+		}
+		return userDTO{ID: "42", Name: "Ana"}, nil
+	}, "Usuário encontrado"))
+	app.GET("/default", HandleR(func(c *Context[any]) (int, error) {
+		return 7, nil
+	}))
+	app.GET("/written", HandleR(func(c *Context[any]) (int, error) {
+		c.Created("Criado", 1)
+		return 2, nil
+	}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/users/42", nil)
+	app.ServeHTTP(w, req)
+
+	var body map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &body)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, true, body["success"])
+	assert.Equal(t, "Usuário encontrado", body["message"])
+	assert.Equal(t, map[string]interface{}{"id": "42", "name": "Ana"}, body["data"])
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/users/1", nil)
+	app.ServeHTTP(w, req)
+
+	body = nil
+	json.Unmarshal(w.Body.Bytes(), &body)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, false, body["success"])
+	assert.Equal(t, ErrorCodeNotFound, body["error_code"])
+	assert.Nil(t, body["data"])
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/default", nil)
+	app.ServeHTTP(w, req)
+
+	body = nil
+	json.Unmarshal(w.Body.Bytes(), &body)
+	assert.Equal(t, MsgSuccess, body["message"])
+	assert.Equal(t, float64(7), body["data"])
+
+	// O handler que já escreveu a resposta não recebe uma segunda escrita
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/written", nil)
+	app.ServeHTTP(w, req)
+
+	body = nil
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, float64(1), body["data"])
+}
+
+type tenantGreeter struct {
+	tenantID string
+}
+
+func TestServices_ProvideAndResolve(t *testing.T) {
+	app := New()
+	app.SetTenantExtractor(HeaderTenantExtractor(nil))
+
+	calls := 0
+	app.Provide("greeting", func(c *gin.Context) interface{} {
+		calls++
+		return "olá " + GetTenantIDFromGin(c)
+	})
+	ProvideService(app, func(c *gin.Context) *tenantGreeter {
+		return &tenantGreeter{tenantID: GetTenantIDFromGin(c)}
+	})
+
+	app.GET("/services", Handle(func(c *Context[any]) error {
+		first := c.Resolve("greeting")
+		second := c.Resolve("greeting")
+		greeter, ok := GetService[*tenantGreeter](c)
+		_, missing := GetService[*testEntity](c)
+
+		c.Success("ok", map[string]interface{}{
+			"greeting": first,
+			"same":     first == second,
+			"tenant":   greeter.tenantID,
+			"found":    ok,
+			"missing":  missing,
+			"unknown":  c.Resolve("unknown") == nil,
+		})
+		return nil
+	}))
+
+	for _, tenantID := range []string{"tenant-a", "tenant-b"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/services", nil)
+		req.Header.Set(HeaderTenantID, tenantID)
+		app.ServeHTTP(w, req)
+
+		var body map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &body)
+		data := body["data"].(map[string]interface{})
+		assert.Equal(t, "olá "+tenantID, data["greeting"])
+		assert.Equal(t, true, data["same"])
+		assert.Equal(t, tenantID, data["tenant"])
+		assert.Equal(t, true, data["found"])
+		assert.Equal(t, false, data["missing"])
+		assert.Equal(t, true, data["unknown"])
+	}
+
+	// Uma execução da factory por requisição
+	assert.Equal(t, 2, calls)
+}
+
+func TestPaginationMiddleware(t *testing.T) {
+	app := New()
+	app.GET("/users", PaginationMiddleware(PaginationConfig{
+		MaxTake:     50,
+		DefaultSort: "-created.set_at",
+		SortFields:  []string{"name", "created.set_at"},
+	}), Handle(func(c *Context[any]) error {
+		c.Success("ok", c.PageParams())
+		return nil
+	}))
+	app.GET("/free", PaginationMiddleware(PaginationConfig{}), Handle(func(c *Context[any]) error {
+		c.Success("ok", c.PageParams())
+		return nil
+	}))
+
+	get := func(url string) (int, PageParams) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", url, nil)
+		app.ServeHTTP(w, req)
+
+		var body struct {
+			Data PageParams `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &body)
+		return w.Code, body.Data
+	}
+
+	status, params := get("/users")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, PageParams{Skip: 0, Take: 10, Sort: []SortOption{{Field: "created.set_at", Direction: -1}}}, params)
+
+	status, params = get("/users?skip=20&take=50&sort=name,-created.set_at")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 20, params.Skip)
+	assert.Equal(t, 50, params.Take)
+	assert.Equal(t, []SortOption{{Field: "name", Direction: 1}, {Field: "created.set_at", Direction: -1}}, params.Sort)
+	assert.Equal(t, &QueryOptions{Order: Order{By: "name", At: 1}}, params.QueryOptions())
+
+	for _, url := range []string{
+		"/users?take=51",
+		"/users?skip=-1",
+		"/users?take=abc",
+		"/users?sort=password", // fora da allowlist
+		"/free?sort=$where",    // nome de campo inválido
+		"/free?sort=name,-a$b", // um campo inválido recusa tudo
+	} {
+		status, _ = get(url)
+		assert.Equal(t, http.StatusBadRequest, status, url)
+	}
+
+	// Sem allowlist qualquer nome de campo válido é aceito
+	status, params = get("/free?sort=-score")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []SortOption{{Field: "score", Direction: -1}}, params.Sort)
+	assert.Nil(t, PageParams{}.QueryOptions())
+}
+
+func TestSetTenantValidator(t *testing.T) {
+	app := New()
+	app.SetTenantExtractor(HeaderTenantExtractor(nil))
+
+	lookups := 0
+	tenants := app.SetTenantValidator(TenantValidatorFunc(func(ctx context.Context, tenantID string) (bool, error) {
+		lookups++
+		if tenantID == "broken" {
+			return false, errors.New("db down")
+		}
+		return tenantID == "active", nil
+	}), time.Minute)
+
+	app.GET("/data", Handle(func(c *Context[any]) error {
+		c.Success("ok", nil)
+		return nil
+	}))
+
+	get := func(tenantID string) (int, string) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/data", nil)
+		if tenantID != "" {
+			req.Header.Set(HeaderTenantID, tenantID)
+		}
+		app.ServeHTTP(w, req)
+		return w.Code, w.Body.String()
+	}
+
+	status, _ := get("active")
+	assert.Equal(t, http.StatusOK, status)
+	status, body := get("disabled")
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Contains(t, body, ErrorCodeForbidden)
+
+	// Resultados válidos e inválidos vêm do cache
+	get("active")
+	get("disabled")
+	assert.Equal(t, 2, lookups)
+
+	// Após invalidar, o tenant é consultado de novo
+	assert.NoError(t, tenants.Invalidate(context.Background(), "active"))
+	get("active")
+	assert.Equal(t, 3, lookups)
+
+	// Erro do validator falha fechado e não é cacheado
+	status, _ = get("broken")
+	assert.Equal(t, http.StatusInternalServerError, status)
+	get("broken")
+	assert.Equal(t, 5, lookups)
+
+	// Sem tenant a requisição segue sem consulta
+	status, _ = get("")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 5, lookups)
+}
+
+func TestHandle_ErrorCodeDiscriminator(t *testing.T) {
+	app := New()
+
+	app.GET("/validation", Handle(func(c *Context[any]) error {
+		return NewValidationError("Invalid JSON data", errors.New("name is required"))
+	}))
+	app.GET("/bad-request", Handle(func(c *Context[any]) error {
+		return NewBadRequestError("Malformed cursor")
+	}))
+	app.GET("/forbidden", Handle(func(c *Context[any]) error {
+		return NewForbiddenError("Forbidden")
+	}))
+	app.GET("/helper", Handle(func(c *Context[any]) error {
+		c.NotFound("User not found")
+		return nil
+	}))
+
+	tests := []struct {
+		path      string
+		status    int
+		errorCode string
+	}{
+		{"/validation", http.StatusBadRequest, ErrorCodeValidation},
+		{"/bad-request", http.StatusBadRequest, ErrorCodeBadRequest},
+		{"/forbidden", http.StatusForbidden, ErrorCodeForbidden},
+		{"/helper", http.StatusNotFound, ErrorCodeNotFound},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", tt.path, nil)
+		app.ServeHTTP(w, req)
+
+		var body map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), tt.path)
+		assert.Equal(t, tt.status, w.Code, tt.path)
+		assert.Equal(t, tt.errorCode, body[ResponseErrorCode], tt.path)
+	}
+
+	// Erros por campo da validação saem em "fields"
+	app.GET("/fields", Handle(func(c *Context[any]) error {
+		return NewValidationError("Validation failed", FieldErrors{{Field: "email", Tag: "email", Message: "email inválido"}})
+	}))
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/fields", nil)
+	app.ServeHTTP(w, req)
+	var body struct {
+		Fields FieldErrors `json:"fields"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, FieldErrors{{Field: "email", Tag: "email", Message: "email inválido"}}, body.Fields)
+}
+
+func TestContext_SuccessMasked(t *testing.T) {
+	type owner struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	type account struct {
+		Name     string `json:"name"`
+		TenantID string `json:"tenant_id"`
+		Balance  int64  `json:"balance"`
+		Owner    owner  `json:"owner"`
+	}
+
+	app := New()
+	app.GET("/accounts", Handle(func(c *Context[any]) error {
+		accounts := []account{
+			{Name: "A", TenantID: "t1", Balance: 9007199254740993, Owner: owner{ID: "u1", Name: "Ana"}},
+		}
+		c.SuccessMasked("ok", accounts, "tenant_id", "owner.id")
+		return nil
+	}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/accounts", nil)
+	app.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.NotContains(t, body, "tenant_id")
+	assert.NotContains(t, body, `"id"`)
+	assert.Contains(t, body, `"name":"Ana"`)
+	assert.Contains(t, body, "9007199254740993")
+}
+
+func TestSlowRequestLogger(t *testing.T) {
+	app := New()
+	app.Use(func(c *gin.Context) {
+		if tenantID := c.GetHeader("X-Test-Tenant"); tenantID != "" {
+			c.Set(TenantIDKey, tenantID)
+		}
+		c.Set(AuthRoleKey, c.GetHeader("X-Test-Role"))
+		c.Next()
+	})
+	store := app.AddSlowRequestLogger(5*time.Millisecond, 3)
+
+	app.GET("/fast", func(c *gin.Context) { c.Status(http.StatusOK) })
+	app.GET("/slow/:id", func(c *gin.Context) {
+		time.Sleep(10 * time.Millisecond)
+		c.Error(errors.New("mongo: connection refused at 10.0.0.5"))
+		c.Status(http.StatusAccepted)
+	})
+
+	requests := []struct{ path, tenant string }{
+		{"/fast", "tenant-a"}, {"/slow/1", "tenant-a"}, {"/slow/2", "tenant-b"}, {"/slow/3", "tenant-a"}, {"/slow/4", "tenant-b"},
+	}
+	for _, r := range requests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", r.path, nil)
+		req.Header.Set("X-Test-Tenant", r.tenant)
+		app.ServeHTTP(w, req)
+	}
+
+	// Buffer de 3: só as três últimas lentas, da mais recente para a mais antiga
+	recent := store.Recent()
+	if assert.Len(t, recent, 3) {
+		assert.Equal(t, "/slow/4", recent[0].Path)
+		assert.Equal(t, "/slow/3", recent[1].Path)
+		assert.Equal(t, "/slow/:id", recent[0].Route)
+		assert.Equal(t, http.StatusAccepted, recent[0].Status)
+		assert.GreaterOrEqual(t, recent[0].DurationMs, 10.0)
+	}
+
+	get := func(tenant, role string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", RouteSlowRequests, nil)
+		req.Header.Set("X-Test-Tenant", tenant)
+		req.Header.Set("X-Test-Role", role)
+		app.ServeHTTP(w, req)
+		return w
+	}
+
+	// Admin vê só o próprio tenant, sem os erros internos
+	w := get("tenant-a", RoleAdmin)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"/slow/3"`)
+	assert.NotContains(t, w.Body.String(), "tenant-b")
+	assert.NotContains(t, w.Body.String(), "connection refused")
+
+	// Sem a role admin ou sem tenant o endpoint é recusado
+	assert.Equal(t, http.StatusForbidden, get("tenant-a", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, get("", RoleAdmin).Code)
+	assert.False(t, app.IsPublicRoute(RouteSlowRequests))
+}
+
+func TestSetTenantExtractor_Replaces(t *testing.T) {
+	var calls []string
+	extractor := func(name string) TenantExtractor {
+		return func(c *gin.Context) TenantInfo {
+			calls = append(calls, name)
+			return TenantInfo{TenantID: name}
+		}
+	}
+
+	app := NewWithOptions(Options{TenantExtractor: extractor("first")})
+	app.SetTenantExtractor(extractor("second"))
+
+	var tenantID string
+	app.GET("/tenant", func(c *gin.Context) {
+		tenantID = GetTenantID(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	request := func() {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/tenant", nil)
+		app.ServeHTTP(w, req)
+	}
+
+	request()
+	assert.Equal(t, []string{"second"}, calls)
+	assert.Equal(t, "second", tenantID)
+
+	// nil desliga a extração
+	app.SetTenantExtractor(nil)
+	calls, tenantID = nil, ""
+	request()
+	assert.Empty(t, calls)
+	assert.Empty(t, tenantID)
+}
+
+func TestOnPanic_ReportsBeforeResponse(t *testing.T) {
+	app := New()
+	app.SetTenantExtractor(func(c *gin.Context) TenantInfo {
+		return TenantInfo{TenantID: "tenant-1"}
+	})
+
+	var recovered interface{}
+	var tenantID string
+	var stack []byte
+	app.OnPanic(func(c *gin.Context, r interface{}, s []byte) {
+		recovered, stack = r, s
+		tenantID = GetTenantID(c.Request.Context())
+		assert.False(t, c.Writer.Written())
+	})
+
+	app.GET("/boom", func(c *gin.Context) { panic("boom") })
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/boom", nil)
+	app.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), `"error_code":"INTERNAL_ERROR"`)
+	assert.Equal(t, "boom", recovered)
+	assert.Equal(t, "tenant-1", tenantID)
+	assert.Contains(t, string(stack), "TestOnPanic_ReportsBeforeResponse")
+}
+
+type stubMetricsPersister struct{}
+
+func (stubMetricsPersister) Save(MetricsSnapshot) error { return nil }
+
+func (stubMetricsPersister) GetHistory(string, time.Time, time.Time) ([]MetricsSnapshot, error) {
+	return nil, nil
+}
+
+type panickingMetricsPersister struct{ stubMetricsPersister }
+
+func (panickingMetricsPersister) Save(MetricsSnapshot) error { panic("bson encode failed") }
+
+func TestMetrics_BackgroundPanicsAreLogged(t *testing.T) {
+	var buf bytes.Buffer
+	metrics := NewMetrics()
+	metrics.SetLogger(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	metrics.runSafely("cleanup", func() { panic("boom") })
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "ERROR", entry["level"])
+	assert.Equal(t, "cleanup", entry["routine"])
+	assert.Equal(t, "boom", entry["panic"])
+	assert.Contains(t, entry["stack"], "runSafely")
+
+	buf.Reset()
+	metrics.SetPersister(panickingMetricsPersister{})
+	metrics.persistMetrics()
+
+	entry = nil
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "ERROR", entry["level"])
+	assert.Contains(t, entry["error"], "bson encode failed")
+}
+
+func TestMetricsPersistenceHealthCheck(t *testing.T) {
+	config := DefaultMetricsConfig
+	config.PersistInterval = time.Minute
+	metrics := NewMetricsWithConfig(config)
+	check := NewMetricsPersistenceHealthCheck(metrics)
+	ctx := context.Background()
+
+	// Sem persistência configurada não há o que verificar
+	assert.Equal(t, HealthStatusUp, check.Check(ctx).Status)
+
+	metrics.EnablePersistence()
+	metrics.SetPersister(stubMetricsPersister{})
+
+	for age, status := range map[time.Duration]HealthStatus{
+		30 * time.Second: HealthStatusUp,
+		3 * time.Minute:  HealthStatusWarn,
+		10 * time.Minute: HealthStatusDown,
+	} {
+		metrics.mu.Lock()
+		metrics.lastPersist = time.Now().Add(-age)
+		metrics.mu.Unlock()
+
+		assert.Equal(t, status, check.Check(ctx).Status, "age %s", age)
+	}
+}
+
+func TestResponseEnvelopeConfig(t *testing.T) {
+	app := New()
+	app.SetResponseEnvelope(ResponseEnvelopeConfig{Success: "status", Data: "payload"})
+
+	app.GET("/ok", Handle(func(c *Context[any]) error {
+		c.Success("ok", gin.H{"id": 1}, 1)
+		return nil
+	}))
+	app.GET("/fail", Handle(func(c *Context[any]) error {
+		return NewNotFoundError("missing")
+	}))
+
+	get := func(path string) map[string]interface{} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		app.ServeHTTP(w, req)
+		var body map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body
+	}
+
+	body := get("/ok")
+	assert.Equal(t, true, body["status"])
+	assert.Equal(t, "ok", body["message"])
+	assert.NotNil(t, body["payload"])
+	assert.Equal(t, float64(1), body["total"])
+	assert.NotContains(t, body, "success")
+	assert.NotContains(t, body, "data")
+
+	body = get("/fail")
+	assert.Equal(t, false, body["status"])
+	assert.Equal(t, "missing", body["message"])
+	assert.Equal(t, ErrorCodeNotFound, body["error_code"])
+}
+
+func TestContext_Accepted(t *testing.T) {
+	app := New()
+	app.POST("/exports", Handle(func(c *Context[any]) error {
+		c.Accepted("Exportação iniciada", gin.H{"job_id": "42"}, "/exports/42")
+		return nil
+	}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/exports", nil)
+	app.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, "/exports/42", w.Header().Get("Location"))
+	assert.Contains(t, w.Body.String(), `"job_id":"42"`)
+	assert.Contains(t, w.Body.String(), `"success":true`)
+}
+
+func TestRouteGroup_SetErrorHandler(t *testing.T) {
+	app := New()
+	failing := Handle(func(c *Context[any]) error {
+		return NewNotFoundError("missing")
+	})
+
+	public := app.Group("/public")
+	public.SetErrorHandler(ErrorHandlerFunc(func(c *gin.Context, err error) {
+		c.JSON(http.StatusTeapot, gin.H{"problem": err.Error()})
+	}))
+	public.GET("/item", failing)
+
+	api := app.Group("/api")
+	api.GET("/item", failing)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/public/item", nil)
+	app.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusTeapot, w.Code)
+	assert.JSONEq(t, `{"problem":"missing"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/item", nil)
+	app.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"error_code":"NOT_FOUND"`)
+}
+
+func TestValidateTenantHeader(t *testing.T) {
+	cases := []struct {
+		format   TenantIDFormat
+		tenantID string
+		want     int
+	}{
+		{TenantIDFormatUUID, "", http.StatusOK},
+		{TenantIDFormatUUID, uuid.New().String(), http.StatusOK},
+		{TenantIDFormatUUID, "not-a-uuid", http.StatusBadRequest},
+		{TenantIDFormatOpaque, "acme-corp", http.StatusOK},
+		{TenantIDFormatOpaque, "acme corp", http.StatusBadRequest},
+		{TenantIDFormatOpaque, strings.Repeat("a", 200), http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		app := New()
+		app.Use(ValidateTenantHeader(tc.format))
+		reached := false
+		app.GET("/items", func(c *gin.Context) {
+			reached = true
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/items", nil)
+		if tc.tenantID != "" {
+			req.Header.Set(HeaderTenantID, tc.tenantID)
+		}
+		app.ServeHTTP(w, req)
+
+		assert.Equal(t, tc.want, w.Code, "tenant %q", tc.tenantID)
+		assert.Equal(t, tc.want == http.StatusOK, reached)
+		if tc.want == http.StatusBadRequest {
+			assert.Contains(t, w.Body.String(), MsgInvalidTenantID)
+		}
+	}
+}
+
+func TestNewWithEngine_AndTrustedProxies(t *testing.T) {
+	engine := gin.New()
+	engine.MaxMultipartMemory = 1 << 20
+	app := NewWithEngine(engine)
+	assert.Same(t, engine, app.engine)
+	assert.Equal(t, int64(1<<20), app.engine.MaxMultipartMemory)
+
+	var clientIP string
+	app.GET("/ip", Handle(func(c *Context[any]) error {
+		clientIP = c.ClientIP()
+		c.Success("ok", nil)
+		return nil
+	}))
+
+	request := func() int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/ip", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("X-Forwarded-For", "1.2.3.4")
+		app.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Engine injetado mantém o padrão do gin: confia em qualquer proxy
+	assert.Equal(t, http.StatusOK, request())
+	assert.Equal(t, "1.2.3.4", clientIP)
+
+	assert.NoError(t, app.SetTrustedProxies(nil))
+	request()
+	assert.Equal(t, "10.0.0.1", clientIP)
+
+	assert.Error(t, app.SetTrustedProxies([]string{"not-an-ip"}))
+}
+
+func TestNew_TrustsNoProxiesByDefault(t *testing.T) {
+	app := New()
+
+	var clientIP string
+	app.GET("/ip", func(c *gin.Context) { clientIP = c.ClientIP() })
+
+	request := func() {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/ip", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("X-Forwarded-For", "1.2.3.4")
+		app.ServeHTTP(w, req)
+	}
+
+	request()
+	assert.Equal(t, "10.0.0.1", clientIP, "X-Forwarded-For must be ignored without trusted proxies")
+
+	// Atrás do load balancer configurado o header passa a valer
+	assert.NoError(t, app.SetTrustedProxies([]string{"10.0.0.0/8"}))
+	request()
+	assert.Equal(t, "1.2.3.4", clientIP)
+}
+
+func TestNewWithOptions_Mode(t *testing.T) {
+	defer gin.SetMode(gin.ReleaseMode)
+
+	NewWithOptions(Options{Mode: gin.TestMode})
+	assert.Equal(t, gin.TestMode, gin.Mode())
+
+	NewWithOptions(Options{})
+	assert.Equal(t, gin.ReleaseMode, gin.Mode())
+}
+
+func TestCacheResponse(t *testing.T) {
+	cache := NewMemoryCache(MemoryCacheConfig{CacheConfig: CacheConfig{TTL: time.Minute}, MaxSize: 100})
+	rc := NewResponseCache(cache, time.Minute, nil)
+
+	app := New()
+	app.SetTenantExtractor(DefaultTenantExtractor)
+	builds := 0
+	app.GET("/report", rc.Middleware(), Handle(func(c *Context[any]) error {
+		builds++
+		if c.Query("fail") != "" {
+			return NewBadRequestError("bad")
+		}
+		c.Success("ok", gin.H{"builds": builds})
+		return nil
+	}))
+
+	get := func(path, tenantID string, headers ...string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		req.Header.Set(HeaderTenantID, tenantID)
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		app.ServeHTTP(w, req)
+		return w
+	}
+
+	first := get("/report?b=2&a=1", "t1")
+	assert.Equal(t, "MISS", first.Header().Get(HeaderCacheStatus))
+
+	// Mesma chave (query em outra ordem) serve do cache sem rodar o handler
+	hit := get("/report?a=1&b=2", "t1")
+	assert.Equal(t, "HIT", hit.Header().Get(HeaderCacheStatus))
+	assert.Equal(t, first.Body.String(), hit.Body.String())
+	assert.Equal(t, "application/json; charset=utf-8", hit.Header().Get("Content-Type"))
+	assert.Equal(t, 1, builds)
+
+	// Outro tenant, erro e requisição autenticada não compartilham cache
+	get("/report?a=1&b=2", "t2")
+	assert.Equal(t, 2, builds)
+	get("/report?fail=1", "t1")
+	get("/report?fail=1", "t1")
+	assert.Equal(t, 4, builds)
+	get("/report?a=1&b=2", "t1", "Authorization", "Bearer x")
+	assert.Equal(t, 5, builds)
+
+	assert.NoError(t, rc.Invalidate(context.Background(), ResponseCacheKey("t1", "/report", "a=1&b=2")))
+	assert.Equal(t, "MISS", get("/report?a=1&b=2", "t1").Header().Get(HeaderCacheStatus))
+	assert.Equal(t, 6, builds)
+}
+
+func TestContext_CacheControl(t *testing.T) {
+	cases := map[string]struct {
+		apply func(c *Context[any])
+		want  string
+	}{
+		"public":    {func(c *Context[any]) { c.CacheControl(time.Hour, true) }, "public, max-age=3600"},
+		"private":   {func(c *Context[any]) { c.CacheControl(90*time.Second, false) }, "private, max-age=90"},
+		"no-cache":  {func(c *Context[any]) { c.CacheControl(0, true) }, "public, no-cache"},
+		"immutable": {func(c *Context[any]) { c.CacheImmutable(24 * time.Hour) }, "public, max-age=86400, immutable"},
+		"no-store":  {func(c *Context[any]) { c.NoStore() }, "no-store"},
+	}
+
+	for name, tc := range cases {
+		app := New()
+		app.GET("/ref", Handle(func(c *Context[any]) error {
+			tc.apply(c)
+			c.Success("ok", nil)
+			return nil
+		}))
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/ref", nil)
+		app.ServeHTTP(w, req)
+		assert.Equal(t, tc.want, w.Header().Get("Cache-Control"), name)
+	}
+}
+
+func TestVersioning_RequireMinVersion(t *testing.T) {
+	app := New()
+	app.Use(Versioning(VersioningConfig{Vendor: "myapi"}))
+
+	var version string
+	app.GET("/items", RequireMinVersion("2"), Handle(func(c *Context[any]) error {
+		version = c.APIVersion()
+		c.Success("ok", nil)
+		return nil
+	}))
+
+	request := func(header, value string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/items", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		app.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request("Accept", "application/vnd.myapi.v2.1+json")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2.1", version)
+
+	w = request(HeaderAPIVersion, "v10")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "10", version)
+
+	w = request(HeaderAPIVersion, "1.9")
+	assert.Equal(t, http.StatusUpgradeRequired, w.Code)
+	assert.Contains(t, w.Body.String(), ErrorCodeUpgradeRequired)
+
+	w = request("", "")
+	assert.Equal(t, http.StatusUpgradeRequired, w.Code)
+}
+
+func TestSuccessMapped(t *testing.T) {
+	type user struct {
+		Name     string
+		Password string
+	}
+	type userDTO struct {
+		Name string `json:"name"`
+	}
+	toDTO := func(u user) userDTO { return userDTO{Name: u.Name} }
+
+	assert.Equal(t, userDTO{Name: "Ana"}, Map(user{Name: "Ana"}, toDTO))
+	assert.Equal(t, []userDTO{}, MapSlice(nil, toDTO))
+
+	app := New()
+	app.GET("/user", Handle(func(c *Context[any]) error {
+		SuccessMapped(c, "ok", user{Name: "Ana", Password: "secret"}, toDTO)
+		return nil
+	}))
+	app.GET("/users", Handle(func(c *Context[any]) error {
+		SuccessMappedSlice(c, "ok", []user{{Name: "Ana"}, {Name: "Bia"}}, toDTO, 2)
+		return nil
+	}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/user", nil)
+	app.ServeHTTP(w, req)
+	assert.JSONEq(t, `{"success":true,"message":"ok","data":{"name":"Ana"}}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/users", nil)
+	app.ServeHTTP(w, req)
+	assert.JSONEq(t, `{"success":true,"message":"ok","data":[{"name":"Ana"},{"name":"Bia"}],"total":2}`, w.Body.String())
+}
+
+func TestBodyLogger_RedactsSensitiveFields(t *testing.T) {
+	var buf bytes.Buffer
+	app := New()
+	app.Use(BodyLogger(BodyLoggerConfig{MaxBodySize: 256, Logger: slog.New(slog.NewJSONHandler(&buf, nil))}))
+
+	var received map[string]interface{}
+	app.POST("/login", func(c *gin.Context) {
+		_ = c.ShouldBindJSON(&received)
+		c.JSON(http.StatusOK, gin.H{"access_token": "tok-123", "user": gin.H{"name": "Ana"}})
+	})
+	app.POST("/upload", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	body := `{"email":"ana@x.com","password":"hunter2","nested":{"Client_Secret":"s3"}}`
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	app.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "hunter2", received["password"], "handler still gets the full body")
+	assert.Contains(t, w.Body.String(), "tok-123")
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "/login", entry["path"])
+	assert.Contains(t, entry["request"], "ana@x.com")
+	assert.Contains(t, entry["response"], "Ana")
+	for _, secret := range []string{"hunter2", "s3\"", "tok-123"} {
+		assert.NotContains(t, buf.String(), secret)
+	}
+	assert.Contains(t, entry["request"], redactedValue)
+
+	// Bodies grandes ou não JSON só aparecem com o tamanho
+	buf.Reset()
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/upload", strings.NewReader(`{"password":"`+strings.Repeat("x", 300)+`"}`))
+	app.ServeHTTP(w, req)
+	entry = map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Contains(t, entry["request"], "truncated")
+	assert.Equal(t, "[non-JSON: 2 bytes]", entry["response"])
+	assert.NotContains(t, buf.String(), "xxxx")
+}
+
+func TestContext_BodyAfterBind(t *testing.T) {
+	type createUser struct {
+		Name  string `json:"name" validate:"required"`
+		Email string `json:"email" validate:"required,email"`
+	}
+
+	var audited interface{}
+	auditMiddleware := func(c *gin.Context) {
+		c.Next()
+		audited, _ = GetBoundBody(c)
+	}
+
+	app := New()
+	app.POST("/users", auditMiddleware, Handle(func(c *Context[createUser]) error {
+		var req createUser
+		if _, ok := c.Body(); ok {
+			t.Error("Body must be empty before BindJSON")
+		}
+		if err := c.BindJSON(&req); err != nil {
+			return err
+		}
+		body, ok := c.Body()
+		assert.True(t, ok)
+		assert.Equal(t, req, body)
+		c.Created(MsgCreatedSuccess, body)
+		return nil
+	}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/users", strings.NewReader(`{"name":"Ana","email":"ana@x.com"}`))
+	req.Header.Set("Content-Type", "application/json")
+	app.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	if body, ok := audited.(*createUser); assert.True(t, ok) {
+		assert.Equal(t, "Ana", body.Name)
+	}
+
+	// Body inválido não é exposto aos middlewares
+	audited = nil
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/users", strings.NewReader(`{"name":"Ana"}`))
+	req.Header.Set("Content-Type", "application/json")
+	app.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, audited)
+}
+
+func TestZendia_DevModeInternalErrors(t *testing.T) {
+	app := New()
+	app.GET("/fail", Handle(func(c *Context[any]) error {
+		return internalError("Failed to get entity", errors.New("connection refused"))
+	}))
+	app.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	request := func(path string) map[string]interface{} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		app.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		var body map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body
+	}
+
+	// Desligado por padrão: nada interno vaza
+	assert.False(t, app.IsDevMode())
+	for _, path := range []string{"/fail", "/panic"} {
+		body := request(path)
+		assert.NotContains(t, body, ResponseStack)
+		assert.NotContains(t, body, ResponseError)
+	}
+
+	app.SetDevMode(true)
+	body := request("/fail")
+	assert.Equal(t, "Failed to get entity", body[ResponseMessage])
+	assert.Equal(t, "connection refused", body[ResponseError])
+	assert.NotEmpty(t, body[ResponseStack])
+
+	body = request("/panic")
+	assert.Equal(t, "panic: boom", body[ResponseError])
+	assert.Contains(t, strings.Join(toStrings(body[ResponseStack]), "\n"), "TestZendia_DevModeInternalErrors")
+
+	// DefaultErrorHandler também aceita DevMode direto
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	(&DefaultErrorHandler{DevMode: true}).Handle(c, errors.New("disk full"))
+	assert.Contains(t, w.Body.String(), "disk full")
+	assert.Contains(t, w.Body.String(), `"stack"`)
+}
+
+func toStrings(value interface{}) []string {
+	items, _ := value.([]interface{})
+	out := make([]string, len(items))
+	for i, item := range items {
+		out[i], _ = item.(string)
+	}
+	return out
+}
+
+func TestI18n_NegotiatesLocale(t *testing.T) {
+	supported := []string{"pt-BR", "en"}
+	cases := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"en-US,en;q=0.9", "en"},
+		{"pt-BR", "pt-BR"},
+		{"pt", "pt-BR"},
+		{"en;q=0.5, pt-PT;q=0.8", "pt-BR"},
+		{"fr-FR, en-GB;q=0.7, *;q=0.1", "en"},
+		{"fr, *;q=0.5", "pt-BR"},
+		{"fr, en;q=0", ""},
+		{"de", ""},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, negotiateLocale(tc.header, supported), "Accept-Language %q", tc.header)
+	}
+
+	type signup struct {
+		Email string `json:"email" validate:"required,email"`
+	}
+
+	app := New()
+	app.Use(I18n(I18nConfig{Supported: supported}))
+	app.POST("/signup", Handle(func(c *Context[signup]) error {
+		var req signup
+		if err := c.BindJSON(&req); err != nil {
+			return err
+		}
+		c.Success(c.Locale(), nil)
+		return nil
+	}))
+
+	request := func(acceptLanguage string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/signup", strings.NewReader(`{"email":"x"}`))
+		req.Header.Set("Content-Type", "application/json")
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
+		app.ServeHTTP(w, req)
+		var body map[string]interface{}
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		return w, body
+	}
+
+	w, body := request("en-US,en;q=0.9,pt;q=0.5")
+	assert.Equal(t, "en", w.Header().Get("Content-Language"))
+	assert.Equal(t, "email must be a valid email", body[ResponseError])
+
+	// Sem Accept-Language compatível usa o default (o primeiro de Supported)
+	w, body = request("de-DE")
+	assert.Equal(t, "pt-BR", w.Header().Get("Content-Language"))
+	assert.Equal(t, "email deve ser um email válido", body[ResponseError])
+
+	// Idioma cadastrado via SetMessage
+	v := NewValidator()
+	v.SetMessage("es", "email", "%[1]s debe ser un email válido")
+	err := v.ValidateLocale(&signup{Email: "x"}, "es-AR")
+	assert.Equal(t, "email debe ser un email válido", errors.Unwrap(err).Error())
+	assert.Equal(t, "email é obrigatório", errors.Unwrap(v.ValidateLocale(&signup{}, "es")).Error())
+}
+
+func TestTenantSourcePrecedence(t *testing.T) {
+	const (
+		claimTenant    = "11111111-1111-1111-1111-111111111111"
+		explicitTenant = "22222222-2222-2222-2222-222222222222"
+		headerTenant   = "33333333-3333-3333-3333-333333333333"
+	)
+	// Simula o firebaseAuthMiddleware gravando o claim do token
+	claim := func(c *gin.Context) {
+		c.Set(AuthTenantIDKey, claimTenant)
+		setIdentity(c, TenantIDKey, claimTenant, TenantSourceClaim)
+		setIdentity(c, UserIDKey, "claim-user", TenantSourceClaim)
+		c.Next()
+	}
+
+	cases := []struct {
+		name       string
+		claimFirst bool // claim antes do extractor
+		claimAfter bool // claim depois do extractor
+		header     bool
+		setTenant  bool
+		wantTenant string
+		wantUser   string
+		wantSource TenantSource
+	}{
+		{name: "nothing", wantSource: TenantSourceNone},
+		{name: "header", header: true, wantTenant: headerTenant, wantUser: "header-user", wantSource: TenantSourceHeader},
+		{name: "explicit", setTenant: true, wantTenant: explicitTenant, wantSource: TenantSourceExplicit},
+		{name: "explicit over header", header: true, setTenant: true, wantTenant: explicitTenant, wantUser: "header-user", wantSource: TenantSourceExplicit},
+		{name: "claim", claimFirst: true, wantTenant: claimTenant, wantUser: "claim-user", wantSource: TenantSourceClaim},
+		{name: "claim before header", claimFirst: true, header: true, wantTenant: claimTenant, wantUser: "claim-user", wantSource: TenantSourceClaim},
+		{name: "claim after header", claimAfter: true, header: true, wantTenant: claimTenant, wantUser: "claim-user", wantSource: TenantSourceClaim},
+		{name: "claim over explicit", claimFirst: true, setTenant: true, wantTenant: claimTenant, wantUser: "claim-user", wantSource: TenantSourceClaim},
+		{name: "claim over explicit and header", claimAfter: true, header: true, setTenant: true, wantTenant: claimTenant, wantUser: "claim-user", wantSource: TenantSourceClaim},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := New()
+			if tc.claimFirst {
+				app.Use(claim)
+			}
+			app.Use(TenantMiddleware(DefaultTenantExtractor))
+			if tc.claimAfter {
+				app.Use(claim)
+			}
+
+			var ginTenant, ctxTenant, ctxUser string
+			var source TenantSource
+			app.GET("/me", Handle(func(c *Context[any]) error {
+				if tc.setTenant {
+					c.SetTenant(explicitTenant)
+				}
+				ginTenant = c.GetTenantID()
+				ctxTenant = GetTenantID(c.Request.Context())
+				ctxUser = GetUserID(c.Request.Context())
+				source = c.TenantSource()
+				c.Success("ok", nil)
+				return nil
+			}))
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/me", nil)
+			if tc.header {
+				req.Header.Set(HeaderTenantID, headerTenant)
+				req.Header.Set(HeaderUserID, "header-user")
+			}
+			app.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tc.wantTenant, ginTenant)
+			assert.Equal(t, tc.wantTenant, ctxTenant, "context.Context deve ver o mesmo tenant do gin.Context")
+			assert.Equal(t, tc.wantUser, ctxUser)
+			assert.Equal(t, tc.wantSource, source)
+		})
+	}
+}
+
+type crudItem struct {
+	ID   int    `json:"id"`
+	Name string `json:"name" validate:"required"`
+	Kind string `json:"kind"`
+}
+
+// memoryCRUDRepository repository em memória com ID int para exercitar o RegisterCRUD
+type memoryCRUDRepository struct {
+	items   map[int]*crudItem
+	nextID  int
+	filters map[string]interface{}
+	page    Pagination
+	opts    []*QueryOptions
+}
+
+func (m *memoryCRUDRepository) Create(ctx context.Context, entity *crudItem) (*crudItem, error) {
+	m.nextID++
+	entity.ID = m.nextID
+	m.items[entity.ID] = entity
+	return entity, nil
+}
+
+func (m *memoryCRUDRepository) GetByID(ctx context.Context, id int) (*crudItem, error) {
+	item, ok := m.items[id]
+	if !ok {
+		return nil, NewNotFoundError("Entity not found")
+	}
+	return item, nil
+}
+
+func (m *memoryCRUDRepository) GetAllSkipTake(ctx context.Context, filters map[string]interface{}, pagination Pagination, opts ...*QueryOptions) ([]*crudItem, int64, error) {
+	m.filters, m.page, m.opts = filters, pagination, opts
+	var items []*crudItem
+	for id := 1; id <= m.nextID; id++ {
+		if item, ok := m.items[id]; ok && (filters["kind"] == nil || filters["kind"] == item.Kind) {
+			items = append(items, item)
+		}
+	}
+	total := int64(len(items))
+	if pagination.Skip < len(items) {
+		items = items[pagination.Skip:]
+	} else {
+		items = nil
+	}
+	if len(items) > pagination.Take {
+		items = items[:pagination.Take]
+	}
+	return items, total, nil
+}
+
+func (m *memoryCRUDRepository) Update(ctx context.Context, id int, entity *crudItem) (*crudItem, error) {
+	if _, ok := m.items[id]; !ok {
+		return nil, NewNotFoundError("Entity not found")
+	}
+	entity.ID = id
+	m.items[id] = entity
+	return entity, nil
+}
+
+func (m *memoryCRUDRepository) Patch(ctx context.Context, id int, fields map[string]interface{}) (*crudItem, error) {
+	item, ok := m.items[id]
+	if !ok {
+		return nil, NewNotFoundError("Entity not found")
+	}
+	if name, ok := fields["name"].(string); ok {
+		item.Name = name
+	}
+	return item, nil
+}
+
+func (m *memoryCRUDRepository) Delete(ctx context.Context, id int) error {
+	if _, ok := m.items[id]; !ok {
+		return NewNotFoundError("Entity not found")
+	}
+	delete(m.items, id)
+	return nil
+}
+
+func TestRegisterCRUD(t *testing.T) {
+	repo := &memoryCRUDRepository{items: map[int]*crudItem{}}
+	app := New()
+	RegisterCRUD[*crudItem, int](app.Group("/items"), repo, CRUDConfig[int]{
+		ParseID:      strconv.Atoi,
+		FilterFields: []string{"kind"},
+		Pagination:   PaginationConfig{MaxTake: 50, SortFields: []string{"name"}},
+	})
+
+	request := func(method, path, body string) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		app.ServeHTTP(w, req)
+		var response map[string]interface{}
+		_ = json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+
+	code, response := request("POST", "/items", `{"name":"Ana","kind":"a"}`)
+	assert.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, MsgCreatedSuccess, response["message"])
+	assert.Equal(t, float64(1), response["data"].(map[string]interface{})["id"])
+	request("POST", "/items", `{"name":"Bia","kind":"b"}`)
+	request("POST", "/items", `{"name":"Caio","kind":"a"}`)
+
+	// Validator e body inválido
+	code, _ = request("POST", "/items", `{"kind":"a"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = request("POST", "/items", `null`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, response = request("GET", "/items?kind=a&skip=1&take=1&sort=-name", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(2), response["total"])
+	assert.Len(t, response["data"], 1)
+	assert.Equal(t, map[string]interface{}{"kind": "a"}, repo.filters)
+	assert.Equal(t, Pagination{Skip: 1, Take: 1}, repo.page)
+	if assert.Len(t, repo.opts, 1) {
+		assert.Equal(t, Order{By: "name", At: -1}, repo.opts[0].Order)
+	}
+
+	// Filtro, sort e take fora da configuração
+	for _, query := range []string{"?role=admin", "?kind=a&kind=b", "?sort=kind", "?take=51"} {
+		code, _ = request("GET", "/items"+query, "")
+		assert.Equal(t, http.StatusBadRequest, code, query)
+	}
+
+	code, response = request("GET", "/items/2", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "Bia", response["data"].(map[string]interface{})["name"])
+	code, _ = request("GET", "/items/x", "")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = request("GET", "/items/99", "")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	code, response = request("PUT", "/items/2", `{"name":"Bianca","kind":"b"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "Bianca", repo.items[2].Name)
+	code, _ = request("PUT", "/items/2", `{"kind":"b"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = request("PATCH", "/items/3", `{"name":"Caíque"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "Caíque", repo.items[3].Name)
+	code, _ = request("PATCH", "/items/3", `{}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	// PATCH valida a entidade resultante e só aceita campos json de T
+	for _, body := range []string{`{"name":""}`, `{"nome":"x"}`, `{"name":5}`} {
+		code, _ = request("PATCH", "/items/3", body)
+		assert.Equal(t, http.StatusBadRequest, code, body)
+	}
+	assert.Equal(t, "Caíque", repo.items[3].Name)
+	code, _ = request("PATCH", "/items/99", `{"name":"x"}`)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	code, _ = request("DELETE", "/items/1", "")
+	assert.Equal(t, http.StatusNoContent, code)
+	code, _ = request("DELETE", "/items/1", "")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	// Só as rotas de Verbs são registradas
+	readOnly := New()
+	RegisterCRUD[*crudItem, int](readOnly.Group("/items"), repo, CRUDConfig[int]{Verbs: CRUDReadOnly, ParseID: strconv.Atoi})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/items", strings.NewReader(`{"name":"x"}`))
+	readOnly.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Repository Mongo satisfaz o contrato com ID uuid.UUID e usa o parser padrão
+	var _ CRUDRepository[*testEntity, uuid.UUID] = (*Repository[*testEntity])(nil)
+	assert.Panics(t, func() { RegisterCRUD[*crudItem, int](New().Group("/x"), repo, CRUDConfig[int]{}) })
+}
+
+func TestContext_RespondNegotiatesAccept(t *testing.T) {
+	type respondItem struct {
+		ID      int       `json:"id" xml:"id"`
+		Name    string    `json:"name" xml:"name"`
+		Created AuditInfo `json:"created" xml:"-"`
+	}
+
+	app := New()
+	app.GET("/items", Handle(func(c *Context[any]) error {
+		c.Respond(http.StatusOK, []respondItem{{ID: 1, Name: "Ana"}, {ID: 2, Name: "=SUM(A1)"}})
+		return nil
+	}))
+
+	request := func(accept string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/items", nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		app.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, accept := range []string{"", "application/json", "*/*", "text/html", "application/xml;q=0.5, application/json"} {
+		w := request(accept)
+		assert.Contains(t, w.Header().Get("Content-Type"), MediaTypeJSON, accept)
+		var body map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), accept)
+		assert.Equal(t, true, body["success"])
+		assert.Len(t, body["data"], 2)
+	}
+
+	for _, accept := range []string{"application/xml", "text/xml", "text/html, application/xml;q=0.9"} {
+		w := request(accept)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), MediaTypeXML, accept)
+		assert.Contains(t, w.Body.String(), "<response><success>true</success><message>"+MsgSuccess+"</message>"+
+			"<data><item><id>1</id><name>Ana</name></item><item><id>2</id><name>=SUM(A1)</name></item></data></response>", accept)
+	}
+
+	w := request("text/csv")
+	assert.Contains(t, w.Header().Get("Content-Type"), MediaTypeCSV)
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	if assert.Len(t, lines, 3) {
+		assert.Equal(t, "created.active,created.by_id,created.by_name,created.set_at,id,name", lines[0])
+		assert.True(t, strings.HasSuffix(lines[1], ",1,Ana"), lines[1])
+		assert.True(t, strings.HasSuffix(lines[2], ",2,'=SUM(A1)"), lines[2])
+	}
+
+	assert.Equal(t, MediaTypeCSV, negotiateMediaType("text/*"))
+	assert.Equal(t, MediaTypeJSON, negotiateMediaType("application/*"))
+	assert.Equal(t, MediaTypeXML, negotiateMediaType("application/json;q=0, */*;q=0.1"))
+}
+
+func TestSetupFirebaseAuth_RejectsSecondSetup(t *testing.T) {
+	app := New()
+	app.SetupFirebaseAuth(FirebaseAuthConfig{PublicRoutes: []string{"/public"}})
+
+	assert.PanicsWithValue(t, "zendia: auth already configured: SetupFirebaseAuth can be called only once per instance", func() {
+		app.SetupFirebaseAuth(FirebaseAuthConfig{})
+	})
+	// A configuração original continua valendo
+	assert.Equal(t, []string{"/public"}, app.firebaseAuthConfig.PublicRoutes)
+}
+
+func TestPublicRoutes_SharedAcrossAuthModes(t *testing.T) {
+	paths := map[string]bool{
+		"/health":          true,
+		"/docs/index.html": true,
+		"/swagger/doc":     true,
+		"/public/metrics":  true,
+		"/public":          true,
+		"/auth/login":      true,
+		"/api/v1/users":    false,
+		"/publications":    false,
+		"/public-keys":     false,
+		"/healthz":         false,
+	}
+
+	// Firebase: rotas privadas sem token respondem 401 antes de chamar o client
+	firebase := New()
+	firebase.SetupFirebaseAuth(FirebaseAuthConfig{PublicRoutes: []string{"/auth"}})
+
+	// Auth próprio consultando a mesma lista
+	custom := New()
+	custom.PublicRoutes("/auth")
+	custom.Use(func(c *gin.Context) {
+		if !custom.IsPublicRoute(c.Request.URL.Path) && c.GetHeader("Authorization") == "" {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Next()
+	})
+
+	for _, app := range []*Zendia{firebase, custom} {
+		app.engine.NoRoute(func(c *gin.Context) { c.Status(http.StatusOK) })
+	}
+
+	for path, public := range paths {
+		assert.Equal(t, public, firebase.IsPublicRoute(path), path)
+		assert.Equal(t, public, custom.IsPublicRoute(path), path)
+
+		for name, app := range map[string]*Zendia{"firebase": firebase, "custom": custom} {
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+			if public {
+				assert.Equal(t, http.StatusOK, w.Code, name+" "+path)
+			} else {
+				assert.Equal(t, http.StatusUnauthorized, w.Code, name+" "+path)
+			}
+		}
+	}
+
+	// Prefixo vazio não libera tudo
+	custom.PublicRoutes("")
+	assert.False(t, custom.IsPublicRoute("/api/v1/users"))
+}
+
+func TestRateLimit_PerRouteStrictestWins(t *testing.T) {
+	app := New()
+	api := app.Group("/api")
+	api.Use(RateLimit(RateLimitConfig{Requests: 5, Window: time.Minute}))
+	api.GET("/users", func(c *gin.Context) { c.Status(http.StatusOK) })
+	api.POST("/auth/login",
+		RateLimit(RateLimitConfig{Requests: 2, Window: time.Minute, KeyFunc: RateLimitKeyByIPAndField("email")}),
+		func(c *gin.Context) {
+			var body struct {
+				Email string `json:"email"`
+			}
+			// O body segue inteiro para o handler
+			if err := c.ShouldBindJSON(&body); err != nil || body.Email == "" {
+				c.Status(http.StatusBadRequest)
+				return
+			}
+			c.Status(http.StatusOK)
+		})
+
+	login := func(email string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/auth/login", strings.NewReader(`{"email":"`+email+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		app.ServeHTTP(w, req)
+		return w
+	}
+	list := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/users", nil))
+		return w
+	}
+
+	// Login: 2 tentativas por IP + email
+	assert.Equal(t, http.StatusOK, login("ana@example.com").Code)
+	assert.Equal(t, http.StatusOK, login("ANA@example.com").Code)
+	w := login("ana@example.com")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Contains(t, w.Body.String(), ErrorCodeTooManyRequests)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+	assert.Equal(t, "0", w.Header().Get(HeaderRateLimitRemaining))
+
+	// Outra conta do mesmo IP tem contador próprio no login...
+	assert.Equal(t, http.StatusOK, login("bia@example.com").Code)
+
+	// ...e a rota geral, com limite maior, ainda atende o mesmo cliente
+	w = list()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "5", w.Header().Get(HeaderRateLimitLimit))
+
+	// O limite do grupo vale também para o login: 4 logins + 1 listagem esgotam os 5
+	assert.Equal(t, http.StatusTooManyRequests, list().Code)
+	assert.Equal(t, http.StatusTooManyRequests, login("caio@example.com").Code)
+}
+
+func TestRateLimiter_WindowResets(t *testing.T) {
+	limiter := newRateLimiter(1, time.Minute)
+	now := time.Now()
+
+	_, _, allowed := limiter.allow("client", now)
+	assert.True(t, allowed)
+	_, retryAfter, allowed := limiter.allow("client", now.Add(20*time.Second))
+	assert.False(t, allowed)
+	assert.Equal(t, 40*time.Second, retryAfter)
+
+	_, _, allowed = limiter.allow("client", now.Add(time.Minute))
+	assert.True(t, allowed)
+	assert.Len(t, limiter.windows, 1)
+}
+
+func TestContext_ImpersonateTenant(t *testing.T) {
+	app := New()
+	app.Use(func(c *gin.Context) {
+		// Simula o token verificado: tenant e role vêm dos claims
+		setIdentity(c, TenantIDKey, "tenant-a", TenantSourceClaim)
+		setIdentity(c, UserIDKey, "user-1", TenantSourceClaim)
+		c.Set(AuthRoleKey, c.GetHeader("X-Test-Role"))
+		c.Next()
+	})
+	app.GET("/support/:tenant", Handle(func(c *Context[any]) error {
+		if err := c.ImpersonateTenant(c.Param("tenant"), c.Query("reason")); err != nil {
+			return err
+		}
+		c.SetTenant("tenant-c") // não desfaz a troca
+		ctx := c.Request.Context()
+		c.Success("ok", gin.H{
+			"tenant": GetTenantID(ctx),
+			"source": c.TenantSource().String(),
+			"audit":  GetImpersonation(ctx),
+		})
+		return nil
+	}))
+	app.GET("/me", Handle(func(c *Context[any]) error {
+		c.Success("ok", gin.H{"tenant": GetTenantID(c.Request.Context()), "audit": GetImpersonation(c.Request.Context())})
+		return nil
+	}))
+
+	get := func(path, role string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-Test-Role", role)
+		app.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/support/tenant-b?reason=ticket-42", RoleAdmin)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Data struct {
+			Tenant string        `json:"tenant"`
+			Source string        `json:"source"`
+			Audit  Impersonation `json:"audit"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "tenant-b", body.Data.Tenant)
+	assert.Equal(t, "impersonation", body.Data.Source)
+	assert.Equal(t, "tenant-a", body.Data.Audit.FromTenantID)
+	assert.Equal(t, "user-1", body.Data.Audit.UserID)
+	assert.Equal(t, "ticket-42", body.Data.Audit.Reason)
+	assert.Equal(t, "tenant-b", w.Header().Get(HeaderTenantID))
+
+	// Sem admin: Forbidden; sem motivo: BadRequest
+	w = get("/support/tenant-b?reason=ticket-42", "user")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), ErrorCodeForbidden)
+	assert.Equal(t, http.StatusBadRequest, get("/support/tenant-b", RoleAdmin).Code)
+
+	// A troca não passa para a próxima requisição
+	w = get("/me", RoleAdmin)
+	assert.Contains(t, w.Body.String(), `"tenant":"tenant-a"`)
+	assert.Contains(t, w.Body.String(), `"audit":null`)
+}
+
+func TestRegisterCRUD_SelectFields(t *testing.T) {
+	repo := &memoryCRUDRepository{items: map[int]*crudItem{
+		1: {ID: 1, Name: "Ana", Kind: "a"},
+		2: {ID: 2, Name: "Bia", Kind: "b"},
+	}, nextID: 2}
+	app := New()
+	RegisterCRUD[*crudItem, int](app.Group("/items"), repo, CRUDConfig[int]{
+		ParseID:      strconv.Atoi,
+		SelectFields: []string{"id", "name"},
+	})
+
+	request := func(path string) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		var response map[string]interface{}
+		_ = json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+
+	code, response := request("/items?fields=id,name")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"id": float64(1), "name": "Ana"},
+		map[string]interface{}{"id": float64(2), "name": "Bia"},
+	}, response["data"])
+	if assert.Len(t, repo.opts, 1) {
+		assert.Equal(t, map[string]interface{}{"id": 1, "name": 1}, repo.opts[0].Projection)
+	}
+
+	code, response = request("/items/2?fields=name")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, map[string]interface{}{"name": "Bia"}, response["data"])
+
+	// Sem ?fields= a representação é completa
+	_, response = request("/items/1")
+	assert.Equal(t, "a", response["data"].(map[string]interface{})["kind"])
+
+	// Campo fora da allowlist ou inválido
+	for _, path := range []string{"/items?fields=kind", "/items/1?fields=id,kind", "/items?fields=$where", "/items?fields=,"} {
+		code, _ = request(path)
+		assert.Equal(t, http.StatusBadRequest, code, path)
+	}
+}
+
+func TestContext_SuccessFieldsAppliesMask(t *testing.T) {
+	type account struct {
+		ID       int    `json:"id"`
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+	app := New()
+	app.GET("/accounts", Handle(func(c *Context[any]) error {
+		// password na allowlist por engano: o mask ainda o remove
+		fields, err := c.Fields("id", "email", "password")
+		if err != nil {
+			return err
+		}
+		c.SuccessFields("ok", []account{{1, "ana@example.com", "secret"}}, fields, "password")
+		return nil
+	}))
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/accounts?fields=id,password", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"data":[{"id":1}]`)
+	assert.NotContains(t, w.Body.String(), "secret")
+}